@@ -0,0 +1,506 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+// The API is described in docs/openapi.yaml. Changes to it under this prefix
+// keep the existing paths and fields working.
+const Prefix = "/api/v1"
+
+// Sessions not used for this long are dropped.
+const sessionLifetime = time.Hour
+
+// The seeds picked by the server stay within the integers that JSON numbers
+// keep exactly.
+const maxPickedSeed = 1<<53 - 1
+
+// Serves the drills as a JSON API, for other programs to run drill sessions
+// with. The sessions are kept in memory, and the drills themselves are
+// sessions of the drill package.
+type Server struct {
+	words  []string
+	quotes []string
+
+	mu       sync.Mutex
+	sessions map[string]*apiSession
+}
+
+type apiSession struct {
+	id       string
+	mode     drillMode
+	speed    float64
+	seed     int64
+	drills   *drill.Session
+	lastUsed time.Time
+}
+
+func NewServer(words []string, quotes []string) *Server {
+	return &Server{
+		words:    words,
+		quotes:   quotes,
+		sessions: map[string]*apiSession{},
+	}
+}
+
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+Prefix+"/sessions", server.createSession)
+	mux.HandleFunc("GET "+Prefix+"/sessions/{id}", server.getSession)
+	mux.HandleFunc("DELETE "+Prefix+"/sessions/{id}", server.deleteSession)
+	mux.HandleFunc("GET "+Prefix+"/sessions/{id}/next", server.next)
+	mux.HandleFunc("GET "+Prefix+"/sessions/{id}/next/audio", server.nextAudio)
+	mux.HandleFunc("GET "+Prefix+"/sessions/{id}/items/{index}/audio", server.itemAudio)
+	mux.HandleFunc("POST "+Prefix+"/sessions/{id}/replay", server.replay)
+	mux.HandleFunc("POST "+Prefix+"/sessions/{id}/answers", server.answer)
+	mux.HandleFunc("GET "+Prefix+"/sessions/{id}/results", server.results)
+
+	mux.HandleFunc(Prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Error: there is no %v %v.", r.Method, r.URL.Path))
+	})
+
+	return mux
+}
+
+type sessionRequest struct {
+	Mode       string   `json:"mode"`
+	Pool       poolJSON `json:"pool"`
+	Iterations int      `json:"iterations"`
+	Speed      float64  `json:"speed"`
+	Seed       int64    `json:"seed"`
+}
+
+// What the items are picked from. Which of these are used depends on the
+// mode, like the flags of the commands.
+type poolJSON struct {
+	Level      int    `json:"level"`
+	Letters    string `json:"letters"`
+	WordLength int    `json:"wordLength"`
+	MinLength  int    `json:"minLength"`
+	MaxLength  int    `json:"maxLength"`
+}
+
+type sessionJSON struct {
+	ID      string  `json:"id"`
+	Mode    string  `json:"mode"`
+	Speed   float64 `json:"speed"`
+	Seed    int64   `json:"seed"`
+	Current int     `json:"current"`
+	Total   int     `json:"total"`
+	Done    bool    `json:"done"`
+
+	Next *nextJSON `json:"next,omitempty"`
+}
+
+type nextJSON struct {
+	Index int    `json:"index"`
+	Audio string `json:"audio"`
+
+	// The text to encode, for the encode drills only, as the others are to
+	// be listened to.
+	Prompt string `json:"prompt,omitempty"`
+}
+
+type answerJSON struct {
+	Text      string `json:"text"`
+	MorseCode string `json:"morseCode"`
+	Given     string `json:"given"`
+	Correct   bool   `json:"correct"`
+	LatencyMs int64  `json:"latencyMs"`
+	Replays   int    `json:"replays"`
+
+	Score *scoreJSON `json:"score,omitempty"`
+}
+
+// The characters right in a word or a quote, and the words right in a decoded
+// quote.
+type scoreJSON struct {
+	Correct int `json:"correct"`
+	Total   int `json:"total"`
+
+	Words *wordScoreJSON `json:"words,omitempty"`
+}
+
+type wordScoreJSON struct {
+	Correct int `json:"correct"`
+	Total   int `json:"total"`
+}
+
+type resultsJSON struct {
+	Done    bool         `json:"done"`
+	Score   int          `json:"score"`
+	Total   int          `json:"total"`
+	Answers []answerJSON `json:"answers"`
+	Stats   statsJSON    `json:"stats"`
+}
+
+type statsJSON struct {
+	Answered         int             `json:"answered"`
+	Correct          int             `json:"correct"`
+	Accuracy         float64         `json:"accuracy"`
+	AverageLatencyMs int64           `json:"averageLatencyMs"`
+	Replays          int             `json:"replays"`
+	Items            []itemStatsJSON `json:"items"`
+}
+
+type itemStatsJSON struct {
+	Text             string `json:"text"`
+	Answered         int    `json:"answered"`
+	Correct          int    `json:"correct"`
+	AverageLatencyMs int64  `json:"averageLatencyMs"`
+}
+
+type answerRequest struct {
+	Answer string `json:"answer"`
+}
+
+type answerResponse struct {
+	Answer  answerJSON  `json:"answer"`
+	Session sessionJSON `json:"session"`
+}
+
+func (server *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	request := sessionRequest{}
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session, err := server.newSession(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for id, old := range server.sessions {
+		if time.Since(old.lastUsed) > sessionLifetime {
+			delete(server.sessions, id)
+		}
+	}
+
+	server.sessions[session.id] = session
+
+	w.Header().Set("Location", Prefix+"/sessions/"+session.id)
+	writeJSON(w, http.StatusCreated, session.json())
+}
+
+func (server *Server) getSession(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, session.json())
+}
+
+func (server *Server) deleteSession(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	delete(server.sessions, session.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (server *Server) next(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	next := session.next()
+	if next == nil {
+		writeError(w, http.StatusConflict, fmt.Errorf("Error: the session is already done."))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, next)
+}
+
+// Counts a replay of the current item, which the client plays by itself.
+func (server *Server) replay(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	if _, ok := session.drills.Replay(); !ok {
+		writeError(w, http.StatusConflict, fmt.Errorf("Error: the session is already done."))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, session.json())
+}
+
+func (server *Server) answer(w http.ResponseWriter, r *http.Request) {
+	request := answerRequest{}
+	if err := readJSON(r, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	if session.drills.Done() {
+		writeError(w, http.StatusConflict, fmt.Errorf("Error: the session is already done."))
+		return
+	}
+
+	answer := session.drills.Answer(session.mode.normalizeAnswer(request.Answer))
+	writeJSON(w, http.StatusOK, answerResponse{
+		Answer:  session.mode.answerJSON(answer),
+		Session: session.json(),
+	})
+}
+
+// The answers so far, and all of them when the session is done.
+func (server *Server) results(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	session, ok := server.findSession(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, session.results())
+}
+
+func (server *Server) nextAudio(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	session, ok := server.findSession(w, r)
+	if !ok {
+		server.mu.Unlock()
+		return
+	}
+
+	item, ok := session.drills.Next()
+	speed := session.speed
+	server.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusConflict, fmt.Errorf("Error: the session is already done."))
+		return
+	}
+
+	// The next item changes with every answer.
+	w.Header().Set("Cache-Control", "no-store")
+	writeAudio(w, item.MorseCode, speed)
+}
+
+// Only up to the current item, the rest being unknown until then.
+func (server *Server) itemAudio(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	session, ok := server.findSession(w, r)
+	if !ok {
+		server.mu.Unlock()
+		return
+	}
+
+	items := session.drills.Items()
+	current, _ := session.drills.Progress()
+	speed := session.speed
+	server.mu.Unlock()
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index > current || index >= len(items) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Error: there is no item %v yet.", r.PathValue("index")))
+		return
+	}
+
+	// The sound of an item never changes.
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	writeAudio(w, items[index].MorseCode, speed)
+}
+
+func writeAudio(w http.ResponseWriter, morseCode string, speed float64) {
+	sound, err := commons.MorseCodeWAV(morseCode, speed)
+	if err != nil {
+		w.Header().Del("Cache-Control")
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Header().Set("Content-Length", strconv.Itoa(len(sound)))
+	w.Write(sound)
+}
+
+// Writes the error response itself if the session is not found. Called with
+// the lock held.
+func (server *Server) findSession(w http.ResponseWriter, r *http.Request) (*apiSession, bool) {
+	session, ok := server.sessions[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Error: there is no session %v.", r.PathValue("id")))
+		return nil, false
+	}
+
+	session.lastUsed = time.Now()
+	return session, true
+}
+
+func (server *Server) newSession(request sessionRequest) (*apiSession, error) {
+	mode, ok := drillModes[strings.ToLower(request.Mode)]
+	if !ok {
+		return nil, fmt.Errorf("Error: unknown mode %q, it should be one of %v.", request.Mode, modeNames)
+	}
+
+	speed := request.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	if speed < 0.25 || speed > 4 {
+		return nil, fmt.Errorf("Error: the speed should be from 0.25 to 4.")
+	}
+
+	seed := request.Seed
+	if seed == 0 {
+		seed = 1 + mathrand.Int63n(maxPickedSeed)
+	}
+
+	random, seed := commons.NewRandom(seed)
+	items, err := mode.generate(server, random, request)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	return &apiSession{
+		id:       hex.EncodeToString(id),
+		mode:     mode,
+		speed:    speed,
+		seed:     seed,
+		drills:   drill.NewSession(items, mode.check),
+		lastUsed: time.Now(),
+	}, nil
+}
+
+func (session *apiSession) json() sessionJSON {
+	current, total := session.drills.Progress()
+	return sessionJSON{
+		ID:      session.id,
+		Mode:    session.mode.name,
+		Speed:   session.speed,
+		Seed:    session.seed,
+		Current: current,
+		Total:   total,
+		Done:    session.drills.Done(),
+		Next:    session.next(),
+	}
+}
+
+// Nil when the session is done.
+func (session *apiSession) next() *nextJSON {
+	item, ok := session.drills.Next()
+	if !ok {
+		return nil
+	}
+
+	current, _ := session.drills.Progress()
+	next := &nextJSON{
+		Index: current,
+		Audio: fmt.Sprintf("%v/sessions/%v/items/%v/audio", Prefix, session.id, current),
+	}
+
+	if session.mode.encodes {
+		next.Prompt = item.Text
+	}
+
+	return next
+}
+
+func (session *apiSession) results() resultsJSON {
+	results := session.drills.Results()
+	stats := results.Stats()
+
+	json := resultsJSON{
+		Done:    session.drills.Done(),
+		Score:   results.Score,
+		Total:   results.Total(),
+		Answers: []answerJSON{},
+		Stats: statsJSON{
+			Answered:         stats.Answered,
+			Correct:          stats.Correct,
+			Accuracy:         stats.Accuracy(),
+			AverageLatencyMs: stats.AverageLatency.Milliseconds(),
+			Replays:          stats.Replays,
+			Items:            []itemStatsJSON{},
+		},
+	}
+
+	for _, answer := range results.Answers {
+		json.Answers = append(json.Answers, session.mode.answerJSON(answer))
+	}
+
+	for _, item := range stats.Items {
+		json.Stats.Items = append(json.Stats.Items, itemStatsJSON{
+			Text:             item.Text,
+			Answered:         item.Answered,
+			Correct:          item.Correct,
+			AverageLatencyMs: item.AverageLatency.Milliseconds(),
+		})
+	}
+
+	return json
+}
+
+// Unknown fields are errors, so that a misspelled field is not silently
+// ignored.
+func readJSON(r *http.Request, value any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(value); err != nil {
+		return fmt.Errorf("Error reading the request: %v", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
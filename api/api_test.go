@@ -0,0 +1,540 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"gopkg.in/yaml.v3"
+)
+
+var testWords = []string{"paris", "morse", "code", "radio", "key", "antenna", "signal"}
+var testQuotes = []string{"Hello world.", "The quick brown fox."}
+
+// Runs requests against a server, checking each response against the
+// OpenAPI document: its status has to be documented for the path, and its body
+// has to follow the schema of that status.
+type apiTest struct {
+	t      *testing.T
+	server *httptest.Server
+	spec   map[string]any
+}
+
+func newAPITest(t *testing.T) *apiTest {
+	file, err := os.ReadFile("../docs/openapi.yaml")
+	if err != nil {
+		t.Fatalf("reading the OpenAPI document: %v", err)
+	}
+
+	spec := map[string]any{}
+	if err := yaml.Unmarshal(file, &spec); err != nil {
+		t.Fatalf("parsing the OpenAPI document: %v", err)
+	}
+
+	server := httptest.NewServer(NewServer(testWords, testQuotes).Handler())
+	t.Cleanup(server.Close)
+
+	return &apiTest{t: t, server: server, spec: spec}
+}
+
+type apiResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (response apiResponse) decode(t *testing.T, value any) {
+	t.Helper()
+
+	if err := json.Unmarshal(response.body, value); err != nil {
+		t.Fatalf("decoding %s: %v", response.body, err)
+	}
+}
+
+// The path template is the one of the document, like /sessions/{id}; an empty
+// one skips the checks against the document.
+func (test *apiTest) do(method string, template string, path string, body string) apiResponse {
+	test.t.Helper()
+
+	request, err := http.NewRequest(method, test.server.URL+Prefix+path, strings.NewReader(body))
+	if err != nil {
+		test.t.Fatal(err)
+	}
+
+	if len(body) != 0 {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := http.DefaultClient.Do(request)
+	if err != nil {
+		test.t.Fatal(err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		test.t.Fatal(err)
+	}
+
+	response := apiResponse{status: httpResponse.StatusCode, header: httpResponse.Header, body: responseBody}
+	if len(template) != 0 {
+		test.checkResponse(method, template, response)
+	}
+
+	return response
+}
+
+func (test *apiTest) checkResponse(method string, template string, response apiResponse) {
+	test.t.Helper()
+	where := fmt.Sprintf("%v %v (%v)", method, template, response.status)
+
+	operation, ok := lookup(test.spec, "paths", template, strings.ToLower(method)).(map[string]any)
+	if !ok {
+		test.t.Errorf("%v: the path is not in the OpenAPI document", where)
+		return
+	}
+
+	documented, ok := lookup(operation, "responses", strconv.Itoa(response.status)).(map[string]any)
+	if !ok {
+		test.t.Errorf("%v: the status is not in the OpenAPI document", where)
+		return
+	}
+
+	documented = test.resolve(documented)
+	if len(response.body) == 0 {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.header.Get("Content-Type"))
+	schema, ok := lookup(documented, "content", mediaType, "schema").(map[string]any)
+	if !ok {
+		test.t.Errorf("%v: the content type %q is not in the OpenAPI document", where, mediaType)
+		return
+	}
+
+	if mediaType != "application/json" {
+		return
+	}
+
+	value := any(nil)
+	if err := json.Unmarshal(response.body, &value); err != nil {
+		test.t.Errorf("%v: the body is not JSON: %v", where, err)
+		return
+	}
+
+	for _, problem := range test.validate(schema, value, "body") {
+		test.t.Errorf("%v: %v", where, problem)
+	}
+}
+
+func lookup(value any, keys ...string) any {
+	for _, key := range keys {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		value = object[key]
+	}
+
+	return value
+}
+
+// Follows the $ref of the object, within the document.
+func (test *apiTest) resolve(object map[string]any) map[string]any {
+	ref, ok := object["$ref"].(string)
+	if !ok {
+		return object
+	}
+
+	keys := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	resolved, ok := lookup(test.spec, keys...).(map[string]any)
+	if !ok {
+		test.t.Fatalf("the OpenAPI document has no %v", ref)
+	}
+
+	return test.resolve(resolved)
+}
+
+// Checks the value against the parts of JSON Schema the document uses: the
+// types, the required and known properties, the items and the enums.
+func (test *apiTest) validate(schema map[string]any, value any, at string) []string {
+	schema = test.resolve(schema)
+	problems := []string{}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%v should be an object, not %v", at, value)}
+		}
+
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%v misses %v", at, name))
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range object {
+			propertySchema, ok := properties[name].(map[string]any)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%v has %v, which is not in the schema", at, name))
+				continue
+			}
+
+			problems = append(problems, test.validate(propertySchema, property, at+"."+name)...)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%v should be an array, not %v", at, value)}
+		}
+
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range array {
+			problems = append(problems, test.validate(items, item, fmt.Sprintf("%v[%v]", at, i))...)
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			problems = append(problems, fmt.Sprintf("%v should be an integer, not %v", at, value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, fmt.Sprintf("%v should be a number, not %v", at, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%v should be a boolean, not %v", at, value))
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%v should be a string, not %v", at, value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		problems = append(problems, fmt.Sprintf("%v is %v, which is not one of %v", at, value, enum))
+	}
+
+	return problems
+}
+
+func (test *apiTest) createSession(body string) sessionJSON {
+	test.t.Helper()
+
+	response := test.do("POST", "/sessions", "/sessions", body)
+	if response.status != http.StatusCreated {
+		test.t.Fatalf("creating %v = %v %s, want 201", body, response.status, response.body)
+	}
+
+	session := sessionJSON{}
+	response.decode(test.t, &session)
+
+	if location := response.header.Get("Location"); location != Prefix+"/sessions/"+session.ID {
+		test.t.Errorf("Location = %q, want the path of session %v", location, session.ID)
+	}
+
+	return session
+}
+
+func (test *apiTest) answer(id string, given string) answerResponse {
+	test.t.Helper()
+
+	body, _ := json.Marshal(answerRequest{Answer: given})
+	response := test.do("POST", "/sessions/{id}/answers", "/sessions/"+id+"/answers", string(body))
+	if response.status != http.StatusOK {
+		test.t.Fatalf("answering %q = %v %s, want 200", given, response.status, response.body)
+	}
+
+	answer := answerResponse{}
+	response.decode(test.t, &answer)
+
+	return answer
+}
+
+func (test *apiTest) results(id string) resultsJSON {
+	test.t.Helper()
+
+	response := test.do("GET", "/sessions/{id}/results", "/sessions/"+id+"/results", "")
+	if response.status != http.StatusOK {
+		test.t.Fatalf("getting the results = %v %s, want 200", response.status, response.body)
+	}
+
+	results := resultsJSON{}
+	response.decode(test.t, &results)
+
+	return results
+}
+
+func TestDecodeSession(t *testing.T) {
+	test := newAPITest(t)
+
+	// With one letter, the items are known without listening to them.
+	session := test.createSession(`{"mode": "decode-letters", "pool": {"letters": "e"}, "iterations": 3}`)
+	if session.Mode != "decode-letters" || session.Total != 3 || session.Current != 0 || session.Done {
+		t.Errorf("session = %+v, want 3 letters to decode", session)
+	}
+
+	if session.Seed == 0 || session.Speed != 1 {
+		t.Errorf("seed, speed = %v, %v, want a picked seed and 1", session.Seed, session.Speed)
+	}
+
+	response := test.do("GET", "/sessions/{id}/next", "/sessions/"+session.ID+"/next", "")
+	next := nextJSON{}
+	response.decode(t, &next)
+
+	wantAudio := fmt.Sprintf("%v/sessions/%v/items/0/audio", Prefix, session.ID)
+	if next.Index != 0 || next.Audio != wantAudio || len(next.Prompt) != 0 {
+		t.Errorf("next = %+v, want item 0 without a prompt", next)
+	}
+
+	test.do("POST", "/sessions/{id}/replay", "/sessions/"+session.ID+"/replay", "")
+
+	first := test.answer(session.ID, "E")
+	if !first.Answer.Correct || first.Answer.Text != "e" || first.Answer.MorseCode != "." || first.Answer.Replays != 1 {
+		t.Errorf("answer = %+v, want a correct answer to e, replayed once", first.Answer)
+	}
+
+	if first.Session.Current != 1 || first.Session.Next == nil || first.Session.Next.Index != 1 {
+		t.Errorf("session after the answer = %+v, want it at item 1", first.Session)
+	}
+
+	if second := test.answer(session.ID, "t"); second.Answer.Correct {
+		t.Errorf("answer t = %+v, want it wrong", second.Answer)
+	}
+
+	last := test.answer(session.ID, " e ")
+	if !last.Answer.Correct || !last.Session.Done || last.Session.Next != nil {
+		t.Errorf("last answer = %+v, want it correct and the session done", last)
+	}
+
+	results := test.results(session.ID)
+	if !results.Done || results.Score != 2 || results.Total != 3 || len(results.Answers) != 3 {
+		t.Errorf("results = %+v, want 2 of 3 right", results)
+	}
+
+	if results.Stats.Answered != 3 || results.Stats.Correct != 2 || results.Stats.Replays != 1 {
+		t.Errorf("stats = %+v, want 3 answered, 2 right and 1 replay", results.Stats)
+	}
+}
+
+func TestEncodeSession(t *testing.T) {
+	test := newAPITest(t)
+
+	session := test.createSession(`{"mode": "encode-words", "pool": {"wordLength": 5}, "iterations": 2, "seed": 7}`)
+	if session.Next == nil || len(session.Next.Prompt) == 0 || len(session.Next.Prompt) > 5 {
+		t.Fatalf("session = %+v, want the word to encode", session)
+	}
+
+	// The dahs can be typed as hyphens.
+	prompt := session.Next.Prompt
+	first := test.answer(session.ID, strings.ReplaceAll(commons.ToMorseCode(prompt), ",", "-"))
+	if !first.Answer.Correct || first.Answer.Text != prompt {
+		t.Errorf("answer = %+v, want a correct answer to %v", first.Answer, prompt)
+	}
+
+	if score := first.Answer.Score; score == nil || score.Correct != len(prompt) || score.Total != len(prompt) || score.Words != nil {
+		t.Errorf("score = %+v, want all %v characters right", score, len(prompt))
+	}
+
+	second := test.answer(session.ID, "...")
+	if second.Answer.Correct || second.Answer.Score == nil || second.Answer.Score.Correct == second.Answer.Score.Total {
+		t.Errorf("answer ... = %+v, want it wrong", second.Answer)
+	}
+
+	results := test.results(session.ID)
+	if !results.Done || results.Score != 1 || results.Total != 2 {
+		t.Errorf("results = %+v, want 1 of 2 right", results)
+	}
+}
+
+func TestDecodeQuoteScore(t *testing.T) {
+	test := newAPITest(t)
+
+	session := test.createSession(`{"mode": "decode-quotes", "pool": {"maxLength": 12}}`)
+	answer := test.answer(session.ID, "hello word")
+
+	score := answer.Answer.Score
+	if answer.Answer.Correct || score == nil || score.Words == nil || score.Words.Correct != 1 || score.Words.Total != 2 {
+		t.Errorf("answer = %+v, want 1 of the 2 words of the quote right", answer.Answer)
+	}
+}
+
+func TestSameSeedSameItems(t *testing.T) {
+	test := newAPITest(t)
+
+	texts := func(body string) []string {
+		session := test.createSession(body)
+		if session.Seed != 42 {
+			t.Errorf("seed = %v, want 42", session.Seed)
+		}
+
+		for range session.Total {
+			test.answer(session.ID, "")
+		}
+
+		texts := []string{}
+		for _, answer := range test.results(session.ID).Answers {
+			texts = append(texts, answer.Text)
+		}
+
+		return texts
+	}
+
+	for _, body := range []string{
+		`{"mode": "decode-words", "pool": {"level": 2}, "iterations": 4, "seed": 42}`,
+		`{"mode": "encode-letters", "pool": {"level": 3}, "iterations": 10, "seed": 42}`,
+	} {
+		first, second := texts(body), texts(body)
+		if !slices.Equal(first, second) {
+			t.Errorf("items of %v = %v, then %v, want the same", body, first, second)
+		}
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	test := newAPITest(t)
+
+	for _, body := range []string{
+		`{"mode": "decode-words", "pool": {"level": 1}, "colour": "red"}`,
+		`{"mode": "decode-words", "pool": {"level": 1, "lenght": 3}}`,
+		`{"mode": "decode-numbers", "pool": {"level": 1}}`,
+		`{"mode": "decode-letters", "pool": {"level": 1}, "iterations": 501}`,
+		`{"mode": "decode-letters", "pool": {"level": 1}, "iterations": -1}`,
+		`{"mode": "encode-words", "pool": {}, "iterations": 101}`,
+		`{"mode": "decode-quotes", "pool": {}, "iterations": 21}`,
+		`{"mode": "decode-letters", "pool": {}}`,
+		`{"mode": "decode-letters", "pool": {"level": 1}, "speed": 5}`,
+		`{"mode": "decode-letters"`,
+	} {
+		if response := test.do("POST", "/sessions", "/sessions", body); response.status != http.StatusBadRequest {
+			t.Errorf("creating %v = %v, want 400", body, response.status)
+		}
+	}
+
+	session := test.createSession(`{"mode": "decode-letters", "pool": {"level": 1}}`)
+	body := `{"answer": "e", "latency": 10}`
+	if response := test.do("POST", "/sessions/{id}/answers", "/sessions/"+session.ID+"/answers", body); response.status != http.StatusBadRequest {
+		t.Errorf("answering %v = %v, want 400", body, response.status)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	test := newAPITest(t)
+
+	for _, request := range []struct{ method, template, path string }{
+		{"GET", "/sessions/{id}", "/sessions/nothing"},
+		{"DELETE", "/sessions/{id}", "/sessions/nothing"},
+		{"GET", "/sessions/{id}/next", "/sessions/nothing/next"},
+		{"GET", "/sessions/{id}/next/audio", "/sessions/nothing/next/audio"},
+		{"POST", "/sessions/{id}/replay", "/sessions/nothing/replay"},
+		{"GET", "/sessions/{id}/results", "/sessions/nothing/results"},
+	} {
+		if response := test.do(request.method, request.template, request.path, ""); response.status != http.StatusNotFound {
+			t.Errorf("%v %v = %v, want 404", request.method, request.path, response.status)
+		}
+	}
+
+	session := test.createSession(`{"mode": "decode-letters", "pool": {"level": 1}, "iterations": 3}`)
+	test.answer(session.ID, "e")
+
+	// Item 1 is the current one, item 2 is not known yet.
+	for index, want := range map[string]int{"0": 200, "1": 200, "2": 404, "3": 404, "-1": 404, "one": 404} {
+		path := fmt.Sprintf("/sessions/%v/items/%v/audio", session.ID, index)
+		if response := test.do("GET", "/sessions/{id}/items/{index}/audio", path, ""); response.status != want {
+			t.Errorf("GET %v = %v, want %v", path, response.status, want)
+		}
+	}
+
+	// Not in the document, but still answered in JSON.
+	response := test.do("GET", "", "/nothing", "")
+	if contentType := response.header.Get("Content-Type"); response.status != http.StatusNotFound || contentType != "application/json" {
+		t.Errorf("GET %v/nothing = %v %v, want a 404 in JSON", Prefix, response.status, contentType)
+	}
+
+	if response := test.do("DELETE", "/sessions/{id}", "/sessions/"+session.ID, ""); response.status != http.StatusNoContent {
+		t.Errorf("deleting the session = %v, want 204", response.status)
+	}
+
+	if response := test.do("GET", "/sessions/{id}", "/sessions/"+session.ID, ""); response.status != http.StatusNotFound {
+		t.Errorf("getting a deleted session = %v, want 404", response.status)
+	}
+}
+
+func TestDoneSession(t *testing.T) {
+	test := newAPITest(t)
+
+	session := test.createSession(`{"mode": "decode-letters", "pool": {"letters": "t"}, "iterations": 1}`)
+	test.answer(session.ID, "t")
+
+	for _, request := range []struct{ method, template, path, body string }{
+		{"GET", "/sessions/{id}/next", "/sessions/" + session.ID + "/next", ""},
+		{"GET", "/sessions/{id}/next/audio", "/sessions/" + session.ID + "/next/audio", ""},
+		{"POST", "/sessions/{id}/replay", "/sessions/" + session.ID + "/replay", ""},
+		{"POST", "/sessions/{id}/answers", "/sessions/" + session.ID + "/answers", `{"answer": "t"}`},
+	} {
+		if response := test.do(request.method, request.template, request.path, request.body); response.status != http.StatusConflict {
+			t.Errorf("%v %v when done = %v, want 409", request.method, request.path, response.status)
+		}
+	}
+
+	// The sessions that are done can still be looked at.
+	response := test.do("GET", "/sessions/{id}", "/sessions/"+session.ID, "")
+	done := sessionJSON{}
+	response.decode(t, &done)
+
+	if response.status != http.StatusOK || !done.Done || done.Next != nil {
+		t.Errorf("GET the session = %v %+v, want it done", response.status, done)
+	}
+}
+
+func TestAudio(t *testing.T) {
+	test := newAPITest(t)
+
+	session := test.createSession(`{"mode": "decode-letters", "pool": {"letters": "e"}, "iterations": 2}`)
+	slow := test.createSession(`{"mode": "decode-letters", "pool": {"letters": "e"}, "iterations": 2, "speed": 0.5}`)
+
+	audio := func(id string) apiResponse {
+		response := test.do("GET", "/sessions/{id}/next/audio", "/sessions/"+id+"/next/audio", "")
+		if response.status != http.StatusOK {
+			t.Fatalf("getting the audio = %v %s, want 200", response.status, response.body)
+		}
+
+		return response
+	}
+
+	response := audio(session.ID)
+	if contentType := response.header.Get("Content-Type"); contentType != "audio/wav" {
+		t.Errorf("Content-Type = %q, want audio/wav", contentType)
+	}
+
+	if !bytes.HasPrefix(response.body, []byte("RIFF")) || !bytes.Equal(response.body[8:12], []byte("WAVE")) {
+		t.Errorf("the audio is not a WAV file")
+	}
+
+	if length := response.header.Get("Content-Length"); length != strconv.Itoa(len(response.body)) {
+		t.Errorf("Content-Length = %v, want %v", length, len(response.body))
+	}
+
+	if slowResponse := audio(slow.ID); len(slowResponse.body) <= len(response.body) {
+		t.Errorf("the audio at half the speed is %v bytes, want more than %v", len(slowResponse.body), len(response.body))
+	}
+
+	itemAudio := test.do("GET", "/sessions/{id}/items/{index}/audio", "/sessions/"+session.ID+"/items/0/audio", "")
+	if !bytes.Equal(itemAudio.body, response.body) || itemAudio.header.Get("Content-Type") != "audio/wav" {
+		t.Errorf("the audio of item 0 differs from the audio of the next item")
+	}
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/cmd/decode"
+	"github.com/noAbbreviation/dihdah/cmd/encode"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+// The drills of the API, picked and graded like the commands with the same
+// name.
+type drillMode struct {
+	name     string
+	generate func(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error)
+	check    drill.Check
+
+	// The encode drills show the text and take its morse code, the decode
+	// drills play the morse code and take the text.
+	encodes bool
+
+	// For the texts, which are rarely all correct. Nil for the rest.
+	score func(answer drill.Answer) *scoreJSON
+}
+
+var drillModes = map[string]drillMode{
+	"decode-letters": {
+		name:     "decode-letters",
+		generate: generateDecodeLetters,
+		check:    drill.CheckText,
+	},
+	"decode-words": {
+		name:     "decode-words",
+		generate: generateDecodeWords,
+		check:    drill.CheckText,
+	},
+	"decode-quotes": {
+		name:     "decode-quotes",
+		generate: generateQuotes(decode.QuoteItems),
+		check:    decode.CheckQuote,
+		score:    decodeQuoteScore,
+	},
+	"encode-letters": {
+		name:     "encode-letters",
+		generate: generateEncodeLetters,
+		check:    drill.CheckMorseCode,
+		encodes:  true,
+	},
+	"encode-words": {
+		name:     "encode-words",
+		generate: generateEncodeWords,
+		check:    encode.CheckTextCode,
+		encodes:  true,
+		score:    encodeTextScore,
+	},
+	"encode-quotes": {
+		name:     "encode-quotes",
+		generate: generateQuotes(encode.TextItems),
+		check:    encode.CheckTextCode,
+		encodes:  true,
+		score:    encodeTextScore,
+	},
+}
+
+const modeNames = "decode-letters, decode-words, decode-quotes, encode-letters, encode-words or encode-quotes"
+
+func (mode drillMode) normalizeAnswer(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if mode.encodes {
+		answer = encode.NormalizeCode(answer)
+	}
+
+	return answer
+}
+
+func (mode drillMode) answerJSON(answer drill.Answer) answerJSON {
+	json := answerJSON{
+		Text:      answer.Item.Text,
+		MorseCode: answer.Item.MorseCode,
+		Given:     answer.Given,
+		Correct:   answer.Correct,
+		LatencyMs: answer.Latency.Milliseconds(),
+		Replays:   answer.Replays,
+	}
+
+	if mode.score != nil {
+		json.Score = mode.score(answer)
+	}
+
+	return json
+}
+
+func decodeQuoteScore(answer drill.Answer) *scoreJSON {
+	results, _, _, _ := decode.GradeQuote(answer.Given, answer.Item.Text)
+	return &scoreJSON{
+		Correct: results.Corrects,
+		Total:   results.Total,
+		Words: &wordScoreJSON{
+			Correct: results.WordCorrects,
+			Total:   results.WordTotal,
+		},
+	}
+}
+
+func encodeTextScore(answer drill.Answer) *scoreJSON {
+	correct, total := encode.TextCodeScore(answer.Item, answer.Given)
+	return &scoreJSON{Correct: correct, Total: total}
+}
+
+// The letters a to z of the custom letters, or else the letters of the level.
+func poolLetters(pool poolJSON, levelLetters func(level int) string, levels int) (string, error) {
+	letters := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+
+		return -1
+	}, strings.ToLower(pool.Letters))
+
+	if len(letters) != 0 {
+		return letters, nil
+	}
+
+	if pool.Level < 1 || pool.Level > levels {
+		return "", fmt.Errorf("Error: the pool needs either letters, or a level from 1 to %v.", levels)
+	}
+
+	return levelLetters(pool.Level), nil
+}
+
+func checkIterations(iterations int, maxIterations int) error {
+	if iterations < 0 || iterations > maxIterations {
+		return fmt.Errorf("Error: the iterations should be from 1 to %v, or zero for the default.", maxIterations)
+	}
+
+	return nil
+}
+
+func generateDecodeLetters(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+	letters, err := poolLetters(request.Pool, decode.LevelLetters, len(decode.NewLettersPerLevel))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIterations(request.Iterations, 500); err != nil {
+		return nil, err
+	}
+
+	return drill.LetterItems(decode.TrainingLetters(random, letters, request.Iterations, false)), nil
+}
+
+func generateEncodeLetters(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+	letters, err := poolLetters(request.Pool, encode.LevelLetters, len(encode.NewLettersPerLevel))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIterations(request.Iterations, 500); err != nil {
+		return nil, err
+	}
+
+	return drill.LetterItems(encode.TrainingLetters(random, letters, request.Iterations, false)), nil
+}
+
+// Up to the word length of the pool, or else of the level.
+func generateDecodeWords(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+	wordLength := request.Pool.WordLength
+	if wordLength == 0 {
+		levels := len(decode.MaxWordLenPerLevel)
+		if request.Pool.Level < 1 || request.Pool.Level > levels {
+			return nil, fmt.Errorf("Error: the pool needs either a word length, or a level from 1 to %v.", levels)
+		}
+
+		wordLength = decode.MaxWordLenPerLevel[request.Pool.Level-1]
+	}
+
+	return pickWords(random, decode.WordPool(server.words, wordLength), request.Iterations, drill.WordItems)
+}
+
+// Up to the word length of the pool, zero for no limit.
+func generateEncodeWords(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+	if request.Pool.WordLength < 0 {
+		return nil, fmt.Errorf("Error: the word length should not be negative.")
+	}
+
+	return pickWords(random, encode.WordPool(server.words, request.Pool.WordLength), request.Iterations, encode.TextItems)
+}
+
+func pickWords(random *rand.Rand, wordPool []string, iterations int, items func(words []string) []drill.Item) ([]drill.Item, error) {
+	if len(wordPool) == 0 {
+		return nil, fmt.Errorf("Error: there are no words within the word length.")
+	}
+
+	if iterations == 0 {
+		iterations = 5
+	}
+
+	if err := checkIterations(iterations, 100); err != nil {
+		return nil, err
+	}
+
+	return items(commons.PickWords(random, wordPool, iterations)), nil
+}
+
+// Within the lengths of the pool, a zero maximum for no limit.
+func generateQuotes(items func(quotes []string) []drill.Item) func(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+	return func(server *Server, random *rand.Rand, request sessionRequest) ([]drill.Item, error) {
+		minLength, maxLength := request.Pool.MinLength, request.Pool.MaxLength
+		if minLength < 0 || maxLength < 0 || (maxLength != 0 && minLength > maxLength) {
+			return nil, fmt.Errorf("Error: the quote lengths should not be negative, and the minimum not over the maximum.")
+		}
+
+		quotePool := commons.FilterQuotesByLength(server.quotes, minLength, maxLength)
+		if len(quotePool) == 0 {
+			return nil, fmt.Errorf("Error: there are no quotes within the lengths.")
+		}
+
+		iterations := request.Iterations
+		if iterations == 0 {
+			iterations = 1
+		}
+
+		if err := checkIterations(iterations, 20); err != nil {
+			return nil, err
+		}
+
+		return items(commons.PickQuotes(random, quotePool, iterations)), nil
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var GetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Shows the value of a key, in the defaults or in the --profile.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userConfig, err := commons.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		key := args[0]
+		values := userConfig.Defaults
+
+		profile, _ := cmd.Flags().GetString("profile")
+		if name, isBinding := strings.CutPrefix(key, keysPrefix); isBinding {
+			keyMap := commons.DefaultKeyMap()
+			if err := keyMap.Override(userConfig.Keys); err != nil {
+				return err
+			}
+
+			binding, exists := keyMap.Bindings()[name]
+			if !exists {
+				return fmt.Errorf("Error: There is no key binding named %q.", name)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), formatKeys(binding.Keys()))
+			return nil
+		}
+
+		if name, isColor := strings.CutPrefix(key, colorsPrefix); isColor {
+			value, found := userConfig.Colors[name]
+			if !found {
+				return fmt.Errorf("Error: %q is not set.", key)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		}
+
+		if len(profile) != 0 {
+			profileValues, exists := userConfig.Profile(profile)
+			if !exists {
+				return fmt.Errorf("Error: There is no profile named %q.", profile)
+			}
+
+			values = profileValues
+		}
+
+		value, found := values[key]
+		if !found {
+			return fmt.Errorf("Error: %q is not set.", key)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Shows every key set in the defaults, and the profiles.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userConfig, err := commons.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		filePath, err := commons.ConfigFilePath()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Config file: %v\n", filePath)
+
+		printValues := func(title string, values map[string]string) {
+			fmt.Fprintf(out, "\n%v\n", title)
+			if len(values) == 0 {
+				fmt.Fprintln(out, "  (nothing set)")
+			}
+
+			for _, key := range slices.Sorted(maps.Keys(values)) {
+				fmt.Fprintf(out, "  %v = %v\n", key, values[key])
+			}
+		}
+
+		printValues("Defaults:", userConfig.Defaults)
+
+		keys := map[string]string{}
+		for name, bindingKeys := range userConfig.Keys {
+			keys[keysPrefix+name] = formatKeys(bindingKeys)
+		}
+
+		printValues("Key bindings:", keys)
+
+		colors := map[string]string{}
+		for name, color := range userConfig.Colors {
+			colors[colorsPrefix+name] = color
+		}
+
+		printValues("Custom theme:", colors)
+
+		for _, name := range userConfig.ProfileNames() {
+			title := fmt.Sprintf("Profile %v:", name)
+			if _, isBuiltin := commons.BuiltinProfiles[name]; isBuiltin {
+				title = fmt.Sprintf("Profile %v (built-in):", name)
+			}
+
+			values, _ := userConfig.Profile(name)
+			printValues(title, values)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var SetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Sets the value of a key, in the defaults or in the --profile.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+
+		userConfig, err := commons.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		profile, _ := cmd.Flags().GetString("profile")
+		if name, isBinding := strings.CutPrefix(key, keysPrefix); isBinding {
+			if len(profile) != 0 {
+				return fmt.Errorf("Error: The key bindings are the same for every profile.")
+			}
+
+			keys := strings.Split(value, ",")
+			keyMap := commons.DefaultKeyMap()
+			if err := keyMap.Override(map[string][]string{name: keys}); err != nil {
+				return err
+			}
+
+			if userConfig.Keys == nil {
+				userConfig.Keys = map[string][]string{}
+			}
+
+			userConfig.Keys[name] = keys
+			return commons.SaveConfig(userConfig)
+		}
+
+		if name, isColor := strings.CutPrefix(key, colorsPrefix); isColor {
+			if len(profile) != 0 {
+				return fmt.Errorf("Error: The custom theme is the same for every profile.")
+			}
+
+			if err := commons.ValidateThemeColor(name, value); err != nil {
+				return err
+			}
+
+			if userConfig.Colors == nil {
+				userConfig.Colors = map[string]string{}
+			}
+
+			userConfig.Colors[name] = value
+			return commons.SaveConfig(userConfig)
+		}
+
+		if err := validate(cmd.Root(), key, value); err != nil {
+			return err
+		}
+
+		values := editedValues(&userConfig, profile)
+
+		values[key] = value
+		return commons.SaveConfig(userConfig)
+	},
+}
+
+var UnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Removes a key from the defaults or from the --profile.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userConfig, err := commons.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		key := args[0]
+		profile, _ := cmd.Flags().GetString("profile")
+
+		if name, isBinding := strings.CutPrefix(key, keysPrefix); isBinding {
+			if _, found := userConfig.Keys[name]; !found {
+				return fmt.Errorf("Error: %q is not set.", key)
+			}
+
+			delete(userConfig.Keys, name)
+			return commons.SaveConfig(userConfig)
+		}
+
+		if name, isColor := strings.CutPrefix(key, colorsPrefix); isColor {
+			if _, found := userConfig.Colors[name]; !found {
+				return fmt.Errorf("Error: %q is not set.", key)
+			}
+
+			delete(userConfig.Colors, name)
+			return commons.SaveConfig(userConfig)
+		}
+
+		values := editedValues(&userConfig, profile)
+		if _, found := values[key]; !found {
+			return fmt.Errorf("Error: %q is not set.", key)
+		}
+
+		delete(values, key)
+		return commons.SaveConfig(userConfig)
+	},
+}
+
+// The values of the profile (or of the defaults) to edit. The values of a
+// built-in profile are copied into the config file, so that they can be unset.
+func editedValues(userConfig *commons.Config, profile string) map[string]string {
+	if len(profile) == 0 {
+		if userConfig.Defaults == nil {
+			userConfig.Defaults = map[string]string{}
+		}
+
+		return userConfig.Defaults
+	}
+
+	if userConfig.Profiles == nil {
+		userConfig.Profiles = map[string]map[string]string{}
+	}
+
+	if _, exists := userConfig.Profiles[profile]; !exists {
+		userConfig.Profiles[profile] = maps.Clone(commons.BuiltinProfiles[profile])
+	}
+
+	if userConfig.Profiles[profile] == nil {
+		userConfig.Profiles[profile] = map[string]string{}
+	}
+
+	return userConfig.Profiles[profile]
+}
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Key bindings are set as "keys.<name>", like "keys.submit".
+const keysPrefix = "keys."
+
+// Colors of the custom theme are set as "colors.<name>", like "colors.wrong".
+const colorsPrefix = "colors."
+
+// Cobra adds this annotation to the flags of MarkFlagsMutuallyExclusive.
+const mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+
+func init() {
+	Cmd.AddCommand(GetCmd)
+	Cmd.AddCommand(SetCmd)
+	Cmd.AddCommand(UnsetCmd)
+	Cmd.AddCommand(ListCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "config",
+	Short: "Defaults and profiles for the flags of the commands.",
+	// Overrides the one of the root command, as the config is not applied
+	// here and --profile can name a profile yet to be made.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return selectUser(cmd)
+	},
+	Long: `The 'config' command manages the config file of dihdah, which gives the defaults of the
+flags of every command, so that they are not typed again on every run.
+
+These are the things the user can do in here:
+  - 'dihdah config get <key>': Shows the value of a key.
+  - 'dihdah config set <key> <value>': Sets the value of a key.
+  - 'dihdah config unset <key>': Removes a key, going back to the default of the flag.
+  - 'dihdah config list': Shows every key set, and the profiles.
+
+# Keys
+
+A key is either the name of a flag, for every command with that flag, or the
+command and the flag name joined with dots, for only that command:
+
+  speed                 --speed of every command
+  decode.letter.speed   --speed of 'dihdah decode letter' only (over "speed")
+  decode.output         --output of 'dihdah decode' and its commands
+
+  $ dihdah config set speed 1.25
+  $ dihdah config set decode.word.level 2
+  $ dihdah decode word           # same as 'dihdah decode word --speed 1.25 --level 2'
+
+The flags given in the command line are always used over the config. A key is
+not used when its flag cannot be used with a flag given, like "level" when
+running with --letters, and a warning says so.
+
+# Profiles
+
+A profile is a named set of keys, used over the defaults with --profile:
+
+  $ dihdah decode letter --profile evening
+
+These profiles are built in, and setting their keys replaces their values:
+
+  beginner   level 1, slower speed, live visualizer, 12 wpm sending
+  contest    faster speed, no visualizer, 28 wpm sending, 3s encode time limit
+  evening    the morse code flashed instead of played, with a live visualizer
+
+Other profiles are made by setting a key in them:
+
+  $ dihdah config set --profile commute speed 1.5
+
+# Key bindings
+
+The keys of the drills and the TUI are set as "keys.<name>", with the keys
+separated by commas, and "space" for the space bar:
+
+  $ dihdah config set keys.submit ctrl+d,f2
+  $ dihdah config set keys.replay space,r
+
+The key bindings are:
+
+  quit, back, confirm, chart, sort    most drills (ctrl+c, escape, enter, ctrl+o, s)
+  replay                              letter, word and number drills (space)
+  submit, toggle-playback, rewind,    quote and text drills (ctrl+d or ctrl+s, ctrl+l,
+    forward, replay-word                ctrl+left, ctrl+right, ctrl+r)
+  up, down, left, right, undo         tree drill, chart and head copy questions
+  close-chart, next-view, prev-view   chart (escape, tab, shift+tab)
+  menu-up, menu-down, menu-back,      TUI menus
+    menu-select, toggle, increase,
+    decrease, reset
+
+The key bindings are the same for every profile.
+
+# Themes
+
+The colors of the drills are selected with --theme, or with the "theme" key:
+
+  dark            the default, for dark terminals
+  light           for light terminals
+  high-contrast   the colors of the terminal, with bold and reversed text
+  custom          the colors set as "colors.<name>" below
+
+  $ dihdah config set theme light
+
+The custom theme takes the colors not set from "colors.base" (dark by default).
+The colors are hex like "#ffcc00" or ANSI numbers like "2":
+
+  $ dihdah config set colors.base light
+  $ dihdah config set colors.wrong "#ff0000"
+  $ dihdah config set theme custom
+
+The colors are: correct, wrong, accent (the highlighted characters and the
+progress bar), selected, selected-background, faint, border, flash (the
+default --flash-color), help-key and help.
+
+With the NO_COLOR environment variable set, no colors are used at all, only
+bold, underlined and reversed text.
+
+The config file is config.json in the same directory as the training history
+($XDG_CONFIG_HOME/dihdah on linux), which also keeps the values last used in
+'dihdah ui'. Each user of --user has their own (see 'dihdah users --help').`,
+}
+
+// Like "decode.letter" for 'dihdah decode letter', or empty for the root command.
+func commandKey(cmd *cobra.Command) string {
+	return strings.Join(strings.Fields(cmd.CommandPath())[1:], ".")
+}
+
+func configurable(flag *pflag.Flag) bool {
+	return !slices.Contains([]string{"help", "version", "profile", "user"}, flag.Name)
+}
+
+// Selects the user of --user, whose files are used from then on.
+func selectUser(cmd *cobra.Command) error {
+	userName, _ := cmd.Flags().GetString("user")
+	return commons.SetUser(userName)
+}
+
+// Selects the user of --user, and sets the flags not given to the command from
+// their config file, with the values of --profile over the defaults.
+func Apply(cmd *cobra.Command) error {
+	if err := selectUser(cmd); err != nil {
+		return err
+	}
+
+	userConfig, err := commons.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := commons.Keys.Override(userConfig.Keys); err != nil {
+		return err
+	}
+
+	profile, _ := cmd.Flags().GetString("profile")
+	if _, exists := userConfig.Profile(profile); len(profile) != 0 && !exists {
+		return fmt.Errorf(
+			"Error: There is no profile named %q. The profiles are: %v",
+			profile,
+			strings.Join(userConfig.ProfileNames(), ", "),
+		)
+	}
+
+	flags := cmd.Flags()
+
+	warnings, err := setFlags(flags, userConfig, profile, commandKey(cmd))
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		cmd.PrintErrln(warning)
+	}
+
+	themeName, _ := flags.GetString("theme")
+	theme, err := commons.LoadTheme(themeName, userConfig.Colors)
+	if err != nil {
+		return err
+	}
+
+	commons.SetTheme(theme)
+	return nil
+}
+
+// Sets the flags not given from the config, for the command of the key. The
+// values whose flag cannot be used with a flag already set are skipped, with a
+// warning for each.
+func setFlags(flags *pflag.FlagSet, userConfig commons.Config, profile string, key string) (warnings []string, err error) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || !configurable(flag) {
+			return
+		}
+
+		value, found := userConfig.FlagValue(profile, key, flag.Name)
+		if !found {
+			return
+		}
+
+		if other, conflicts := changedConflict(flags, flag); conflicts {
+			warnings = append(warnings, fmt.Sprintf(
+				"Warning: The config value %q of --%v is not used, as --%v is set.",
+				value,
+				flag.Name,
+				other,
+			))
+
+			return
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("Error: Invalid value %q for --%v in the config: %v", value, flag.Name, setErr)
+		}
+	})
+
+	return warnings, err
+}
+
+// The name of a flag already set that the flag cannot be used with, if any.
+func changedConflict(flags *pflag.FlagSet, flag *pflag.Flag) (string, bool) {
+	for _, group := range flag.Annotations[mutuallyExclusiveAnnotation] {
+		for _, name := range strings.Split(group, " ") {
+			if other := flags.Lookup(name); other != nil && other != flag && other.Changed {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// The flags of every command that the key is for.
+func flagsOfKey(root *cobra.Command, key string) []*pflag.Flag {
+	found := []*pflag.Flag{}
+
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		for _, flags := range []*pflag.FlagSet{cmd.LocalFlags(), cmd.InheritedFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if !configurable(flag) || slices.Contains(found, flag) {
+					return
+				}
+
+				if key == flag.Name || key == commons.ConfigKey(commandKey(cmd), flag.Name) {
+					found = append(found, flag)
+				}
+			})
+		}
+
+		for _, subCmd := range cmd.Commands() {
+			visit(subCmd)
+		}
+	}
+
+	visit(root)
+	return found
+}
+
+// Like "ctrl+d,ctrl+s", as given to 'config set'.
+func formatKeys(keys []string) string {
+	formatted := slices.Clone(keys)
+	for i, k := range formatted {
+		if k == " " {
+			formatted[i] = "space"
+		}
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// Checks that the key is for a flag, and that the value can be given to it.
+func validate(root *cobra.Command, key string, value string) error {
+	flags := flagsOfKey(root, key)
+	if len(flags) == 0 {
+		return fmt.Errorf("Error: No command has a flag for %q.", key)
+	}
+
+	for _, flag := range flags {
+		previous := flag.Value.String()
+		err := flag.Value.Set(value)
+		flag.Value.Set(previous)
+
+		if err != nil {
+			return fmt.Errorf("Error: Invalid value %q for --%v: %v", value, flag.Name, err)
+		}
+	}
+
+	return nil
+}
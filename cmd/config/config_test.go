@@ -0,0 +1,98 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+// A command like 'decode letter', with --letters and --level exclusive.
+func newTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "letter"}
+	cmd.Flags().String("letters", "", "")
+	cmd.Flags().Int("level", 1, "")
+	cmd.Flags().Float64("speed", 1, "")
+	cmd.MarkFlagsMutuallyExclusive("letters", "level")
+
+	root := &cobra.Command{Use: "dihdah"}
+	decode := &cobra.Command{Use: "decode"}
+	root.AddCommand(decode)
+	decode.AddCommand(cmd)
+
+	return cmd
+}
+
+func TestSetFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		defaults     map[string]string
+		want         map[string]string
+		wantWarnings []string
+	}{
+		{
+			"flags not given",
+			nil,
+			map[string]string{"level": "3", "speed": "1.5"},
+			map[string]string{"level": "3", "speed": "1.5"},
+			nil,
+		},
+		{
+			"flag given over the config",
+			[]string{"--speed", "2"},
+			map[string]string{"speed": "1.5"},
+			map[string]string{"speed": "2"},
+			nil,
+		},
+		{
+			"exclusive flag given",
+			[]string{"--letters", "abc"},
+			map[string]string{"level": "3", "speed": "1.5"},
+			map[string]string{"letters": "abc", "level": "1", "speed": "1.5"},
+			[]string{`Warning: The config value "3" of --level is not used, as --letters is set.`},
+		},
+		{
+			// The first one in the order of the flags is used.
+			"both exclusive flags in the config",
+			nil,
+			map[string]string{"letters": "abc", "decode.letter.level": "3"},
+			map[string]string{"letters": "abc", "level": "1"},
+			[]string{`Warning: The config value "3" of --level is not used, as --letters is set.`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := newTestCommand()
+			if err := cmd.ParseFlags(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			warnings, err := setFlags(cmd.Flags(), commons.Config{Defaults: test.defaults}, "", commandKey(cmd))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, want := range test.want {
+				if got := cmd.Flags().Lookup(name).Value.String(); got != want {
+					t.Errorf("--%v = %q, want %q", name, got, want)
+				}
+			}
+
+			if !slices.Equal(warnings, test.wantWarnings) {
+				t.Errorf("warnings = %q, want %q", warnings, test.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestSetFlagsInvalidValue(t *testing.T) {
+	cmd := newTestCommand()
+	config := commons.Config{Defaults: map[string]string{"speed": "fast"}}
+
+	if _, err := setFlags(cmd.Flags(), config, "", commandKey(cmd)); err == nil {
+		t.Errorf("setFlags() with an invalid value = nil, want an error")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 	"github.com/spf13/cobra"
 )
 
@@ -65,41 +66,28 @@ var LetterCmd = &cobra.Command{
 				return fmt.Errorf("Error: --letters is empty.")
 			}
 
-			for i := range levelArg {
-				letters += NewLettersPerLevel[i]
-			}
+			letters = LevelLetters(int(levelArg))
 		}
 
 		dedupedLetters := DedupCleanLetters(letters)
 		speed, _ := cmd.Flags().GetFloat64("speed")
 
-		doAllLetters, _ := cmd.Flags().GetBool("recap")
-		if doAllLetters {
-			allLettersRand := []rune(dedupedLetters)
-			rand.Shuffle(len(allLettersRand), func(i, j int) {
-				allLettersRand[i], allLettersRand[j] = allLettersRand[j], allLettersRand[i]
-			})
-
-			p := tea.NewProgram(NewLetterModel(string(allLettersRand), dedupedLetters, speed, nil))
-			if _, err := p.Run(); err != nil {
-				return fmt.Errorf("Error running the program: %v", err)
-			}
-
-			return nil
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
 		}
 
+		doAllLetters, _ := cmd.Flags().GetBool("recap")
 		iterations, _ := cmd.Flags().GetUint("iterations")
-		if iterations == 0 {
-			iterations = max(uint(len(dedupedLetters)/2), 3)
-		}
 
-		trainingLetters := ""
-		for range iterations {
-			randomLetter := letters[rand.Intn(len(letters))]
-			trainingLetters += string(randomLetter)
+		random, _ := commons.NewRandom(0)
+		trainingLetters := TrainingLetters(random, letters, int(iterations), doAllLetters)
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainLetters(plainSession(cmd), trainingLetters, dedupedLetters, speed)
 		}
 
-		p := tea.NewProgram(NewLetterModel(trainingLetters, dedupedLetters, speed, nil))
+		p := tea.NewProgram(NewLetterModel(trainingLetters, dedupedLetters, speed, playback, nil))
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("Error running the program: %v", err)
 		}
@@ -161,6 +149,43 @@ NOTES:
 	slow down or speed up the sound being played.`,
 }
 
+// The letters of the levels up to the given one.
+func LevelLetters(level int) string {
+	letters := ""
+	for i := range min(level, len(NewLettersPerLevel)) {
+		letters += NewLettersPerLevel[i]
+	}
+
+	return letters
+}
+
+// Picks the letters to train: random letters of the pool (by default half as
+// many as there are letters, at least 3), or all of them shuffled for a recap.
+func TrainingLetters(random *rand.Rand, letters string, iterations int, recap bool) string {
+	dedupedLetters := DedupCleanLetters(letters)
+
+	if recap {
+		allLettersRand := []rune(dedupedLetters)
+		random.Shuffle(len(allLettersRand), func(i, j int) {
+			allLettersRand[i], allLettersRand[j] = allLettersRand[j], allLettersRand[i]
+		})
+
+		return string(allLettersRand)
+	}
+
+	if iterations == 0 {
+		iterations = max(len(dedupedLetters)/2, 3)
+	}
+
+	trainingLetters := ""
+	for range iterations {
+		randomLetter := letters[random.Intn(len(letters))]
+		trainingLetters += string(randomLetter)
+	}
+
+	return trainingLetters
+}
+
 func DedupCleanLetters(str string) string {
 	runes := []rune(strings.ToLower(str))
 	firstLetter := runes[0]
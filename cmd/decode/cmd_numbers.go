@@ -0,0 +1,243 @@
+package decode
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/spf13/cobra"
+)
+
+// The usual cut numbers sent in contest exchanges, (e.g. 5nn for 599)
+var CutNumbers = map[rune]rune{
+	'0': 't',
+	'9': 'n',
+	'1': 'a',
+}
+
+func init() {
+	NumberCmd.Flags().Uint16P("iterations", "n", 5, "Training iterations.")
+	NumberCmd.Flags().Float64P("speed", "s", 1, "Speed ratio to train with.")
+	NumberCmd.Flags().Uint16P("digits", "d", 3, "Length of the numbers to train with.")
+
+	NumberCmd.Flags().Bool("cut", false, "Send cut numbers (t for 0, n for 9, a for 1).")
+	NumberCmd.Flags().Bool("grid", false, "Train with Maidenhead grid locators instead of numbers.")
+
+	NumberCmd.MarkFlagsMutuallyExclusive("cut", "grid")
+	NumberCmd.MarkFlagsMutuallyExclusive("digits", "grid")
+}
+
+var NumberCmd = &cobra.Command{
+	Use:     "number",
+	Short:   "Train for decoding numbers.",
+	Aliases: []string{"numbers"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iterations, _ := cmd.Flags().GetUint16("iterations")
+		if iterations == 0 {
+			return fmt.Errorf("--iterations is set to zero.")
+		}
+
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		if speed == 0 {
+			return fmt.Errorf("Speed must not be zero.")
+		}
+
+		digits, _ := cmd.Flags().GetUint16("digits")
+		if digits == 0 {
+			return fmt.Errorf("--digits is set to zero.")
+		}
+
+		useCutNumbers, _ := cmd.Flags().GetBool("cut")
+		useGrids, _ := cmd.Flags().GetBool("grid")
+
+		kind := plainNumbers
+		switch {
+		case useCutNumbers:
+			kind = cutNumbers
+		case useGrids:
+			kind = gridLocators
+		}
+
+		items := GenerateNumberItems(kind, int(iterations), int(digits))
+
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainNumbers(plainSession(cmd), items, kind, speed)
+		}
+
+		p := tea.NewProgram(NewNumberModel(items, kind, speed, playback, nil))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'decode numbers' command gives the user drills to decode numbers, the ones used in
+contest exchanges, serial numbers, signal reports and grid squares.
+
+# How it works
+
+For each item, you will be given a sound clip to listen. Input the number corresponding
+to the sound. Pressing space or hitting enter when empty will repeat the sound.
+Enter to confirm the answer.
+
+====================================================================
+Decode number training (3 digits) (1 of 5)
+> 599
+
+(escape to go back, space to repeat sound, enter to confirm, ctrl+c to exit)
+====================================================================
+
+At the end of the training session, you will be presented with the numbers played
+together with your input.
+
+==================================================================
+Decode number training results (cut numbers, 5 iterations):
+
+ #    Sent   Number  Correct?  Input
+ 1    5nn    599     yes       599
+
+ 2    a4t    140     no        150
+                                ?
+(1/5 mistakes) (escape/enter to go back, s to toggle sort, ctrl+c to exit)
+==================================================================
+
+# Extras
+
+With --cut, the digits 0, 9 and 1 are sent as t, n and a respectively. Either the
+digit or the cut letter is accepted as the answer.
+
+With --grid, the items are six character Maidenhead grid locators (e.g. fn31pr).
+The letters are not case sensitive.
+
+NOTE:
+- For the convenience and the challenge, --speed can be used to slow down or speed
+up the sound being played.`,
+}
+
+type numberKind int
+
+const (
+	plainNumbers numberKind = iota
+	cutNumbers
+	gridLocators
+)
+
+func (kind numberKind) String() string {
+	return [...]string{
+		"numbers",
+		"cut numbers",
+		"grid locators",
+	}[kind]
+}
+
+type numberItem struct {
+	Value string
+	Sent  string
+}
+
+func GenerateNumberItems(kind numberKind, iterations int, digits int) []numberItem {
+	items := make([]numberItem, 0, iterations)
+
+	for range iterations {
+		value := ""
+
+		switch kind {
+		case gridLocators:
+			value = randomGridLocator()
+		default:
+			value = randomNumber(digits)
+		}
+
+		item := numberItem{Value: value, Sent: value}
+		if kind == cutNumbers {
+			item.Sent = ToCutNumber(value)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func randomNumber(digits int) string {
+	number := strings.Builder{}
+
+	for i := range digits {
+		digit := rand.Intn(10)
+		if i == 0 && digits > 1 {
+			digit = 1 + rand.Intn(9)
+		}
+
+		number.WriteRune(rune('0' + digit))
+	}
+
+	return number.String()
+}
+
+func randomGridLocator() string {
+	return string([]rune{
+		rune('a' + rand.Intn(18)),
+		rune('a' + rand.Intn(18)),
+		rune('0' + rand.Intn(10)),
+		rune('0' + rand.Intn(10)),
+		rune('a' + rand.Intn(24)),
+		rune('a' + rand.Intn(24)),
+	})
+}
+
+func ToCutNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		if cut, ok := CutNumbers[r]; ok {
+			return cut
+		}
+
+		return r
+	}, number)
+}
+
+func FromCutNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		for digit, cut := range CutNumbers {
+			if r == cut {
+				return digit
+			}
+		}
+
+		return r
+	}, strings.ToLower(number))
+}
+
+// The items of a drill session, played as sent and graded by their values.
+func numberDrillItems(items []numberItem) []drill.Item {
+	drillItems := []drill.Item{}
+	for _, item := range items {
+		drillItems = append(drillItems, drill.Item{Text: item.Value, MorseCode: commons.ToMorseCode(item.Sent)})
+	}
+
+	return drillItems
+}
+
+func numberCheck(kind numberKind) drill.Check {
+	return func(item drill.Item, userAnswer string) bool {
+		return CheckNumberAnswer(kind, userAnswer, numberItem{Value: item.Text})
+	}
+}
+
+func CheckNumberAnswer(kind numberKind, userAnswer string, item numberItem) bool {
+	userAnswer = strings.ToLower(strings.TrimSpace(userAnswer))
+
+	switch kind {
+	case cutNumbers:
+		return FromCutNumber(userAnswer) == item.Value
+	default:
+		return userAnswer == strings.ToLower(item.Value)
+	}
+}
@@ -1,22 +1,28 @@
 package decode
 
 import (
-	"bufio"
 	_ "embed"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/commons"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	QuoteCmd.Flags().Uint16P("iterations", "n", 1, "Quotes to train with in one session.")
 	QuoteCmd.Flags().Float64P("speed", "s", 1, "Speed to do the training with.")
 	QuoteCmd.Flags().String("quotes", "", "Custom quote file to use for training.")
+	QuoteCmd.Flags().Bool("head-copy", false, "Listen without writing, then answer questions about the quote.")
+
+	QuoteCmd.Flags().Uint16("min-length", 0, "Minimum length (in characters) of the quotes to train with.")
+	QuoteCmd.Flags().Uint16("max-length", 0, "Maximum length (in characters) of the quotes to train with. Zero for no limit.")
+
+	QuoteCmd.MarkFlagsMutuallyExclusive("head-copy", "iterations")
 }
 
 var QuoteCmd = &cobra.Command{
@@ -24,6 +30,11 @@ var QuoteCmd = &cobra.Command{
 	Short:   "Train for decoding quotes.",
 	Aliases: []string{"quotes"},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		iterations, _ := cmd.Flags().GetUint16("iterations")
+		if iterations == 0 {
+			return fmt.Errorf("--iterations is set to zero.")
+		}
+
 		quotesFile, _ := cmd.Flags().GetString("quotes")
 		fileReader := io.Reader(strings.NewReader(assets.Quotes))
 
@@ -39,26 +50,59 @@ var QuoteCmd = &cobra.Command{
 			quotesFile = "(the default quotes file)"
 		}
 
-		quotes := []string(nil)
-		scanner := bufio.NewScanner(fileReader)
+		quotes, err := commons.LoadQuotes(fileReader)
+		if err != nil {
+			return fmt.Errorf("Error scanning %v: %v", quotesFile, err)
+		}
+
+		minLength, _ := cmd.Flags().GetUint16("min-length")
+		maxLength, _ := cmd.Flags().GetUint16("max-length")
 
-		for scanner.Scan() {
-			quote := strings.TrimSpace(scanner.Text())
-			quotes = append(quotes, quote)
+		if maxLength != 0 && minLength > maxLength {
+			return fmt.Errorf("--min-length is greater than --max-length.")
 		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("Error scanning %v: %v", quotesFile, err)
+		quotePool := commons.FilterQuotesByLength(quotes, int(minLength), int(maxLength))
+		if len(quotePool) == 0 {
+			return fmt.Errorf("There are no quotes in %v within the length limits.", quotesFile)
+		}
+
+		if len(quotePool) < int(iterations) {
+			cmd.PrintErrf("Warning: There are only %v quotes available, training with all of them.\n", len(quotePool))
 		}
 
-		randomQuote := quotes[rand.Intn(len(quotes))]
+		random, _ := commons.NewRandom(0)
+		sessionQuotes := commons.PickQuotes(random, quotePool, int(iterations))
 
 		speed, _ := cmd.Flags().GetFloat64("speed")
 		if speed == 0 {
 			return fmt.Errorf("Speed must not be zero.")
 		}
 
-		p := tea.NewProgram(NewQuoteModel(randomQuote, speed, nil))
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		headCopy, _ := cmd.Flags().GetBool("head-copy")
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			if headCopy {
+				return runPlainHeadCopy(plainSession(cmd), sessionQuotes[0], quotes, speed)
+			}
+
+			return runPlainQuotes(plainSession(cmd), "Decode quote training", "Quote", sessionQuotes, 0, speed, nil)
+		}
+
+		var model tea.Model = NewQuoteModel(sessionQuotes[0], speed, playback, nil)
+		if headCopy {
+			model = NewHeadCopyModel(sessionQuotes[0], quotes, speed, playback, nil)
+		}
+
+		if len(sessionQuotes) > 1 {
+			model = NewQuoteSessionModel("Decode quote training", sessionQuotes, 0, speed, playback, nil)
+		}
+
+		p := tea.NewProgram(model)
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("Error running the program: %v", err)
 		}
@@ -71,10 +115,12 @@ The flags should be self-explanatory.
 # How it works
 
 You will be given a long sound clip, which is an encoded morse code sentence. Ctrl+l
-will either stop or play the clip. Ctrl+c will either clear your input or go back.
-Ctrl+s will confirm your input.
+will pause or resume the clip (or restart it once it has ended). Ctrl+left/ctrl+right
+(or pgup/pgdown) will rewind or forward the clip by a word, and ctrl+r will replay the
+word currently being played. Esc will either clear your input or go back. Ctrl+s will
+confirm your input.
 
-===========================================================================================
+=========================================================================================
 Decode quote training
 
 ┃  1 ?????
@@ -83,22 +129,75 @@ Decode quote training
 ┃
 ┃
 ┃
+███████████░░░░░░░░░░░░░░░░░░░░░░░░░░░░░ 0:07/0:26 (paused)
 
-(ctrl+l to stop/restart playing, ctrl+s to confirm answer, ctrl+c/esc to clear or go back)
-===========================================================================================
+(ctrl+l to pause/play, ctrl+left to go back a word, ctrl+right to skip a word, ctrl+r to replay the word,
+ ctrl+d to submit, escape to clear or go back, ctrl+o for the chart, ctrl+c to exit)
+=========================================================================================
 
 At the end of the training session, you will be presented with the correct quote
 played. Use that as learning and/or feedback for the next training session.
 
-=========================================
+=====================================================================
 Decode quote training results
 
    Do all things with love. - Og Mandino
-      ?         ?
->> do nll things_with love    og mandino
 
-(1/28 mistakes) (ctrl+c/esc to go back)
-=========================================
+   do all things with love og mandino
+      ?         -
+>> do nll things_with love og mandino
+
+1 wrong (?), 0 extra (+), 1 missed (-)
+
+(1/28 mistakes, 4/7 words correct) (ctrl+c/esc to go back)
+=====================================================================
+
+The answer is aligned against the quote with the least amount of edits, so a
+missed or an extra letter only counts as one mistake instead of shifting all of
+the following letters. Wrong letters are marked with "?", extra letters with "+"
+and missed letters with "-".
+
+# Sessions
+
+With --iterations, several quotes are trained in one session, without repeating
+a quote. The --min-length and --max-length flags limit the quotes to the ones of
+a certain length (in characters). At the end of the session, the cumulative
+results of all the quotes are shown. Select a quote and press enter to see its
+corrections.
+
+=========================================================
+Decode quote training results (3 quotes, 5/97 mistakes)
+
+ #    Quote                                     Mistakes
+ 1    Do all things with love. - Og Mandino     1/19
+ 2    Independence is happiness. - Susan B...   0/35
+ 3    Where there is love there is life. -...   4/43
+
+(enter to see the corrections, escape to go back, ctrl+c to exit)
+=========================================================
+
+# Head copy
+
+With --head-copy, the input is hidden while the quote plays. Try to understand it
+without writing anything down. Afterwards, answer a few questions about the quote
+(a missing word and the author, if there is one), then write the quote down as you
+remember it.
+
+==========================================================
+Head copy training (question 1 of 2)
+
+Which word is missing? "Do all _____ with love."
+
+  [+] 1. things
+  [ ] 2. wander
+  [ ] 3. happiness
+  [ ] 4. exhausts
+
+(up/down or 1-4 to choose, enter to confirm, esc to go back, ctrl+c to exit)
+==========================================================
+
+The results show the comprehension (questions answered correctly) separately
+from the letter accuracy of what you wrote down.
 
 NOTE:
 - For the convenience and challenge, --speed can be used to slow down or speed
@@ -0,0 +1,293 @@
+package decode
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	diacritics "github.com/Regis24GmbH/go-diacritics"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+const textPositionsFile = "text_positions.json"
+
+func init() {
+	TextCmd.Flags().Float64P("speed", "s", 1, "Speed ratio to train with.")
+	TextCmd.Flags().String("chunk", "sentence", "How to split the text, either 'sentence' or 'paragraph'.")
+	TextCmd.Flags().Bool("markdown", false, "Strip markdown syntax from the text. Implied for .md files.")
+	TextCmd.Flags().Bool("restart", false, "Start from the beginning of the file instead of the saved position.")
+}
+
+var TextCmd = &cobra.Command{
+	Use:   "text [file]",
+	Short: "Train for decoding text from a file or stdin.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		if speed == 0 {
+			return fmt.Errorf("Speed must not be zero.")
+		}
+
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		chunkBy, _ := cmd.Flags().GetString("chunk")
+		if chunkBy != "sentence" && chunkBy != "paragraph" {
+			return fmt.Errorf("--chunk must be either 'sentence' or 'paragraph', got '%v'.", chunkBy)
+		}
+
+		stripMarkdown, _ := cmd.Flags().GetBool("markdown")
+
+		textFile := "-"
+		if len(args) != 0 {
+			textFile = args[0]
+		}
+
+		var textReader io.Reader = os.Stdin
+		fromStdin := textFile == "-"
+
+		plain, _ := cmd.Flags().GetBool("plain")
+		if plain && fromStdin {
+			return fmt.Errorf("Error: The answers are read from stdin with --plain, so the text should be given as a file.")
+		}
+
+		if !fromStdin {
+			file, err := os.Open(textFile)
+			if err != nil {
+				return fmt.Errorf("Error opening %v: %v", textFile, err)
+			}
+
+			defer file.Close()
+			textReader = file
+
+			switch strings.ToLower(filepath.Ext(textFile)) {
+			case ".md", ".markdown":
+				stripMarkdown = true
+			}
+		}
+
+		contents, err := io.ReadAll(textReader)
+		if err != nil {
+			return fmt.Errorf("Error reading %v: %v", textFile, err)
+		}
+
+		text := string(contents)
+		if stripMarkdown {
+			text = StripMarkdown(text)
+		}
+
+		chunks := SplitTextChunks(NormalizeText(text), chunkBy == "paragraph")
+		if len(chunks) == 0 {
+			return fmt.Errorf("There is nothing to train with in %v.", textFile)
+		}
+
+		positions := map[string]int{}
+		positionKey := ""
+		start := 0
+
+		// Printed before a --plain session, or shown by the session model
+		// as the program owns the terminal.
+		notices := []string{}
+
+		if !fromStdin {
+			absPath, err := filepath.Abs(textFile)
+			if err != nil {
+				return fmt.Errorf("Error resolving %v: %v", textFile, err)
+			}
+
+			positionKey = fmt.Sprintf("%v#%v", absPath, chunkBy)
+			if err := commons.LoadStoredJSON(textPositionsFile, &positions); err != nil {
+				notices = append(notices, fmt.Sprintf("Warning: Cannot load the saved positions: %v", err))
+			}
+
+			restart, _ := cmd.Flags().GetBool("restart")
+			if !restart {
+				start = positions[positionKey]
+			}
+
+			if start >= len(chunks) {
+				notices = append(notices, "Info: The file has been finished before, starting from the beginning.")
+				start = 0
+			}
+		}
+
+		onAdvance := func(next int) error {
+			if len(positionKey) == 0 {
+				return nil
+			}
+
+			positions[positionKey] = next
+			if err := commons.SaveStoredJSON(textPositionsFile, positions); err != nil {
+				return fmt.Errorf("Cannot save the position: %v", err)
+			}
+
+			return nil
+		}
+
+		if plain {
+			for _, notice := range notices {
+				cmd.PrintErrln(notice)
+			}
+
+			return runPlainQuotes(plainSession(cmd), "Decode text training", "Part", chunks, start, speed, onAdvance)
+		}
+
+		sessionM := NewQuoteSessionModel("Decode text training", chunks, start, speed, playback, nil)
+		sessionM.onAdvance = onAdvance
+		sessionM.notices = notices
+
+		programOpts := []tea.ProgramOption(nil)
+		if fromStdin {
+			programOpts = append(programOpts, tea.WithInputTTY())
+		}
+
+		p := tea.NewProgram(sessionM, programOpts...)
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'decode text' command gives the user drills to decode real content: a chapter
+of a book, news text, or your own notes. The text is read from a plain text file,
+a markdown file, or from stdin if no file (or '-') is given.
+
+# How it works
+
+The text is split into sentences (or paragraphs with --chunk paragraph), and each
+of them is trained in order the same way as 'decode quotes'. Ctrl+l will either
+stop or play the clip, ctrl+d (or ctrl+s) will confirm your input.
+
+Between each chunk, you can continue with enter or stop with escape.
+
+==================================================
+Decode text training (13 of 254)
+
+12 trained this session (9/402 mistakes so far)
+
+(enter to continue, escape to stop, ctrl+c to exit)
+==================================================
+
+The position in each file is remembered between sessions, so running the same
+command again continues where you left off. Use --restart to start from the
+beginning. Text from stdin is not remembered.
+
+NOTES:
+  - Accented letters are converted to their plain latin counterparts. The digits
+    are played and graded like the letters, the rest of the punctuation is skipped.
+  - For the convenience and challenge, --speed can be used to slow down or speed
+    up the sound being played.`,
+}
+
+var (
+	markdownImagePattern  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLinkPattern   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHTMLPattern   = regexp.MustCompile(`<[^>]+>`)
+	markdownPrefixPattern = regexp.MustCompile(`^\s*(#{1,6}\s+|>\s*|[-*+]\s+|\d+[.)]\s+)+`)
+	markdownRulePattern   = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	paragraphSeparator    = regexp.MustCompile(`\n\s*\n`)
+)
+
+func StripMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	strippedLines := make([]string, 0, len(lines))
+	inCodeBlock := false
+
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inCodeBlock || markdownRulePattern.MatchString(line) {
+			continue
+		}
+
+		line = markdownPrefixPattern.ReplaceAllString(line, "")
+		line = markdownImagePattern.ReplaceAllString(line, "$1")
+		line = markdownLinkPattern.ReplaceAllString(line, "$1")
+		line = markdownHTMLPattern.ReplaceAllString(line, "")
+		line = strings.NewReplacer("**", "", "__", "", "*", "", "`", "").Replace(line)
+
+		strippedLines = append(strippedLines, line)
+	}
+
+	return strings.Join(strippedLines, "\n")
+}
+
+// Converts the text into plain ascii, so that it can be displayed alongside
+// the corrections of the quote training.
+func NormalizeText(text string) string {
+	text = diacritics.Normalize(text)
+	text = strings.NewReplacer(
+		"‘", "'", "’", "'",
+		"“", "\"", "”", "\"",
+		"–", "-", "—", " - ",
+		"…", "...",
+		"\r", "",
+		"\t", " ",
+	).Replace(text)
+
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return -1
+		}
+
+		return r
+	}, text)
+}
+
+func SplitTextChunks(text string, byParagraph bool) []string {
+	paragraphs := paragraphSeparator.Split(text, -1)
+	chunks := []string(nil)
+
+	addChunk := func(chunk string) {
+		chunk = strings.Join(strings.Fields(chunk), " ")
+
+		hasLetters := strings.ContainsFunc(chunk, func(r rune) bool {
+			_, ok := commons.MorseCodeLookup[unicode.ToLower(r)]
+			return ok
+		})
+
+		if hasLetters {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	for _, paragraph := range paragraphs {
+		if byParagraph {
+			addChunk(paragraph)
+			continue
+		}
+
+		sentence := strings.Builder{}
+		runes := []rune(strings.Join(strings.Fields(paragraph), " "))
+
+		for i, r := range runes {
+			sentence.WriteRune(r)
+
+			if r != '.' && r != '!' && r != '?' {
+				continue
+			}
+
+			if i+1 < len(runes) && runes[i+1] != ' ' {
+				continue
+			}
+
+			addChunk(sentence.String())
+			sentence.Reset()
+		}
+
+		addChunk(sentence.String())
+	}
+
+	return chunks
+}
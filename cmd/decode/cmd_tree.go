@@ -0,0 +1,125 @@
+package decode
+
+import (
+	"fmt"
+	"math/rand"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	TreeCmd.Flags().UintP("iterations", "n", 0, "Training iterations.")
+	TreeCmd.Flags().Float64P("speed", "s", 1, "Speed ratio to train with.")
+
+	TreeCmd.Flags().Uint16P("level", "l", 0, fmt.Sprintf(
+		"Level to have for training (same as in 'decode letters'). Max level: %v. Defaults to the whole alphabet.",
+		len(NewLettersPerLevel),
+	))
+	TreeCmd.Flags().String("letters", "", "Custom alphabet pool to train.")
+
+	TreeCmd.MarkFlagsMutuallyExclusive("level", "letters")
+}
+
+var TreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Train for decoding letters by walking the morse code tree.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		letters, _ := cmd.Flags().GetString("letters")
+		if len(letters) != 0 {
+			letters = DedupCleanLetters(letters)
+		}
+
+		if len(letters) == 0 {
+			levelArg, _ := cmd.Flags().GetUint16("level")
+
+			if int(levelArg) > len(NewLettersPerLevel) {
+				cmd.PrintErrf("Warning: Level is at most %v. Will be set to max.\n", len(NewLettersPerLevel))
+				levelArg = uint16(len(NewLettersPerLevel))
+			}
+
+			if levelArg == 0 {
+				letters = "abcdefghijklmnopqrstuvwxyz"
+			}
+
+			for i := range levelArg {
+				letters += NewLettersPerLevel[i]
+			}
+		}
+
+		dedupedLetters := DedupCleanLetters(letters)
+		speed, _ := cmd.Flags().GetFloat64("speed")
+
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		iterations, _ := cmd.Flags().GetUint("iterations")
+		if iterations == 0 {
+			iterations = max(uint(len(dedupedLetters)/2), 3)
+		}
+
+		trainingLetters := ""
+		for range iterations {
+			trainingLetters += string(dedupedLetters[rand.Intn(len(dedupedLetters))])
+		}
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainTree(plainSession(cmd), trainingLetters, dedupedLetters, speed)
+		}
+
+		p := tea.NewProgram(NewTreeModel(trainingLetters, dedupedLetters, speed, playback, nil))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'decode tree' command gives the user drills to decode the morse code alphabet
+by walking the dichotomic morse code tree: a dit goes to the left, a dah goes to the right.
+
+# How it works
+
+For each item, you will be given a sound clip to listen. Starting from the top of the
+tree, take the left (dit) or the right (dah) branch for each element you heard, and
+confirm with enter where you stopped. Up or backspace undoes a step. Space repeats
+the sound.
+
+==============================================================
+Decode tree training (26 letters) (2 of 13)
+(dits to the left, dahs to the right)
+
+               e                               t
+       i               a               n               m
+   s       u       r       w       d       k       g       o
+ h   v   f       l       p   j   b   x   c   y   z   q
+
+> a  .,
+
+(escape to go back, space to repeat sound, left for dit, ...)
+==============================================================
+
+After confirming, the way to the answer is highlighted, along with the wrong turns
+taken. Enter goes to the next item.
+
+At the end of the training session, you will be presented with the branches of the
+tree you went through, the weakest first: the turns (or the stops) you missed the
+most, and how many times you were asked to take them.
+
+=================================================================
+Decode tree training results (26 letters, 13 iterations), weakest branches first:
+
+ From      Going        Mistakes  Tried
+ a .,      dah (right)  2         3
+ (start)   dit (left)   1         9
+ ...
+
+(3/13 mistakes) (escape/enter to go back, ctrl+c to exit)
+=================================================================
+
+NOTES:
+  - Without --level or --letters, the whole alphabet is trained.
+  - The tree can also be browsed with 'Morse code chart' in the menu, or with
+    ctrl+o while training.`,
+}
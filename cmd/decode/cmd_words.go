@@ -1,15 +1,14 @@
 package decode
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/commons"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +30,9 @@ func init() {
 	)
 
 	WordCmd.Flags().String("words", "", "Custom word file to train on. You probably should start by using --level.")
+	WordCmd.Flags().BoolP("continuous", "c", false, "Play the words without stopping, copying behind while they play.")
+	WordCmd.Flags().Uint16("lag", 2, "Words the user can be behind of the stream in --continuous mode.")
+
 	WordCmd.MarkFlagsOneRequired("level", "words")
 	WordCmd.MarkFlagsMutuallyExclusive("w-length", "level")
 }
@@ -76,48 +78,37 @@ var WordCmd = &cobra.Command{
 			wordFile = "(the default word file)"
 		}
 
-		wordPool := []string(nil)
-		scanner := bufio.NewScanner(fileReader)
-
-		scanner.Split(bufio.ScanWords)
-		for scanner.Scan() {
-			word := scanner.Text()
-			word = strings.Map(func(r rune) rune {
-				if r >= 'a' && r <= 'z' {
-					return r
-				}
+		allWords, err := commons.LoadWords(fileReader)
+		if err != nil {
+			return fmt.Errorf("Error reading through %v: %v", wordFile, err)
+		}
 
-				if r >= 'A' && r <= 'Z' {
-					return r
-				}
+		random, _ := commons.NewRandom(0)
+		words := commons.PickWords(random, WordPool(allWords, int(wordLength)), int(iterations))
 
-				if r == '-' {
-					return r
-				}
+		speed, _ := cmd.Flags().GetFloat64("speed")
 
-				return -1
-			}, word)
+		playback, err := playbackFromFlags(cmd)
+		if err != nil {
+			return err
+		}
 
-			if len(word) <= int(wordLength) || int(wordLength) >= len(MaxWordLenPerLevel) {
-				wordPool = append(wordPool, word)
+		continuous, _ := cmd.Flags().GetBool("continuous")
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			if continuous {
+				return fmt.Errorf("Error: --continuous cannot be used with --plain, as the words are typed while they play.")
 			}
-		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("Error reading through %v: %v", wordFile, err)
+			return runPlainWords(plainSession(cmd), words, speed)
 		}
 
-		words := []string(nil)
-		for range min(len(wordPool), int(iterations)) {
-			wordIdx := rand.Intn(len(wordPool))
-			words = append(words, wordPool[wordIdx])
-
-			wordPool[wordIdx] = wordPool[len(wordPool)-1]
-			wordPool = wordPool[:len(wordPool)-1]
+		var model tea.Model = NewWordModel(words, wordLength, speed, playback, nil)
+		if continuous {
+			lagTolerance, _ := cmd.Flags().GetUint16("lag")
+			model = NewCopyBehindModel(words, int(lagTolerance), speed, playback, nil)
 		}
 
-		speed, _ := cmd.Flags().GetFloat64("speed")
-		p := tea.NewProgram(NewWordModel(words, wordLength, speed, nil))
+		p := tea.NewProgram(model)
 
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("Error running the model: %v\n", err)
@@ -161,6 +152,28 @@ Decoding words training results (5 letter limit, 5 iterations):
 (2/5 mistakes) (escape / ctrl+c / enter to go back)
 ================================================================
 
+# Copy behind
+
+With --continuous, the words are played one after another without stopping. Type
+the words while the next ones are playing, a few characters or words behind the
+sound. Ctrl+s will confirm your input. Your transcript is then aligned with the
+words that were sent, showing how many words you were behind when finishing
+each word. The --lag flag sets how many words behind is still acceptable.
+
+=================================================================
+Copy behind training results (5 words, lag tolerance of 2 words):
+
+ #    Sent   Typed  Correct?  Lag
+ 1    egg    egg    yes       0
+ 2    type   tove   no        1
+ 3    smart  smart  yes       3
+ 4    loose  _      no        -
+ 5    part   part   yes       0
+
+3/5 words within the lag tolerance, at most 3 words behind
+(2/5 mistakes) (escape/enter to go back, ctrl+c to exit)
+=================================================================
+
 # Extras
 
 This is the default word length limit if you specify --level/-l:
@@ -177,3 +190,16 @@ NOTE:
 - For the convenience and the challenge, --speed can be used to slow down or speed
 up the sound being played.`,
 }
+
+// The words of the word file up to the word length, or all of them from the
+// length of the last level.
+func WordPool(allWords []string, wordLength int) []string {
+	wordPool := []string(nil)
+	for _, word := range allWords {
+		if len(word) <= wordLength || wordLength >= MaxWordLenPerLevel[len(MaxWordLenPerLevel)-1] {
+			wordPool = append(wordPool, word)
+		}
+	}
+
+	return wordPool
+}
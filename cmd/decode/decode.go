@@ -1,6 +1,13 @@
 package decode
 
 import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
 	"github.com/spf13/cobra"
 )
 
@@ -9,17 +16,219 @@ var Cmd = &cobra.Command{
 	Short: "Drills for decoding the morse code alphabet",
 	Long: `This is the subcommand for decoding the morse code alphabet, from letters to sentences.
 
-These are the three things the user can do in here:
+These are the things the user can do in here:
   - 'dihdah decode letters': Gives the user drills to decode the morse code alphabet.
   - 'dihdah decode words': Gives the user drills to be proficient on decoding morse code words.
   - 'dihdah decode quotes': Gives the user drills to be proficient on decoding morse code sentences.
+  - 'dihdah decode numbers': Gives the user drills to decode numbers, cut numbers and grid locators.
+  - 'dihdah decode text': Gives the user drills to decode a text file or stdin, sentence by sentence.
+  - 'dihdah decode tree': Gives the user drills to decode letters by walking the morse code tree.
+
+Run either 'dihdah decode letters --help', 'dihdah decode words --help',
+'dihdah decode quotes --help', 'dihdah decode numbers --help',
+'dihdah decode text --help', or 'dihdah decode tree --help' for more details.
+
+# Visual output
+
+With --output, the morse code can be shown as a flashing block instead of (or
+along with) the sound, for practising where sound is not an option or for
+training optical signalling. The flashes are timed exactly like the sound.
+
+  --output audio|visual|both   how the morse code is played (default audio)
+  --flash-size n               height of the flashing block in lines (it is twice as wide)
+  --flash-color color          color of the flashing block (hex like "#ffcc00" or an ANSI number,
+                               the one of the --theme by default)
+
+# Plain mode
+
+With --plain, the drills are line by line instead of full screen, for screen
+readers: each prompt is printed on its own line, the answers are typed on a line
+and confirmed with enter (an empty line plays the sound again), and the results
+are told as sentences, without colors or tables. The answers can also be piped
+through stdin.
+
+  $ dihdah decode letters --level 1 --plain
+  Decode letter training, 3 letters, 3 iterations. Type the letter you hear, or press enter to hear it again.
+  Letter 1 of 3.
+  t
+  Correct, t.
+  Letter 2 of 3.
+  e
+  Wrong, it was h, dot dot dot dot. You answered e.
+  ...
+  Results: 2 of 3 letters right. Mistakes: h as e.
+
+The morse code is only played as sound (not flashed or visualized), and the
+--continuous mode of 'decode words' is not available.
+
+# Visualizer
+
+With --visualizer, the dits and dahs can be shown along with the letters, words
+and quotes being played. Start with live and turn it off gradually as you get
+better:
+
+  live     the dits and dahs light up while they play
+  scroll   the signal scrolls from right to left while it plays
+  reveal   the dits and dahs are shown only after answering
+  none     nothing is shown (default)
+
+=======================================================
+Decode word training (5 letter limit) (2 of 5)
+
 
-Run either 'dihdah decode letters --help', 'dihdah decode words --help', or
-'dihdah decode quotes --help' for more details.`,
+... ,,, .., ,. ,..
+> sou
+
+(escape to go back, space to repeat sound, enter to confirm, ctrl+c to exit)
+=======================================================`,
 }
 
 func init() {
+	Cmd.PersistentFlags().String("output", commons.DefaultPlayback.Output.String(), "How to play the morse code: audio, visual or both.")
+	Cmd.PersistentFlags().String("visualizer", commons.DefaultPlayback.Visualizer.String(), "Show the dits and dahs: none, live, scroll, or reveal (after answering).")
+	Cmd.PersistentFlags().Int("flash-size", commons.DefaultPlayback.FlashSize, "Height in lines of the flashing block of the visual output.")
+	Cmd.PersistentFlags().String("flash-color", commons.DefaultPlayback.FlashColor, "Color of the flashing block of the visual output, the one of the theme if not given.")
+	Cmd.PersistentFlags().Bool("plain", false, "Line by line prompts and answers instead of the full screen drills, for screen readers.")
+
 	Cmd.AddCommand(LetterCmd)
 	Cmd.AddCommand(WordCmd)
 	Cmd.AddCommand(QuoteCmd)
+	Cmd.AddCommand(NumberCmd)
+	Cmd.AddCommand(TextCmd)
+	Cmd.AddCommand(TreeCmd)
+}
+
+func playbackFromFlags(cmd *cobra.Command) (commons.Playback, error) {
+	playback := commons.DefaultPlayback
+
+	outputStr, _ := cmd.Flags().GetString("output")
+	output, err := commons.ParseOutputMode(outputStr)
+	if err != nil {
+		return playback, err
+	}
+
+	playback.Output = output
+
+	visualizerStr, _ := cmd.Flags().GetString("visualizer")
+	visualizer, err := commons.ParseVisualizerMode(visualizerStr)
+	if err != nil {
+		return playback, err
+	}
+
+	playback.Visualizer = visualizer
+	playback.FlashSize, _ = cmd.Flags().GetInt("flash-size")
+	playback.FlashColor, _ = cmd.Flags().GetString("flash-color")
+
+	if playback.FlashSize < 1 {
+		return playback, fmt.Errorf("Flash size should be at least 1.")
+	}
+
+	if plain, _ := cmd.Flags().GetBool("plain"); plain && !playback.Audio() {
+		return playback, fmt.Errorf("Error: --plain only plays the morse code as sound, and cannot be used with --output visual.")
+	}
+
+	return playback, nil
+}
+
+// The visual side of playing the morse code: the flashing block and the dit/dah
+// visualizer.
+type morseVisuals struct {
+	playback   commons.Playback
+	flasher    *components.Flasher
+	visualizer *components.Visualizer
+
+	// Of the morse code played by a stream, for flashing along with it.
+	timeline []commons.SignalElement
+}
+
+func newMorseVisuals(playback commons.Playback) *morseVisuals {
+	return &morseVisuals{
+		playback:   playback,
+		flasher:    components.NewFlasher(playback),
+		visualizer: components.NewVisualizer(playback.Visualizer),
+	}
+}
+
+// Plays the morse code as sound (through the replay signal of the player that
+// already has it), along with the visuals.
+func (v *morseVisuals) replay(replaySignal chan<- struct{}, morseCode string, speed float64) tea.Cmd {
+	if v.playback.Audio() {
+		replaySignal <- struct{}{}
+	}
+
+	return v.play(morseCode, speed)
+}
+
+// Plays the visuals of the morse code, that is played from the start somewhere
+// else.
+func (v *morseVisuals) play(morseCode string, speed float64) tea.Cmd {
+	flashingCmd := tea.Cmd(nil)
+	if v.playback.Visual() {
+		flashingCmd = v.flasher.Play(commons.MorseTimeline(morseCode, speed))
+	}
+
+	v.visualizer.Load(morseCode, speed)
+	return tea.Batch(flashingCmd, v.visualizer.Play())
+}
+
+// Loads the morse code played by a seekable stream, to be followed with
+// setPosition.
+func (v *morseVisuals) load(morseCode string, speed float64) {
+	v.timeline = commons.MorseTimeline(morseCode, speed)
+	v.visualizer.Load(morseCode, speed)
+}
+
+func (v *morseVisuals) setPosition(at time.Duration) {
+	if v.playback.Visual() {
+		v.flasher.SetPosition(v.timeline, at)
+	}
+
+	v.visualizer.SetPosition(at)
+}
+
+// Whether the visuals should follow the position of a stream closely.
+func (v *morseVisuals) followsPosition() bool {
+	mode := v.playback.Visualizer
+	return v.playback.Visual() || mode == commons.LiveVisualizer || mode == commons.ScrollVisualizer
+}
+
+// Shows the morse code of the answered item, in the reveal mode.
+func (v *morseVisuals) reveal(label string, morseCode string) {
+	v.visualizer.Reveal(label, morseCode)
+}
+
+func (v *morseVisuals) update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case components.FlashMsg:
+		return v.flasher.Update(msg)
+	case components.VisualizerMsg:
+		return v.visualizer.Update(msg)
+	}
+
+	return nil
+}
+
+// Shows the revealed morse code under the view (of the results), in the reveal
+// mode.
+func (v *morseVisuals) withRevealed(view string) string {
+	if v.playback.Visualizer != commons.RevealVisualizer {
+		return view
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, view, "", v.visualizer.View())
+}
+
+// Shows the visuals above the input.
+func (v *morseVisuals) view(inputView string) string {
+	views := []string{}
+	if v.playback.Visual() {
+		views = append(views, v.flasher.View())
+	}
+
+	if visualizerView := v.visualizer.View(); len(visualizerView) != 0 {
+		views = append(views, visualizerView, "")
+	}
+
+	views = append(views, inputView)
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
 }
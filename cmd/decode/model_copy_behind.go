@@ -0,0 +1,362 @@
+package decode
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gopxl/beep"
+	"github.com/gopxl/beep/speaker"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/ui/chart"
+)
+
+type copyBehindModel struct {
+	backReference tea.Model
+
+	words        []string
+	speed        float64
+	playback     commons.Playback
+	lagTolerance int
+
+	visuals *morseVisuals
+
+	input       textarea.Model
+	startedAt   time.Time
+	wordEnds    []time.Duration
+	typedTimes  []time.Duration
+	streamEnded bool
+
+	showResults  bool
+	resultsTable table.Model
+	corrects     int
+	withinLag    int
+	maxLag       int
+
+	stopSignal chan<- struct{}
+}
+
+type streamEndedMsg struct{}
+
+func NewCopyBehindModel(words []string, lagTolerance int, speed float64, playback commons.Playback, backReference tea.Model) *copyBehindModel {
+	input := textarea.New()
+	input.Placeholder = "?????"
+	input.MaxHeight = 5
+	input.FocusedStyle = textarea.Style{
+		CursorLine: lipgloss.NewStyle(),
+	}
+	input.Focus()
+
+	return &copyBehindModel{
+		backReference: backReference,
+		words:         words,
+		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
+		lagTolerance:  lagTolerance,
+		input:         input,
+	}
+}
+
+func (_m *copyBehindModel) Init() tea.Cmd {
+	var playingCmd tea.Cmd
+	var duration time.Duration
+
+	playingCmd, _m.stopSignal, _m.wordEnds, duration = initPlayingMorseCodeStream(_m.words, _m.speed, _m.playback)
+	_m.startedAt = time.Now()
+
+	visualsCmd := _m.visuals.play(strings.Join(streamMorseCodes(_m.words), ""), _m.speed)
+
+	return tea.Batch(
+		textarea.Blink,
+		playingCmd,
+		visualsCmd,
+		tea.Tick(duration, func(_ time.Time) tea.Msg {
+			return streamEndedMsg{}
+		}),
+	)
+}
+
+// Returns the morse code of each word, with a word gap after all of them but
+// the last.
+func streamMorseCodes(words []string) []string {
+	morseCodes := make([]string, 0, len(words))
+	for i, word := range words {
+		morseCode := commons.ToMorseCode(word)
+		if i != len(words)-1 {
+			morseCode += string(commons.MorseSpaceIndicator)
+		}
+
+		morseCodes = append(morseCodes, morseCode)
+	}
+
+	return morseCodes
+}
+
+// The morse code of the whole quote, as played by 'decode quotes'.
+func QuoteMorseCode(quote string) string {
+	return strings.Join(streamMorseCodes(quoteWords(quote)), "")
+}
+
+// Plays all the words without stopping, returning when each word ends relative
+// to the start of the stream.
+func initPlayingMorseCodeStream(words []string, speed float64, playback commons.Playback) (
+	playingCmd tea.Cmd,
+	stopSignal chan<- struct{},
+	wordEnds []time.Duration,
+	duration time.Duration,
+) {
+	streamBuffer := beep.NewBuffer(commons.AudioFormat)
+	wordEnds = make([]time.Duration, 0, len(words))
+
+	for _, morseCode := range streamMorseCodes(words) {
+		streamBuffer.Append(commons.MorseCharSound(morseCode, speed))
+		wordEnds = append(wordEnds, commons.AudioFormat.SampleRate.D(streamBuffer.Len()))
+	}
+
+	_stopSignal := make(chan struct{})
+	mixer := &beep.Mixer{}
+
+	if playback.Audio() {
+		speaker.Lock()
+		mixer.Add(streamBuffer.Streamer(0, streamBuffer.Len()))
+		speaker.Unlock()
+	}
+
+	playingCmd = func() tea.Msg {
+		speaker.Play(mixer)
+		<-_stopSignal
+
+		speaker.Lock()
+		mixer.Clear()
+		speaker.Unlock()
+
+		return doneMsg{}
+	}
+
+	duration = commons.AudioFormat.SampleRate.D(streamBuffer.Len())
+	return playingCmd, _stopSignal, wordEnds, duration
+}
+
+func (_m *copyBehindModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(strings.Join(_m.words, ""), _m.speed, _m)
+			return chartM, chartM.Init()
+		}
+	}
+
+	if _m.showResults {
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Back, commons.Keys.Confirm):
+				if _m.backReference == nil {
+					return _m, tea.Quit
+				}
+
+				return _m.backReference, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		_m.resultsTable, cmd = _m.resultsTable.Update(msg)
+		return _m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case components.FlashMsg, components.VisualizerMsg:
+		return _m, _m.visuals.update(msg)
+
+	case streamEndedMsg:
+		_m.streamEnded = true
+		return _m, nil
+
+	case tea.KeyMsg:
+		switch {
+		default:
+			runes := []rune(msg.String())
+			if len(runes) != 1 {
+				break
+			}
+
+			char := unicode.ToLower(runes[0])
+			if char == ' ' || char == '-' || (char >= 'a' && char <= 'z') {
+				break
+			}
+
+			return _m, nil
+		case key.Matches(msg, commons.Keys.Confirm):
+			return _m, nil
+		case key.Matches(msg, commons.Keys.Back):
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			close(_m.stopSignal)
+			return _m.backReference, nil
+		case key.Matches(msg, commons.Keys.Submit):
+			_m.recordTypedWords(true)
+			_m.initResults()
+			_m.showResults = true
+
+			close(_m.stopSignal)
+			return _m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	_m.input, cmd = _m.input.Update(msg)
+
+	_m.recordTypedWords(false)
+	return _m, cmd
+}
+
+// Records the time on when each word is finished typing, a word being
+// finished when there is a space after it.
+func (_m *copyBehindModel) recordTypedWords(submitting bool) {
+	value := _m.input.Value()
+	completedWords := len(strings.Fields(value))
+
+	if !submitting && len(value) != 0 && !unicode.IsSpace(rune(value[len(value)-1])) {
+		completedWords -= 1
+	}
+
+	elapsed := time.Since(_m.startedAt)
+	for len(_m.typedTimes) < completedWords {
+		_m.typedTimes = append(_m.typedTimes, elapsed)
+	}
+}
+
+// Counts how many words have been sent completely at the given time.
+func (_m *copyBehindModel) sentWordsAt(elapsed time.Duration) int {
+	sentWords := 0
+	for _, wordEnd := range _m.wordEnds {
+		if wordEnd > elapsed {
+			break
+		}
+
+		sentWords += 1
+	}
+
+	return sentWords
+}
+
+func (_m *copyBehindModel) initResults() {
+	sentWords := make([]string, len(_m.words))
+	for i, word := range _m.words {
+		sentWords[i] = strings.ToLower(word)
+	}
+
+	typedWords := strings.Fields(strings.ToLower(_m.input.Value()))
+
+	// The typed word index for each sent word, or -1 if the word has been missed
+	alignment := make([]int, len(sentWords))
+	for _, step := range commons.Align(sentWords, typedWords) {
+		if step.RealIdx >= 0 {
+			alignment[step.RealIdx] = step.UserIdx
+		}
+	}
+
+	rows := []table.Row{}
+	maxWordWidth := 4
+
+	for i, sentWord := range sentWords {
+		maxWordWidth = max(maxWordWidth, len(sentWord))
+
+		typedIdx := alignment[i]
+		if typedIdx < 0 {
+			rows = append(rows, table.Row{fmt.Sprint(i + 1), sentWord, "_", "no", "-"})
+			continue
+		}
+
+		typedWord := typedWords[typedIdx]
+		maxWordWidth = max(maxWordWidth, len(typedWord))
+
+		correctString := "no"
+		if typedWord == sentWord {
+			correctString = "yes"
+			_m.corrects += 1
+		}
+
+		lag := 0
+		if typedIdx < len(_m.typedTimes) {
+			lag = max(0, _m.sentWordsAt(_m.typedTimes[typedIdx])-(i+1))
+		}
+
+		_m.maxLag = max(_m.maxLag, lag)
+		if lag <= _m.lagTolerance {
+			_m.withinLag += 1
+		}
+
+		rows = append(rows, table.Row{fmt.Sprint(i + 1), sentWord, typedWord, correctString, fmt.Sprint(lag)})
+	}
+
+	columns := []table.Column{
+		{Title: "#", Width: 3},
+		{Title: "Sent", Width: maxWordWidth},
+		{Title: "Typed", Width: maxWordWidth},
+		{Title: "Correct?", Width: 8},
+		{Title: "Lag", Width: 4},
+	}
+
+	_m.resultsTable = table.New(
+		table.WithFocused(true),
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithHeight(min(10, len(rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+}
+
+func (_m *copyBehindModel) View() string {
+	if _m.showResults {
+		total := len(_m.words)
+
+		scoreText := "(all correct!)"
+		if _m.corrects != total {
+			scoreText = fmt.Sprintf("(%v/%v mistakes)", total-_m.corrects, total)
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("Copy behind training results (%v words, lag tolerance of %v words):", total, _m.lagTolerance),
+			"",
+			_m.resultsTable.View(),
+			"",
+			fmt.Sprintf("%v/%v words within the lag tolerance, at most %v words behind", _m.withinLag, total, _m.maxLag),
+			fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Quit)),
+			"",
+		)
+	}
+
+	streamStatus := "(playing...)"
+	if _m.streamEnded {
+		streamStatus = "(done playing)"
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("Copy behind training (%v words) %v", len(_m.words), streamStatus),
+		"",
+		_m.visuals.view(_m.input.View()),
+		"",
+		fmt.Sprintf("(%v)", commons.KeyHelp(
+			commons.WithDesc(commons.Keys.Submit, "to confirm answer"),
+			commons.Keys.Back,
+			commons.Keys.Chart,
+			commons.Keys.Quit,
+		)),
+		"",
+	)
+}
@@ -0,0 +1,418 @@
+package decode
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	diacritics "github.com/Regis24GmbH/go-diacritics"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/ui/chart"
+)
+
+type headCopyPhase int
+
+const (
+	listeningPhase headCopyPhase = iota
+	questionsPhase
+	recallPhase
+	resultsPhase
+)
+
+type comprehensionQuestion struct {
+	Prompt  string
+	Choices []string
+	Answer  int
+
+	userAnswer int
+}
+
+type headCopyModel struct {
+	backReference tea.Model
+
+	quote     string
+	questions []comprehensionQuestion
+	speed     float64
+	playback  commons.Playback
+
+	visuals *morseVisuals
+
+	phase           headCopyPhase
+	currentQuestion int
+	selectedChoice  int
+
+	input textarea.Model
+
+	results    QuoteTrainingResults
+	understood int
+
+	stopSignal chan<- struct{}
+}
+
+func NewHeadCopyModel(quote string, quotePool []string, speed float64, playback commons.Playback, backReference tea.Model) *headCopyModel {
+	input := textarea.New()
+	input.Placeholder = "?????"
+	input.MaxHeight = 5
+	input.FocusedStyle = textarea.Style{
+		CursorLine: lipgloss.NewStyle(),
+	}
+
+	return &headCopyModel{
+		backReference: backReference,
+		quote:         quote,
+		questions:     newComprehensionQuestions(quote, quotePool),
+		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
+		input:         input,
+	}
+}
+
+func (_m *headCopyModel) Init() tea.Cmd {
+	words := strings.Fields(diacritics.Normalize(_m.quote))
+
+	var playingCmd tea.Cmd
+	var duration time.Duration
+	playingCmd, _m.stopSignal, _, duration = initPlayingMorseCodeStream(words, _m.speed, _m.playback)
+
+	visualsCmd := _m.visuals.play(strings.Join(streamMorseCodes(words), ""), _m.speed)
+
+	return tea.Batch(
+		playingCmd,
+		visualsCmd,
+		tea.Tick(duration, func(_ time.Time) tea.Msg {
+			return streamEndedMsg{}
+		}),
+	)
+}
+
+// Splits the "Quote. - Author" format of the quotes file.
+func splitQuoteAuthor(quote string) (text string, author string) {
+	separatorIdx := strings.LastIndex(quote, " - ")
+	if separatorIdx < 0 {
+		return strings.TrimSpace(quote), ""
+	}
+
+	return strings.TrimSpace(quote[:separatorIdx]), strings.TrimSpace(quote[separatorIdx+3:])
+}
+
+func newComprehensionQuestions(quote string, quotePool []string) []comprehensionQuestion {
+	const choiceCount = 4
+
+	text, author := splitQuoteAuthor(quote)
+	questions := []comprehensionQuestion(nil)
+
+	cleanWord := func(word string) string {
+		return strings.ToLower(strings.TrimFunc(diacritics.Normalize(word), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}))
+	}
+
+	quoteWords := strings.Fields(text)
+	candidateIdxs := []int(nil)
+	for i, word := range quoteWords {
+		if len(cleanWord(word)) >= 3 {
+			candidateIdxs = append(candidateIdxs, i)
+		}
+	}
+
+	if len(candidateIdxs) != 0 {
+		missingIdx := candidateIdxs[rand.Intn(len(candidateIdxs))]
+		missingWord := cleanWord(quoteWords[missingIdx])
+
+		distractors := []string(nil)
+		for _, otherQuote := range quotePool {
+			otherText, _ := splitQuoteAuthor(otherQuote)
+			for _, word := range strings.Fields(otherText) {
+				word = cleanWord(word)
+				if len(word) < 3 || word == missingWord || slices.Contains(distractors, word) {
+					continue
+				}
+
+				distractors = append(distractors, word)
+			}
+		}
+
+		blankedWords := slices.Clone(quoteWords)
+		blankedWords[missingIdx] = strings.Repeat("_", 5)
+
+		questions = append(questions, newMultipleChoice(
+			fmt.Sprintf("Which word is missing? \"%v\"", strings.Join(blankedWords, " ")),
+			missingWord,
+			distractors,
+			choiceCount,
+		))
+	}
+
+	if len(author) != 0 {
+		distractors := []string(nil)
+		for _, otherQuote := range quotePool {
+			_, otherAuthor := splitQuoteAuthor(otherQuote)
+			if len(otherAuthor) == 0 || otherAuthor == author || slices.Contains(distractors, otherAuthor) {
+				continue
+			}
+
+			distractors = append(distractors, otherAuthor)
+		}
+
+		questions = append(questions, newMultipleChoice(
+			"Who is the author of the quote?",
+			author,
+			distractors,
+			choiceCount,
+		))
+	}
+
+	return questions
+}
+
+func newMultipleChoice(prompt string, answer string, distractors []string, choiceCount int) comprehensionQuestion {
+	rand.Shuffle(len(distractors), func(i, j int) {
+		distractors[i], distractors[j] = distractors[j], distractors[i]
+	})
+
+	choices := append([]string{answer}, distractors[:min(len(distractors), choiceCount-1)]...)
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+
+	return comprehensionQuestion{
+		Prompt:  prompt,
+		Choices: choices,
+		Answer:  slices.Index(choices, answer),
+	}
+}
+
+func (_m *headCopyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.quote, _m.speed, _m)
+			return chartM, chartM.Init()
+		}
+	}
+
+	switch _m.phase {
+	case listeningPhase:
+		switch msg := msg.(type) {
+		case components.FlashMsg, components.VisualizerMsg:
+			return _m, _m.visuals.update(msg)
+
+		case streamEndedMsg:
+			close(_m.stopSignal)
+
+			_m.phase = questionsPhase
+			if len(_m.questions) == 0 {
+				_m.phase = recallPhase
+				return _m, _m.input.Focus()
+			}
+
+		case tea.KeyMsg:
+			if key.Matches(msg, commons.Keys.Back) {
+				close(_m.stopSignal)
+				return _m.goBack()
+			}
+		}
+
+		return _m, nil
+
+	case questionsPhase:
+		keyMsg, isKey := msg.(tea.KeyMsg)
+		if !isKey {
+			return _m, nil
+		}
+
+		question := &_m.questions[_m.currentQuestion]
+
+		switch {
+		case key.Matches(keyMsg, commons.Keys.Back):
+			return _m.goBack()
+		case key.Matches(keyMsg, commons.Keys.Up):
+			_m.selectedChoice = max(0, _m.selectedChoice-1)
+		case key.Matches(keyMsg, commons.Keys.Down):
+			_m.selectedChoice = min(len(question.Choices)-1, _m.selectedChoice+1)
+		case slices.Contains([]string{"1", "2", "3", "4"}, keyMsg.String()):
+			choice := int(keyMsg.Runes[0] - '1')
+			if choice < len(question.Choices) {
+				_m.selectedChoice = choice
+			}
+		case key.Matches(keyMsg, commons.Keys.Confirm):
+			question.userAnswer = _m.selectedChoice
+			if question.userAnswer == question.Answer {
+				_m.understood += 1
+			}
+
+			_m.selectedChoice = 0
+			_m.currentQuestion += 1
+
+			if _m.currentQuestion >= len(_m.questions) {
+				_m.phase = recallPhase
+				return _m, _m.input.Focus()
+			}
+		}
+
+		return _m, nil
+
+	case recallPhase:
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			default:
+				runes := []rune(keyMsg.String())
+				if len(runes) != 1 {
+					break
+				}
+
+				char := unicode.ToLower(runes[0])
+				if char == ' ' || (char >= 'a' && char <= 'z') {
+					break
+				}
+
+				return _m, nil
+			case key.Matches(keyMsg, commons.Keys.Back):
+				if len(_m.input.Value()) != 0 {
+					_m.input.SetValue("")
+					return _m, nil
+				}
+
+				return _m.goBack()
+			case key.Matches(keyMsg, commons.Keys.Submit):
+				_m.results = InitQuoteTrainingResults(_m.input.Value(), _m.quote)
+				_m.phase = resultsPhase
+				return _m, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		_m.input, cmd = _m.input.Update(msg)
+		return _m, cmd
+
+	case resultsPhase:
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey && key.Matches(keyMsg, commons.Keys.Back, commons.Keys.Confirm) {
+			return _m.goBack()
+		}
+	}
+
+	return _m, nil
+}
+
+func (_m *headCopyModel) goBack() (tea.Model, tea.Cmd) {
+	if _m.backReference == nil {
+		return _m, tea.Quit
+	}
+
+	return _m.backReference, nil
+}
+
+func (_m *headCopyModel) View() string {
+	switch _m.phase {
+	case listeningPhase:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			"Head copy training",
+			"",
+			_m.visuals.view("(listening...) Try to understand the quote without writing it down."),
+			"",
+			fmt.Sprintf("(%v)", commons.KeyHelp(commons.Keys.Back, commons.Keys.Chart, commons.Keys.Quit)),
+			"",
+		)
+
+	case questionsPhase:
+		question := _m.questions[_m.currentQuestion]
+
+		choices := []string{}
+		for i, choice := range question.Choices {
+			selectedStr := " "
+			if i == _m.selectedChoice {
+				selectedStr = "+"
+			}
+
+			choices = append(choices, fmt.Sprintf("  [%v] %v. %v", selectedStr, i+1, choice))
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("Head copy training (question %v of %v)", _m.currentQuestion+1, len(_m.questions)),
+			"",
+			question.Prompt,
+			"",
+			strings.Join(choices, "\n"),
+			"",
+			fmt.Sprintf(
+				"(%v/%v or 1-4 to choose, %v)",
+				commons.Keys.Up.Help().Key,
+				commons.Keys.Down.Help().Key,
+				commons.KeyHelp(commons.Keys.Confirm, commons.Keys.Back, commons.Keys.Quit),
+			),
+			"",
+		)
+
+	case recallPhase:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			"Head copy training (recall)",
+			"",
+			"Write down the quote as you remember it.",
+			_m.input.View(),
+			"",
+			fmt.Sprintf("(%v)", commons.KeyHelp(
+				commons.WithDesc(commons.Keys.Submit, "to confirm answer"),
+				commons.WithDesc(commons.Keys.Back, "to clear or go back"),
+				commons.Keys.Chart,
+				commons.Keys.Quit,
+			)),
+			"",
+		)
+	}
+
+	comprehensionText := "(no questions)"
+	if len(_m.questions) != 0 {
+		comprehensionText = fmt.Sprintf("%v/%v questions answered correctly", _m.understood, len(_m.questions))
+	}
+
+	questionReviews := []string{}
+	for _, question := range _m.questions {
+		correctString := "yes"
+		if question.userAnswer != question.Answer {
+			correctString = "no"
+		}
+
+		questionReviews = append(questionReviews, fmt.Sprintf(
+			"  %v (correct? %v) answered: %v, answer: %v",
+			question.Prompt,
+			correctString,
+			question.Choices[question.userAnswer],
+			question.Choices[question.Answer],
+		))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"Head copy training results",
+		"",
+		fmt.Sprintf("Comprehension: %v", comprehensionText),
+		strings.Join(questionReviews, "\n"),
+		"",
+		fmt.Sprintf(
+			"Letter accuracy: %v/%v letters, %v/%v words correct",
+			_m.results.Corrects,
+			_m.results.Total,
+			_m.results.WordCorrects,
+			_m.results.WordTotal,
+		),
+		_m.results.Display,
+		"",
+		fmt.Sprintf("(%v)", commons.KeyHelp(commons.Keys.Back, commons.Keys.Quit)),
+		"",
+	)
+}
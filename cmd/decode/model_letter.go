@@ -5,6 +5,7 @@ import (
 	"slices"
 	"strconv"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,35 +13,33 @@ import (
 	"github.com/gopxl/beep"
 	"github.com/gopxl/beep/speaker"
 	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
 )
 
 type letterModel struct {
 	backReference    tea.Model
 	wrongRightSorted bool
 
-	drill       *commons.Drill
+	session     *drill.Session
 	lettersUsed string
 	speed       float64
+	playback    commons.Playback
 
+	visuals      *morseVisuals
 	input        textinput.Model
 	resultsTable table.Model
 	rows         []table.Row
 
-	userAnswers []rune
 	showResults bool
-	score       int
 
 	charPlayer   chan<- rune
 	replaySignal chan<- struct{}
 	killSignal   chan<- struct{}
 }
 
-func NewLetterModel(trainingLetters string, lettersUsed string, speed float64, backRef tea.Model) *letterModel {
-	drills := &commons.Drill{
-		Text:    trainingLetters,
-		Correct: make([]bool, len(trainingLetters)),
-	}
-
+func NewLetterModel(trainingLetters string, lettersUsed string, speed float64, playback commons.Playback, backRef tea.Model) *letterModel {
 	input := textinput.New()
 	input.CharLimit = 1
 	input.Width = 4
@@ -49,11 +48,12 @@ func NewLetterModel(trainingLetters string, lettersUsed string, speed float64, b
 
 	return &letterModel{
 		backReference: backRef,
-		drill:         drills,
+		session:       drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckText),
 		input:         input,
 		lettersUsed:   lettersUsed,
-		userAnswers:   make([]rune, len(trainingLetters)),
 		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
 	}
 }
 
@@ -116,43 +116,54 @@ func (_m *letterModel) Init() tea.Cmd {
 	var playingCmd tea.Cmd
 	playingCmd, _m.charPlayer, _m.replaySignal, _m.killSignal = initPlayingMorseCode(_m.speed)
 
-	_m.charPlayer <- rune(_m.drill.Text[_m.drill.Current])
-	_m.replaySignal <- struct{}{}
+	item, _ := _m.session.Next()
+	_m.charPlayer <- rune(item.Text[0])
+	_m.session.Start()
+
+	return tea.Batch(textinput.Blink, playingCmd, _m.replay())
+}
+
+func (_m *letterModel) replay() tea.Cmd {
+	item, ok := _m.session.Next()
+	if !ok {
+		return nil
+	}
 
-	return tea.Batch(textinput.Blink, playingCmd)
+	return _m.visuals.replay(_m.replaySignal, item.MorseCode, _m.speed)
 }
 
 type quitMsg struct{}
 
 func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	drill := _m.drill
-
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
 			if _m.backReference == nil {
 				return _m, tea.Quit
 			}
 
 			close(_m.killSignal)
 			return _m.backReference, nil
-		case "ctrl+c":
+		case key.Matches(msg, commons.Keys.Quit):
 			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.lettersUsed, _m.speed, _m)
+			return chartM, chartM.Init()
 		}
 	}
 
 	if _m.showResults {
-		if key, isKey := msg.(tea.KeyMsg); isKey {
-			switch key.String() {
-			case "enter", "esc":
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Confirm, commons.Keys.Back):
 				if _m.backReference == nil {
 					return _m, tea.Quit
 				}
 
 				close(_m.killSignal)
 				return _m.backReference, nil
-			case "s":
+			case key.Matches(keyMsg, commons.Keys.Sort):
 				_m.resultsTable = _m.toggleSorted()
 			}
 		}
@@ -164,10 +175,10 @@ func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case " ":
-			_m.replaySignal <- struct{}{}
-			return _m, nil
+		switch {
+		case key.Matches(msg, commons.Keys.Replay):
+			_m.session.Replay()
+			return _m, _m.replay()
 		default:
 			keyMsg := msg.Runes
 			if len(keyMsg) != 1 {
@@ -180,55 +191,41 @@ func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return _m, nil
 
-		case "enter":
-			if drill.Current >= len(drill.Text) {
+		case key.Matches(msg, commons.Keys.Confirm):
+			if _m.session.Done() {
 				_m.showResults = true
 				return _m, nil
 			}
 
 			userAnswer := _m.input.Value()
-			currentChar := drill.Text[drill.Current]
-
 			if len(userAnswer) == 0 {
-				_m.replaySignal <- struct{}{}
-				return _m, nil
-			}
-
-			_m.userAnswers[drill.Current] = rune(userAnswer[0])
-			if userAnswer == string(currentChar) {
-				drill.Correct[drill.Current] = true
+				_m.session.Replay()
+				return _m, _m.replay()
 			}
 
-			drill.Current += 1
-			for drill.Current < len(drill.Text) {
-				currentChar := drill.Text[drill.Current]
-				if currentChar >= 'a' && currentChar <= 'z' {
-					break
-				}
-
-				drill.Current += 1
-			}
+			answer := _m.session.Answer(userAnswer)
+			_m.visuals.reveal(answer.Item.Text, answer.Item.MorseCode)
 
-			if drill.Current >= len(drill.Text) {
+			next, ok := _m.session.Next()
+			if !ok {
 				close(_m.charPlayer)
 				_m.killSignal <- struct{}{}
 
 				_m.rows = _m.initResultsTable()
 				_m.wrongRightSorted = true
 				_m.resultsTable = _m.toggleSorted()
-
-				_m.score, _ = countCorrectLetters(drill.Text, drill.Correct)
 				_m.showResults = true
 
 				return _m, nil
 			}
 
 			_m.input.Reset()
-			_m.charPlayer <- rune(drill.Text[drill.Current])
-			_m.replaySignal <- struct{}{}
+			_m.charPlayer <- rune(next.Text[0])
 
-			return _m, nil
+			return _m, _m.replay()
 		}
+	case components.FlashMsg, components.VisualizerMsg:
+		return _m, _m.visuals.update(msg)
 	case quitMsg:
 		return _m, tea.Quit
 	}
@@ -253,6 +250,7 @@ func (_m *letterModel) toggleSorted() table.Model {
 		table.WithColumns(letterResultsColumns),
 		table.WithRows(_m.rows),
 		table.WithHeight(min(10, len(_m.rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
 	)
 }
 
@@ -282,32 +280,23 @@ func compareRowNums(rowA, rowB table.Row) int {
 }
 
 func (_m letterModel) initResultsTable() []table.Row {
-	drill := _m.drill
-
-	j := 1
 	rows := []table.Row{}
 
-	for i := 0; i < len(drill.Text); i++ {
-		currentChar := rune(drill.Text[i])
-		if currentChar < 'a' || currentChar > 'z' {
-			continue
-		}
-
+	for i, answer := range _m.session.Results().Answers {
 		correctString := "yes"
-		if !drill.Correct[i] {
+		if !answer.Correct {
 			correctString = "no"
 		}
 
 		row := table.Row{
-			fmt.Sprint(j),
-			string(currentChar),
-			commons.MorseCodeLookup[currentChar],
+			fmt.Sprint(i + 1),
+			answer.Item.Text,
+			answer.Item.MorseCode,
 			correctString,
-			string(_m.userAnswers[i]),
+			answer.Given,
 		}
 
 		rows = append(rows, row)
-		j += 1
 	}
 
 	return rows
@@ -321,35 +310,14 @@ var letterResultsColumns = []table.Column{
 	{Title: "Answered", Width: 8},
 }
 
-func countCorrectLetters(text string, correct []bool) (int, error) {
-	if len([]rune(text)) != len(correct) {
-		return -1, fmt.Errorf("Corrects slice is not equal to length of text.")
-	}
-
-	correctCount := 0
-	for i := 0; i < len(text); i++ {
-		currentChar := rune(text[i])
-		if currentChar < 'a' || currentChar > 'z' {
-			continue
-		}
-
-		if correct[i] {
-			correctCount += 1
-		}
-	}
-
-	return correctCount, nil
-}
-
 func (_m *letterModel) View() string {
-	drill := _m.drill
+	current, total := _m.session.Progress()
 	if _m.showResults {
-		iterations := len(drill.Text)
+		results := _m.session.Results()
 
 		scoreText := "(all correct!)"
-		if _m.score != iterations {
-			mistakes := len(drill.Text) - _m.score
-			scoreText = fmt.Sprintf("(%v/%v mistakes)", mistakes, iterations)
+		if !results.AllCorrect() {
+			scoreText = fmt.Sprintf("(%v/%v mistakes)", len(results.Mistakes()), results.Total())
 		}
 
 		return lipgloss.JoinVertical(
@@ -357,12 +325,12 @@ func (_m *letterModel) View() string {
 			fmt.Sprintf(
 				"Decode letter training results (%v letters, %v iterations):",
 				len(_m.lettersUsed),
-				len(drill.Text),
+				total,
 			),
 			"",
 			_m.resultsTable.View(),
 			"",
-			fmt.Sprintf("%v (escape/enter to go back, s to toggle sort, ctrl+c to exit)", scoreText),
+			fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Sort, commons.Keys.Quit)),
 			"",
 		)
 	}
@@ -373,12 +341,15 @@ func (_m *letterModel) View() string {
 		fmt.Sprintf(
 			"Decode letter training (%v letters) (%v of %v)",
 			len(_m.lettersUsed),
-			drill.Current+1,
-			len(drill.Text),
+			current+1,
+			total,
 		),
-		_m.input.View(),
+		_m.visuals.view(_m.input.View()),
 		"",
-		"(escape to go back, space to repeat sound, enter to confirm, ctrl+c to exit)",
+		fmt.Sprintf(
+			"(%v)",
+			commons.KeyHelp(commons.Keys.Back, commons.Keys.Replay, commons.Keys.Confirm, commons.Keys.Chart, commons.Keys.Quit),
+		),
 		"",
 	)
 }
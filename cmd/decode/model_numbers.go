@@ -0,0 +1,398 @@
+package decode
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gopxl/beep"
+	"github.com/gopxl/beep/speaker"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
+)
+
+type numberModel struct {
+	backReference    tea.Model
+	wrongRightSorted bool
+
+	items    []numberItem
+	session  *drill.Session
+	kind     numberKind
+	digits   int
+	speed    float64
+	playback commons.Playback
+
+	visuals *morseVisuals
+
+	input        textinput.Model
+	resultsTable table.Model
+	rows         [][3]table.Row
+
+	showResults bool
+
+	codePlayer   chan<- string
+	replaySignal chan<- struct{}
+	killSignal   chan<- struct{}
+}
+
+func NewNumberModel(items []numberItem, kind numberKind, speed float64, playback commons.Playback, backReference tea.Model) *numberModel {
+	digits := 0
+	for _, item := range items {
+		digits = max(digits, len(item.Value))
+	}
+
+	input := textinput.New()
+	input.CharLimit = 12
+	input.Width = 14
+	input.Placeholder = "??????"
+	input.Focus()
+
+	return &numberModel{
+		backReference: backReference,
+		items:         items,
+		session:       drill.NewSession(numberDrillItems(items), numberCheck(kind)),
+		kind:          kind,
+		digits:        digits,
+		input:         input,
+		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
+	}
+}
+
+func initPlayingMorseCodeSequence(speed float64) (
+	cmd tea.Cmd,
+	codeSignal chan<- string,
+	replaySignal chan<- struct{},
+	killSignal chan<- struct{},
+) {
+	_replaySignal := make(chan struct{}, 16)
+	newCode := make(chan string, 16)
+	_killSignal := make(chan struct{}, 16)
+
+	mixer := &beep.Mixer{}
+	var currentSound *beep.Buffer
+
+	playingCmd := func() tea.Msg {
+		speaker.Play(mixer)
+
+		for {
+			select {
+			case <-_killSignal:
+				speaker.Lock()
+				mixer.Clear()
+				speaker.Unlock()
+
+				return doneMsg{}
+			case morseCode, ok := <-newCode:
+				if !ok {
+					speaker.Lock()
+					mixer.Clear()
+					speaker.Unlock()
+
+					return doneMsg{}
+				}
+
+				currentStreamer := commons.MorseCharSound(morseCode, speed)
+				currentSound = beep.NewBuffer(commons.AudioFormat)
+				currentSound.Append(currentStreamer)
+			case <-_replaySignal:
+				if currentSound == nil {
+					continue
+				}
+
+				speaker.Lock()
+
+				mixer.Clear()
+				mixer.Add(currentSound.Streamer(0, currentSound.Len()))
+
+				speaker.Unlock()
+			}
+		}
+	}
+
+	return playingCmd, newCode, _replaySignal, _killSignal
+}
+
+func (_m *numberModel) Init() tea.Cmd {
+	var playingCmd tea.Cmd
+	playingCmd, _m.codePlayer, _m.replaySignal, _m.killSignal = initPlayingMorseCodeSequence(_m.speed)
+
+	item, _ := _m.session.Next()
+	_m.codePlayer <- item.MorseCode
+	_m.session.Start()
+
+	return tea.Batch(textinput.Blink, playingCmd, _m.replay())
+}
+
+func (_m *numberModel) replay() tea.Cmd {
+	item, ok := _m.session.Next()
+	if !ok {
+		return nil
+	}
+
+	return _m.visuals.replay(_m.replaySignal, item.MorseCode, _m.speed)
+}
+
+func (_m *numberModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			_m.killSignal <- struct{}{}
+			return _m.backReference, nil
+		case key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(drill.Pool(_m.session.Items()), _m.speed, _m)
+			return chartM, chartM.Init()
+		}
+	}
+
+	if _m.showResults {
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Confirm, commons.Keys.Back):
+				if _m.backReference == nil {
+					return _m, tea.Quit
+				}
+
+				_m.killSignal <- struct{}{}
+				return _m.backReference, nil
+			case key.Matches(keyMsg, commons.Keys.Sort):
+				_m.resultsTable = _m.toggleSorted()
+			}
+		}
+
+		var cmd tea.Cmd
+		_m.resultsTable, cmd = _m.resultsTable.Update(msg)
+		return _m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Replay):
+			_m.session.Replay()
+			return _m, _m.replay()
+		default:
+			keyMsg := msg.Runes
+			if len(keyMsg) != 1 {
+				break
+			}
+
+			char := keyMsg[0]
+			if char >= 'A' && char <= 'Z' {
+				char += 'a' - 'A'
+			}
+
+			if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') {
+				break
+			}
+
+			return _m, nil
+
+		case key.Matches(msg, commons.Keys.Confirm):
+			if _m.session.Done() {
+				_m.showResults = true
+				return _m, nil
+			}
+
+			userAnswer := _m.input.Value()
+			if len(userAnswer) == 0 {
+				_m.session.Replay()
+				return _m, _m.replay()
+			}
+
+			current, _ := _m.session.Progress()
+			answer := _m.session.Answer(userAnswer)
+			_m.visuals.reveal(_m.items[current].Sent, answer.Item.MorseCode)
+
+			next, ok := _m.session.Next()
+			if !ok {
+				close(_m.codePlayer)
+
+				_m.rows = _m.initResultsTable()
+				_m.wrongRightSorted = true
+				_m.resultsTable = _m.toggleSorted()
+
+				_m.showResults = true
+				return _m, nil
+			}
+
+			_m.input.Reset()
+			_m.codePlayer <- next.MorseCode
+
+			return _m, _m.replay()
+		}
+
+	case components.FlashMsg, components.VisualizerMsg:
+		return _m, _m.visuals.update(msg)
+
+	case doneMsg:
+		return _m, tea.Tick(time.Second*3, func(_ time.Time) tea.Msg {
+			return quitMsg{}
+		})
+
+	case quitMsg:
+		return _m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	_m.input, cmd = _m.input.Update(msg)
+
+	return _m, cmd
+}
+
+func (_m numberModel) initResultsTable() [][3]table.Row {
+	rows := [][3]table.Row{}
+	maxSentWidth := 4
+	maxInputWidth := 5
+
+	for i, answer := range _m.session.Results().Answers {
+		item := _m.items[i]
+
+		userAnswer := strings.ToLower(answer.Given)
+		if _m.kind == cutNumbers {
+			userAnswer = FromCutNumber(userAnswer)
+		}
+
+		maxSentWidth = max(maxSentWidth, len(item.Sent))
+		maxInputWidth = max(maxInputWidth, len(item.Value), len(userAnswer))
+
+		correctString := "yes"
+		if !answer.Correct {
+			correctString = "no"
+		}
+
+		userDisplayedAnswer, correctionString := correctionRows(item.Value, userAnswer)
+		maxInputWidth = max(maxInputWidth, len(userDisplayedAnswer))
+
+		groupedRows := [3]table.Row{
+			{fmt.Sprint(i + 1), item.Sent, item.Value, correctString, userDisplayedAnswer},
+			{"", "", "", "", correctionString},
+			{"", "", "", "", ""},
+		}
+		rows = append(rows, groupedRows)
+	}
+
+	numberResultsColumns[numberSentIdx].Width = maxSentWidth
+	numberResultsColumns[numberValueIdx].Width = maxInputWidth
+	numberResultsColumns[numberInputIdx].Width = maxInputWidth
+
+	return rows
+}
+
+const (
+	_ int = iota
+	numberSentIdx
+	numberValueIdx
+	numberCorrectIdx
+	numberInputIdx
+)
+
+var numberResultsColumns = []table.Column{
+	{Title: "#", Width: 3},
+	{Title: "Sent"},   // Width: maxSentWidth
+	{Title: "Number"}, // Width: maxInputWidth
+	{Title: "Correct?", Width: 8},
+	{Title: "Input"}, // Width: maxInputWidth
+}
+
+func number_compareCorrectsThenNums(rowA, rowB [3]table.Row) int {
+	if rowA[0][numberCorrectIdx] != rowB[0][numberCorrectIdx] {
+		if rowA[0][numberCorrectIdx] == "no" {
+			return -1
+		} else {
+			return 1
+		}
+	}
+
+	return word_compareRowNums(rowA, rowB)
+}
+
+func (_m *numberModel) toggleSorted() table.Model {
+	_m.wrongRightSorted = !_m.wrongRightSorted
+
+	if _m.wrongRightSorted {
+		slices.SortFunc(_m.rows, number_compareCorrectsThenNums)
+	} else {
+		slices.SortFunc(_m.rows, word_compareRowNums)
+	}
+
+	rows := []table.Row{}
+	for _, r := range _m.rows {
+		rows = append(rows, r[:]...)
+	}
+
+	return table.New(
+		table.WithFocused(true),
+		table.WithColumns(numberResultsColumns),
+		table.WithRows(rows),
+		table.WithHeight(min(10, len(rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+}
+
+func (_m *numberModel) View() string {
+	current, total := _m.session.Progress()
+
+	trainingSpecification := fmt.Sprintf("%v digits", _m.digits)
+	if _m.kind != plainNumbers {
+		trainingSpecification = _m.kind.String()
+	}
+
+	if _m.showResults {
+		results := _m.session.Results()
+		iterations := results.Total()
+
+		scoreText := "(all correct!)"
+		if !results.AllCorrect() {
+			scoreText = fmt.Sprintf("(%v/%v mistakes)", len(results.Mistakes()), iterations)
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf(
+				"Decode number training results (%v, %v iterations):",
+				trainingSpecification,
+				iterations,
+			),
+			"",
+			_m.resultsTable.View(),
+			"",
+			fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Sort, commons.Keys.Quit)),
+			"",
+		)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		fmt.Sprintf(
+			"Decode number training (%v) (%v of %v)",
+			trainingSpecification,
+			current+1,
+			total,
+		),
+		_m.visuals.view(_m.input.View()),
+		"",
+		fmt.Sprintf(
+			"(%v)",
+			commons.KeyHelp(commons.Keys.Back, commons.Keys.Replay, commons.Keys.Confirm, commons.Keys.Chart, commons.Keys.Quit),
+		),
+		"",
+	)
+}
@@ -0,0 +1,275 @@
+package decode
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// Walks through several quotes (or text chunks) in order, one quote training
+// for each of them.
+type quoteSessionModel struct {
+	backReference tea.Model
+
+	title    string
+	quotes   []string
+	speed    float64
+	playback commons.Playback
+
+	start   int
+	current int
+	results []quoteFinishedMsg
+
+	showResults  bool
+	resultsTable table.Model
+	drillDown    int
+
+	// Called after finishing a quote with the index of the next quote to train.
+	// Its error is shown as a warning, before the next quote.
+	onAdvance func(next int) error
+
+	// Shown before the next quote, like the warnings of onAdvance. With
+	// notices to show first, the session waits to be continued before the
+	// first quote.
+	notices []string
+}
+
+type nextQuoteMsg struct{}
+
+func NewQuoteSessionModel(title string, quotes []string, start int, speed float64, playback commons.Playback, backReference tea.Model) *quoteSessionModel {
+	return &quoteSessionModel{
+		backReference: backReference,
+		title:         title,
+		quotes:        quotes,
+		speed:         speed,
+		playback:      playback,
+		start:         start,
+		current:       start,
+		drillDown:     -1,
+	}
+}
+
+func (_m *quoteSessionModel) Init() tea.Cmd {
+	if len(_m.notices) != 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return nextQuoteMsg{}
+	}
+}
+
+func (_m *quoteSessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, isKey := msg.(tea.KeyMsg); isKey && key.Matches(keyMsg, commons.Keys.Quit) {
+		return _m, tea.Quit
+	}
+
+	if _m.showResults {
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			if _m.drillDown >= 0 {
+				if key.Matches(keyMsg, commons.Keys.Back, commons.Keys.Confirm, commons.Keys.Undo) {
+					_m.drillDown = -1
+				}
+
+				return _m, nil
+			}
+
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Back):
+				return _m.goBack()
+			case key.Matches(keyMsg, commons.Keys.Confirm):
+				_m.drillDown = _m.resultsTable.Cursor()
+				return _m, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		_m.resultsTable, cmd = _m.resultsTable.Update(msg)
+		return _m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case nextQuoteMsg:
+		if _m.current >= len(_m.quotes) {
+			return _m, nil
+		}
+
+		_m.notices = nil
+		quoteM := NewQuoteModel(_m.quotes[_m.current], _m.speed, _m.playback, _m)
+		return quoteM, quoteM.Init()
+
+	case quoteFinishedMsg:
+		if msg.aborted {
+			return _m.finishSession()
+		}
+
+		_m.results = append(_m.results, msg)
+		_m.current += 1
+
+		if _m.onAdvance != nil {
+			if err := _m.onAdvance(_m.current); err != nil {
+				_m.notices = append(_m.notices, fmt.Sprintf("Warning: %v", err))
+			}
+		}
+
+		if _m.current >= len(_m.quotes) {
+			return _m.finishSession()
+		}
+
+		return _m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
+			return _m.finishSession()
+		case key.Matches(msg, commons.Keys.Confirm):
+			return _m, func() tea.Msg {
+				return nextQuoteMsg{}
+			}
+		}
+	}
+
+	return _m, nil
+}
+
+// Shows the cumulative results, if there are any.
+func (_m *quoteSessionModel) finishSession() (tea.Model, tea.Cmd) {
+	if len(_m.results) == 0 {
+		return _m.goBack()
+	}
+
+	const maxQuoteWidth = 40
+	rows := []table.Row{}
+
+	for i, result := range _m.results {
+		quote := []rune(_m.quotes[_m.start+i])
+		if len(quote) > maxQuoteWidth {
+			quote = append(quote[:maxQuoteWidth-3], []rune("...")...)
+		}
+
+		rows = append(rows, table.Row{
+			fmt.Sprint(_m.start + i + 1),
+			string(quote),
+			fmt.Sprintf("%v/%v", result.results.Total-result.results.Corrects, result.results.Total),
+			fmt.Sprintf("%v/%v", result.results.WordCorrects, result.results.WordTotal),
+		})
+	}
+
+	_m.resultsTable = table.New(
+		table.WithFocused(true),
+		table.WithColumns([]table.Column{
+			{Title: "#", Width: 4},
+			{Title: "Quote", Width: maxQuoteWidth},
+			{Title: "Mistakes", Width: 8},
+			{Title: "Words", Width: 8},
+		}),
+		table.WithRows(rows),
+		table.WithHeight(min(10, len(rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+
+	_m.showResults = true
+	return _m, nil
+}
+
+func (_m *quoteSessionModel) goBack() (tea.Model, tea.Cmd) {
+	if _m.backReference == nil {
+		return _m, tea.Quit
+	}
+
+	return _m.backReference, nil
+}
+
+func (_m *quoteSessionModel) cumulativeScore() (corrects int, total int) {
+	for _, result := range _m.results {
+		corrects += result.results.Corrects
+		total += result.results.Total
+	}
+
+	return corrects, total
+}
+
+func (_m *quoteSessionModel) View() string {
+	corrects, total := _m.cumulativeScore()
+
+	if _m.showResults {
+		if _m.drillDown >= 0 {
+			result := _m.results[_m.drillDown].results
+
+			return lipgloss.JoinVertical(
+				lipgloss.Left,
+				fmt.Sprintf("%v results (#%v)", _m.title, _m.start+_m.drillDown+1),
+				"",
+				result.Display,
+				"",
+				fmt.Sprintf(
+					"%v (%v)",
+					result.ScoreText(),
+					commons.KeyHelp(commons.WithDesc(commons.Keys.Back, "to go back to the session results"), commons.Keys.Quit),
+				),
+				"",
+			)
+		}
+
+		scoreText := "all correct!"
+		if corrects != total {
+			scoreText = fmt.Sprintf("%v/%v mistakes", total-corrects, total)
+		}
+
+		lines := []string{
+			fmt.Sprintf("%v results (%v quotes, %v)", _m.title, len(_m.results), scoreText),
+			"",
+			_m.resultsTable.View(),
+			"",
+		}
+
+		lines = append(_m.appendNotices(lines),
+			fmt.Sprintf(
+				"(%v)",
+				commons.KeyHelp(commons.WithDesc(commons.Keys.Confirm, "to see the corrections"), commons.Keys.Back, commons.Keys.Quit),
+			),
+			"",
+		)
+
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	scoreText := "(nothing trained yet)"
+	if total != 0 {
+		scoreText = fmt.Sprintf("(%v/%v mistakes so far)", total-corrects, total)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%v (%v of %v)", _m.title, _m.current+1, len(_m.quotes)),
+		"",
+		fmt.Sprintf("%v trained this session %v", len(_m.results), scoreText),
+		"",
+	}
+
+	lines = append(_m.appendNotices(lines),
+		fmt.Sprintf(
+			"(%v)",
+			commons.KeyHelp(
+				commons.WithDesc(commons.Keys.Confirm, "to continue"),
+				commons.WithDesc(commons.Keys.Back, "to stop"),
+				commons.Keys.Quit,
+			),
+		),
+		"",
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// Each notice on its own line, then an empty line, if there are any.
+func (_m *quoteSessionModel) appendNotices(lines []string) []string {
+	if len(_m.notices) == 0 {
+		return lines
+	}
+
+	return append(append(lines, _m.notices...), "")
+}
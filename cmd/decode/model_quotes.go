@@ -2,35 +2,45 @@ package decode
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 	"unicode"
 
-	diacritics "github.com/Regis24GmbH/go-diacritics"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gopxl/beep"
+	"github.com/gopxl/beep/effects"
 	"github.com/gopxl/beep/speaker"
 	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
 )
 
 type quoteModel struct {
 	backReference tea.Model
 
-	drill *commons.Drill
-	speed float64
+	// Of the one quote, answered all at once.
+	session  *drill.Session
+	speed    float64
+	playback commons.Playback
+
+	visuals *morseVisuals
 
 	input       textarea.Model
 	showResults bool
+	results     QuoteTrainingResults
 
-	displayedResults string
-	corrects         int
-	total            int
-
-	toggleSignal chan<- struct{}
+	playbackSignal chan<- playbackAction
+	stream         *commons.SeekableStream
+	wordStarts     []int
+	progress       progress.Model
 }
 
-func NewQuoteModel(quote string, speed float64, backReference tea.Model) *quoteModel {
+func NewQuoteModel(quote string, speed float64, playback commons.Playback, backReference tea.Model) *quoteModel {
 	input := textarea.New()
 	input.Placeholder = "?????"
 	input.MaxHeight = 5
@@ -41,73 +51,112 @@ func NewQuoteModel(quote string, speed float64, backReference tea.Model) *quoteM
 
 	return &quoteModel{
 		backReference: backReference,
-		drill: &commons.Drill{
-			Text:    quote,
-			Correct: make([]bool, len(quote)),
-		},
-		input: input,
-		speed: speed,
+		session:       drill.NewSession(QuoteItems([]string{quote}), CheckQuote),
+		input:         input,
+		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
+		progress: progress.New(
+			progress.WithWidth(input.Width()),
+			progress.WithoutPercentage(),
+			progress.WithSolidFill(commons.CurrentTheme.Colors.Accent),
+		),
 	}
 }
 
+func (_m *quoteModel) quote() drill.Item {
+	return _m.session.Items()[0]
+}
+
 func (_m *quoteModel) Init() tea.Cmd {
+	quote := _m.quote()
+
 	var playingCmd tea.Cmd
-	playingCmd, _m.toggleSignal = initPlayingMorseCodeQuote(_m.drill.Text, _m.speed)
+	playingCmd, _m.playbackSignal, _m.stream, _m.wordStarts = initPlayingMorseCodeQuote(quote.Text, _m.speed, _m.playback)
+
+	_m.visuals.load(quote.MorseCode, _m.speed)
+	_m.session.Start()
 
-	return tea.Sequence(textarea.Blink, playingCmd)
+	return tea.Batch(
+		tea.Sequence(textarea.Blink, playingCmd),
+		_m.tickPlayback(),
+	)
 }
 
-func initPlayingMorseCodeQuote(quote string, speed float64) (tea.Cmd, chan<- struct{}) {
-	cleanedQuote := strings.ToLower(diacritics.Normalize(quote))
-	runes := []rune(cleanedQuote)
+type playbackAction int
+
+const (
+	togglePlayback playbackAction = iota
+	rewindWord
+	forwardWord
+	replayWord
+)
 
-	firstRune := runes[0]
+type playbackTickMsg struct {
+	stream *commons.SeekableStream
+}
 
-	morseCode := ""
-	previouslySpace := false
+func initPlayingMorseCodeQuote(quote string, speed float64, playback commons.Playback) (
+	playingCmd tea.Cmd,
+	playbackSignal chan<- playbackAction,
+	stream *commons.SeekableStream,
+	wordStarts []int,
+) {
+	quoteBuffer := beep.NewBuffer(commons.AudioFormat)
 
-	if firstRune >= 'a' && firstRune <= 'z' {
-		morseCode += commons.MorseCodeLookup[firstRune]
+	for _, morseCode := range streamMorseCodes(quoteWords(quote)) {
+		wordStarts = append(wordStarts, quoteBuffer.Len())
+		quoteBuffer.Append(commons.MorseCharSound(morseCode, speed))
 	}
 
-	for _, r := range runes[1:] {
-		if r < 'a' {
-			r += 'a' - 'A'
-		}
+	_playbackSignal := make(chan playbackAction, 16)
+	stream = commons.NewSeekableStream(quoteBuffer)
 
-		if r < 'a' || r > 'z' {
-			previouslySpace = true
-			continue
-		}
+	// The stream still plays when the output is only visual, to flash along
+	// with its position.
+	mixer := &beep.Mixer{}
+	mixer.Add(&effects.Volume{Streamer: stream, Silent: !playback.Audio()})
 
-		if previouslySpace {
-			morseCode += string(commons.MorseSpaceIndicator)
+	wordEnd := func(wordIdx int) int {
+		if wordIdx+1 < len(wordStarts) {
+			return wordStarts[wordIdx+1]
 		}
 
-		previouslySpace = false
-		morseCode += " " + commons.MorseCodeLookup[r]
+		return stream.Len()
 	}
 
-	toggleSignal := make(chan struct{}, 16)
-	streamer := commons.MorseCharSound(morseCode, speed)
-
-	quoteBuffer := beep.NewBuffer(commons.AudioFormat)
-	quoteBuffer.Append(streamer)
-
-	mixer := &beep.Mixer{}
-	playingCmd := func() tea.Msg {
+	playingCmd = func() tea.Msg {
 		speaker.Play(mixer)
-		for range toggleSignal {
+
+		for action := range _playbackSignal {
 			speaker.Lock()
 
-			if mixer.Len() == 0 {
-				mixer.Add(quoteBuffer.Streamer(0, quoteBuffer.Len()))
-			} else {
-				mixer.Clear()
+			currentWord := currentWordIdx(wordStarts, stream.Position())
+
+			switch action {
+			case togglePlayback:
+				if stream.Ended() {
+					stream.Seek(0)
+					stream.Paused = false
+					break
+				}
+
+				stream.Paused = !stream.Paused
+			case rewindWord:
+				if currentWord > 0 {
+					stream.Seek(wordStarts[currentWord-1])
+				} else {
+					stream.Seek(0)
+				}
+			case forwardWord:
+				stream.Seek(wordEnd(currentWord))
+			case replayWord:
+				if currentWord >= 0 {
+					stream.PlayBetween(wordStarts[currentWord], wordEnd(currentWord))
+				}
 			}
 
 			speaker.Unlock()
-
 		}
 
 		speaker.Lock()
@@ -117,28 +166,55 @@ func initPlayingMorseCodeQuote(quote string, speed float64) (tea.Cmd, chan<- str
 		return doneMsg{}
 	}
 
-	return playingCmd, toggleSignal
+	return playingCmd, _playbackSignal, stream, wordStarts
+}
+
+// Returns the index of the word being played at the position, or -1 if there
+// are no words.
+func currentWordIdx(wordStarts []int, position int) int {
+	idx, found := slices.BinarySearch(wordStarts, position)
+	if !found {
+		idx -= 1
+	}
+
+	if idx < 0 && len(wordStarts) != 0 {
+		return 0
+	}
+
+	return idx
+}
+
+func (_m *quoteModel) tickPlayback() tea.Cmd {
+	// The visuals need the position much more often than the progress bar.
+	interval := time.Millisecond * 100
+	if _m.visuals.followsPosition() {
+		interval = time.Millisecond * 10
+	}
+
+	stream := _m.stream
+	return tea.Tick(interval, func(_ time.Time) tea.Msg {
+		return playbackTickMsg{stream: stream}
+	})
 }
 
 func (_m *quoteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case key.Matches(msg, commons.Keys.Quit):
 			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.quote().Text, _m.speed, _m)
+			return chartM, chartM.Init()
 		}
 	}
 
 	if _m.showResults {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "esc", "enter":
-				if _m.backReference == nil {
-					return _m, tea.Quit
-				}
-
-				return _m.backReference, nil
+			switch {
+			case key.Matches(msg, commons.Keys.Back, commons.Keys.Confirm):
+				return _m.goBack(false)
 			}
 		}
 		return _m, nil
@@ -146,7 +222,7 @@ func (_m *quoteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
+		switch {
 		default:
 			runes := []rune(msg.String())
 			if len(runes) != 1 {
@@ -158,42 +234,61 @@ func (_m *quoteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 
-			if char < 'a' {
-				char += 'a' - 'A'
-			}
-
-			if char >= 'a' && char <= 'z' {
+			if _, ok := commons.MorseCodeLookup[unicode.ToLower(char)]; ok {
 				break
 			}
 
 			return _m, nil
-		case "esc":
+		case key.Matches(msg, commons.Keys.Back):
 			if len(_m.input.Value()) != 0 {
 				_m.input.SetValue("")
 				return _m, nil
 			}
 
-			if _m.backReference == nil {
-				return _m, tea.Quit
-			}
-
-			close(_m.toggleSignal)
-			return _m.backReference, nil
-		case "ctrl+l":
-			_m.toggleSignal <- struct{}{}
+			close(_m.playbackSignal)
+			return _m.goBack(true)
+		case key.Matches(msg, commons.Keys.TogglePlayback):
+			_m.playbackSignal <- togglePlayback
+			return _m, nil
+		case key.Matches(msg, commons.Keys.Rewind):
+			_m.playbackSignal <- rewindWord
+			return _m, nil
+		case key.Matches(msg, commons.Keys.Forward):
+			_m.playbackSignal <- forwardWord
 			return _m, nil
-		case "ctrl+s":
+		case key.Matches(msg, commons.Keys.ReplayWord):
+			_m.session.Replay()
+			_m.playbackSignal <- replayWord
+			return _m, nil
+		case key.Matches(msg, commons.Keys.Submit):
 			if _m.showResults {
 				return _m, nil
 			}
 
-			_m.displayedResults, _m.corrects, _m.total = InitQuoteTrainingResults(_m.input.Value(), _m.drill.Text)
+			answer := _m.session.Answer(_m.input.Value())
+			_m.results = InitQuoteTrainingResults(answer.Given, answer.Item.Text)
+			_m.visuals.reveal("Code:", answer.Item.MorseCode)
 			_m.showResults = true
 
-			close(_m.toggleSignal)
+			close(_m.playbackSignal)
+
+			return _m, nil
+		}
 
+	case playbackTickMsg:
+		if msg.stream != _m.stream {
 			return _m, nil
 		}
+
+		if _m.visuals.followsPosition() {
+			speaker.Lock()
+			position := _m.stream.Position()
+			speaker.Unlock()
+
+			_m.visuals.setPosition(commons.AudioFormat.SampleRate.D(position))
+		}
+
+		return _m, _m.tickPlayback()
 	}
 
 	var cmd tea.Cmd
@@ -201,95 +296,48 @@ func (_m *quoteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return _m, cmd
 }
 
-func InitQuoteTrainingResults(userAnswerStr string, realAnswerStr string) (displayedResults string, corrects int, total int) {
-	userFields := strings.FieldsFunc(userAnswerStr, func(r rune) bool {
-		return !unicode.IsLetter(r)
-	})
-
-	realAnswer := []rune(strings.ToLower(diacritics.Normalize(realAnswerStr)))
-	userAnswer := []rune(strings.ToLower(strings.Join(userFields, " ")))
-
-	correctionString := strings.Builder{}
-	userDisplayedAnswer := strings.Builder{}
-
-	total = 0
-	corrects = 0
-
-	userAnswerIdx := 0
-	encounteredSpace := false
-	extendIncorrectPadding := false
-
-	for _, realRune := range realAnswer {
-		if !unicode.IsLetter(realRune) && !encounteredSpace {
-			if userAnswerIdx >= len(userAnswer) {
-				correctionString.WriteRune('?')
-				userDisplayedAnswer.WriteRune('_')
-				continue
-			}
-
-			encounteredSpace = true
-			userRune := userAnswer[userAnswerIdx]
-
-			if userRune != ' ' {
-				correctionString.WriteRune('?')
-				userDisplayedAnswer.WriteRune('_')
-
-				extendIncorrectPadding = true
-			} else {
-				correctionString.WriteRune(' ')
-				userDisplayedAnswer.WriteRune(' ')
-
-				userAnswerIdx += 1
-			}
-
-			continue
-		}
+// Sent to the back reference when leaving the quote training.
+type quoteFinishedMsg struct {
+	aborted bool
+	results QuoteTrainingResults
+}
 
-		if realRune < 'a' || realRune > 'z' {
-			if extendIncorrectPadding {
-				userDisplayedAnswer.WriteRune('_')
-			} else {
-				userDisplayedAnswer.WriteRune(' ')
-			}
+func (_m *quoteModel) goBack(aborted bool) (tea.Model, tea.Cmd) {
+	if _m.backReference == nil {
+		return _m, tea.Quit
+	}
 
-			correctionString.WriteRune(' ')
-			continue
-		}
+	finishedMsg := quoteFinishedMsg{
+		aborted: aborted,
+		results: _m.results,
+	}
 
-		encounteredSpace = false
-		extendIncorrectPadding = false
-		total += 1
+	return _m.backReference, func() tea.Msg {
+		return finishedMsg
+	}
+}
 
-		if userAnswerIdx >= len(userAnswer) {
-			correctionString.WriteRune('?')
-			userDisplayedAnswer.WriteRune('_')
-			continue
-		}
+func InitQuoteTrainingResults(userAnswerStr string, realAnswerStr string) QuoteTrainingResults {
+	results, realAnswer, userAnswer, steps := GradeQuote(userAnswerStr, realAnswerStr)
 
-		userRune := userAnswer[userAnswerIdx]
-		userDisplayedAnswer.WriteRune(userRune)
+	realRow, markerRow, userRow := commons.RenderAlignment(realAnswer, userAnswer, steps, '_')
+	_results := [3][]rune{[]rune(realRow), []rune(markerRow), []rune(userRow)}
+	resultsBuilder := []string{"   " + realAnswerStr, ""}
 
-		if realRune == userRune {
-			correctionString.WriteRune(' ')
-			corrects += 1
-		} else {
-			correctionString.WriteRune('?')
+	theme := commons.CurrentTheme
+	styledRows := func(width int) []string {
+		return []string{
+			string(_results[0][:width]),
+			commons.StyleAlignmentRow(_results[1][:width], steps[:width], lipgloss.NewStyle(), theme.Wrong()),
+			commons.StyleAlignmentRow(_results[2][:width], steps[:width], theme.Correct(), theme.Wrong()),
 		}
-
-		userAnswerIdx += 1
 	}
 
-	_results := [3]string{realAnswerStr, correctionString.String(), userDisplayedAnswer.String()}
-	resultsBuilder := []string{}
-
 	maxWidth := 40
 	for len(_results[0]) > maxWidth {
 		resultsJoined := lipgloss.JoinVertical(
 			lipgloss.Left,
-			_results[0][:min(maxWidth, len(_results[0]))],
-			_results[1][:min(maxWidth, len(_results[1]))],
-			_results[2][:min(maxWidth, len(_results[2]))],
-			strings.Repeat("-", maxWidth),
+			append(styledRows(maxWidth), strings.Repeat("-", maxWidth))...,
 		)
 		resultsBuilder = append(resultsBuilder, lipgloss.JoinHorizontal(
 			lipgloss.Left,
@@ -300,33 +348,36 @@ func InitQuoteTrainingResults(userAnswerStr string, realAnswerStr string) (displ
 		_results[0] = _results[0][maxWidth:]
 		_results[1] = _results[1][maxWidth:]
 		_results[2] = _results[2][maxWidth:]
+		steps = steps[maxWidth:]
 	}
 
-	resultsJoined := lipgloss.JoinVertical(lipgloss.Left, _results[:]...)
+	resultsJoined := lipgloss.JoinVertical(lipgloss.Left, styledRows(len(_results[0]))...)
 	resultsBuilder = append(resultsBuilder, lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		"   \n   \n>> ",
 		resultsJoined,
 	))
 
-	return lipgloss.JoinVertical(lipgloss.Left, resultsBuilder...), corrects, total
+	resultsBuilder = append(resultsBuilder, "", fmt.Sprintf(
+		"%v wrong (?), %v extra (+), %v missed (-)",
+		results.Substitutions,
+		results.Insertions,
+		results.Deletions,
+	))
+
+	results.Display = lipgloss.JoinVertical(lipgloss.Left, resultsBuilder...)
+	return results
 }
 
 func (_m *quoteModel) View() string {
 	if _m.showResults {
-		scoreText := "(all correct!)"
-		if _m.corrects != _m.total {
-			mistakes := _m.total - _m.corrects
-			scoreText = fmt.Sprintf("(%v/%v mistakes)", mistakes, _m.total)
-		}
-
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
 			"Decode quote training results",
 			"",
-			_m.displayedResults,
+			_m.visuals.withRevealed(_m.results.Display),
 			"",
-			fmt.Sprintf("%v (ctrl+c to exit, escape/enter to go back)", scoreText),
+			fmt.Sprintf("%v (%v)", _m.results.ScoreText(), commons.KeyHelp(commons.Keys.Quit, commons.Keys.Back)),
 			"",
 		)
 	}
@@ -335,9 +386,54 @@ func (_m *quoteModel) View() string {
 		lipgloss.Left,
 		"Decode quote training",
 		"",
-		_m.input.View(),
+		_m.visuals.view(_m.input.View()),
+		_m.progressView(),
 		"",
-		"(ctrl+l to stop/restart playing, ctrl+s to confirm answer, esc to clear or go back, ctrl+c to exit)",
+		fmt.Sprintf("(%v,", commons.KeyHelp(
+			commons.Keys.TogglePlayback,
+			commons.Keys.Rewind,
+			commons.Keys.Forward,
+			commons.Keys.ReplayWord,
+		)),
+		fmt.Sprintf(" %v)", commons.KeyHelp(
+			commons.Keys.Submit,
+			commons.WithDesc(commons.Keys.Back, "to clear or go back"),
+			commons.Keys.Chart,
+			commons.Keys.Quit,
+		)),
 		"",
 	)
 }
+
+func (_m *quoteModel) progressView() string {
+	speaker.Lock()
+	position, length := _m.stream.Position(), _m.stream.Len()
+	paused, ended := _m.stream.Paused, _m.stream.Ended()
+	speaker.Unlock()
+
+	percent := 0.0
+	if length != 0 {
+		percent = float64(position) / float64(length)
+	}
+
+	state := "playing"
+	if ended {
+		state = "ended"
+	} else if paused {
+		state = "paused"
+	}
+
+	sampleRate := commons.AudioFormat.SampleRate
+	return fmt.Sprintf(
+		"%v %v/%v (%v)",
+		_m.progress.ViewAs(percent),
+		formatPlaybackTime(sampleRate.D(position)),
+		formatPlaybackTime(sampleRate.D(length)),
+		state,
+	)
+}
+
+func formatPlaybackTime(duration time.Duration) string {
+	seconds := int(duration.Seconds())
+	return fmt.Sprintf("%v:%02d", seconds/60, seconds%60)
+}
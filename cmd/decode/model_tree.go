@@ -0,0 +1,398 @@
+package decode
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
+)
+
+// The way to take at a node of the tree: a dit, a dah, or stopping there.
+const treeStop = '|'
+
+// A decision at a node of the morse code tree, like "after a dit, go dah".
+type treeBranch struct {
+	from   string
+	toward rune
+}
+
+type branchStats struct {
+	tried    int
+	mistakes int
+}
+
+type treeModel struct {
+	backReference tea.Model
+
+	session     *drill.Session
+	current     drill.Item
+	lettersUsed string
+	speed       float64
+	playback    commons.Playback
+
+	tree    *commons.MorseTreeNode
+	visuals *morseVisuals
+
+	path     string
+	revealed bool
+	branches map[treeBranch]*branchStats
+
+	resultsTable table.Model
+	showResults  bool
+
+	charPlayer   chan<- rune
+	replaySignal chan<- struct{}
+	killSignal   chan<- struct{}
+}
+
+func NewTreeModel(trainingLetters string, lettersUsed string, speed float64, playback commons.Playback, backRef tea.Model) *treeModel {
+	return &treeModel{
+		backReference: backRef,
+		session:       drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckMorseCode),
+		lettersUsed:   lettersUsed,
+		speed:         speed,
+		playback:      playback,
+		tree:          commons.MorseTree(),
+		visuals:       newMorseVisuals(playback),
+		branches:      map[treeBranch]*branchStats{},
+	}
+}
+
+func (_m *treeModel) Init() tea.Cmd {
+	var playingCmd tea.Cmd
+	playingCmd, _m.charPlayer, _m.replaySignal, _m.killSignal = initPlayingMorseCode(_m.speed)
+
+	_m.current, _ = _m.session.Next()
+	_m.charPlayer <- rune(_m.current.Text[0])
+	_m.session.Start()
+
+	return tea.Batch(playingCmd, _m.replay())
+}
+
+// Also plays the letter after it is revealed, until going to the next one.
+func (_m *treeModel) replay() tea.Cmd {
+	return _m.visuals.replay(_m.replaySignal, _m.current.MorseCode, _m.speed)
+}
+
+func (_m *treeModel) currentCode() string {
+	return _m.current.MorseCode
+}
+
+func (_m *treeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			close(_m.killSignal)
+			return _m.backReference, nil
+		case key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.lettersUsed, _m.speed, _m)
+			return chartM, chartM.Init()
+		}
+	}
+
+	if _m.showResults {
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey && key.Matches(keyMsg, commons.Keys.Confirm) {
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			close(_m.killSignal)
+			return _m.backReference, nil
+		}
+
+		var cmd tea.Cmd
+		_m.resultsTable, cmd = _m.resultsTable.Update(msg)
+		return _m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if _m.revealed {
+			switch {
+			case key.Matches(msg, commons.Keys.Replay):
+				return _m, _m.replay()
+			case key.Matches(msg, commons.Keys.Confirm):
+				return _m, _m.next()
+			}
+
+			return _m, nil
+		}
+
+		switch {
+		case key.Matches(msg, commons.Keys.Replay):
+			_m.session.Replay()
+			return _m, _m.replay()
+		case key.Matches(msg, commons.Keys.Left):
+			if _m.tree.Find(_m.path+".") != nil {
+				_m.path += "."
+			}
+		case key.Matches(msg, commons.Keys.Right):
+			if _m.tree.Find(_m.path+",") != nil {
+				_m.path += ","
+			}
+		case key.Matches(msg, commons.Keys.Up, commons.Keys.Undo):
+			if len(_m.path) != 0 {
+				_m.path = _m.path[:len(_m.path)-1]
+			}
+		case key.Matches(msg, commons.Keys.Confirm):
+			if len(_m.path) == 0 {
+				_m.session.Replay()
+				return _m, _m.replay()
+			}
+
+			_m.session.Answer(_m.path)
+			_m.trackBranches(_m.currentCode(), _m.path)
+
+			_m.visuals.reveal(_m.current.Text, _m.currentCode())
+			_m.revealed = true
+		}
+	case components.FlashMsg, components.VisualizerMsg:
+		return _m, _m.visuals.update(msg)
+	}
+
+	return _m, nil
+}
+
+func (_m *treeModel) next() tea.Cmd {
+	_m.path = ""
+	_m.revealed = false
+
+	next, ok := _m.session.Next()
+	if !ok {
+		close(_m.charPlayer)
+		_m.killSignal <- struct{}{}
+
+		_m.resultsTable = _m.initResultsTable()
+		_m.showResults = true
+
+		return nil
+	}
+
+	_m.current = next
+	_m.charPlayer <- rune(next.Text[0])
+
+	return _m.replay()
+}
+
+// Counts the branches taken towards the code, up to the first wrong turn of the
+// path, which is counted as a mistake.
+func (_m *treeModel) trackBranches(code string, path string) {
+	for i := 0; i <= len(code); i++ {
+		branch := treeBranch{from: code[:i], toward: treeStop}
+		if i < len(code) {
+			branch.toward = rune(code[i])
+		}
+
+		stats, ok := _m.branches[branch]
+		if !ok {
+			stats = &branchStats{}
+			_m.branches[branch] = stats
+		}
+
+		stats.tried += 1
+
+		tookBranch := len(path) > i && path[i] == code[i]
+		if i == len(code) {
+			tookBranch = len(path) == len(code)
+		}
+
+		if !tookBranch {
+			stats.mistakes += 1
+			return
+		}
+	}
+}
+
+func (_m *treeModel) initResultsTable() table.Model {
+	branches := slices.Collect(maps.Keys(_m.branches))
+
+	// The weakest branches first
+	slices.SortFunc(branches, func(a, b treeBranch) int {
+		statsA, statsB := _m.branches[a], _m.branches[b]
+		if diff := statsB.mistakes*statsA.tried - statsA.mistakes*statsB.tried; diff != 0 {
+			return diff
+		}
+
+		if diff := statsB.mistakes - statsA.mistakes; diff != 0 {
+			return diff
+		}
+
+		if diff := strings.Compare(a.from, b.from); diff != 0 {
+			return diff
+		}
+
+		return int(a.toward - b.toward)
+	})
+
+	rows := []table.Row{}
+	for _, branch := range branches {
+		stats := _m.branches[branch]
+
+		from := "(start)"
+		if node := _m.tree.Find(branch.from); len(branch.from) != 0 {
+			from = branch.from
+			if node.Char != 0 {
+				from = fmt.Sprintf("%v %v", string(node.Char), branch.from)
+			}
+		}
+
+		toward := "stop"
+		switch branch.toward {
+		case '.':
+			toward = "dit (left)"
+		case ',':
+			toward = "dah (right)"
+		}
+
+		rows = append(rows, table.Row{
+			from,
+			toward,
+			fmt.Sprint(stats.mistakes),
+			fmt.Sprint(stats.tried),
+		})
+	}
+
+	return table.New(
+		table.WithFocused(true),
+		table.WithColumns(treeResultsColumns),
+		table.WithRows(rows),
+		table.WithHeight(min(10, len(rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+}
+
+var treeResultsColumns = []table.Column{
+	{Title: "From", Width: 8},
+	{Title: "Going", Width: 11},
+	{Title: "Mistakes", Width: 8},
+	{Title: "Tried", Width: 5},
+}
+
+func (_m *treeModel) renderNode(node *commons.MorseTreeNode) string {
+	theme := commons.CurrentTheme
+	char := string(node.Char)
+	code := _m.currentCode()
+	onPath := strings.HasPrefix(_m.path, node.Code)
+
+	if _m.revealed {
+		switch {
+		case strings.HasPrefix(code, node.Code):
+			return theme.Correct().Render(char)
+		case onPath:
+			return theme.Wrong().Reverse(true).Render(char)
+		}
+
+		return char
+	}
+
+	switch {
+	case node.Code == _m.path:
+		return theme.Selected().Render(char)
+	case onPath:
+		return theme.Accent().Render(char)
+	}
+
+	return char
+}
+
+func (_m *treeModel) View() string {
+	if _m.showResults {
+		results := _m.session.Results()
+
+		scoreText := "(all correct!)"
+		if !results.AllCorrect() {
+			scoreText = fmt.Sprintf("(%v/%v mistakes)", len(results.Mistakes()), results.Total())
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf(
+				"Decode tree training results (%v letters, %v iterations), weakest branches first:",
+				len(_m.lettersUsed),
+				results.Total(),
+			),
+			"",
+			_m.resultsTable.View(),
+			"",
+			fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Quit)),
+			"",
+		)
+	}
+
+	status := "> (start)"
+	if node := _m.tree.Find(_m.path); len(_m.path) != 0 {
+		char := "(no character)"
+		if node.Char != 0 {
+			char = string(node.Char)
+		}
+
+		status = fmt.Sprintf("> %v  %v", char, _m.path)
+	}
+
+	help := commons.KeyHelp(
+		commons.Keys.Back,
+		commons.Keys.Replay,
+		commons.WithDesc(commons.Keys.Left, "for dit"),
+		commons.WithDesc(commons.Keys.Right, "for dah"),
+		commons.WithDesc(commons.Keys.Up, "to undo"),
+		commons.Keys.Confirm,
+		commons.Keys.Chart,
+		commons.Keys.Quit,
+	)
+	// The answer is counted once it is revealed.
+	current, total := _m.session.Progress()
+	if !_m.revealed {
+		current += 1
+	}
+
+	if _m.revealed {
+		if answer, _ := _m.session.LastAnswer(); answer.Correct {
+			status = fmt.Sprintf("%v (correct!)", status)
+		} else {
+			status = fmt.Sprintf("%v (wrong, it was %v  %v)", status, _m.current.Text, _m.currentCode())
+		}
+
+		help = commons.KeyHelp(
+			commons.Keys.Back,
+			commons.Keys.Replay,
+			commons.WithDesc(commons.Keys.Confirm, "for the next"),
+			commons.Keys.Chart,
+			commons.Keys.Quit,
+		)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		fmt.Sprintf(
+			"Decode tree training (%v letters) (%v of %v)",
+			len(_m.lettersUsed),
+			current,
+			total,
+		),
+		"(dits to the left, dahs to the right)",
+		"",
+		components.RenderMorseTree(_m.tree, 2, _m.renderNode),
+		"",
+		_m.visuals.view(status),
+		"",
+		fmt.Sprintf("(%v)", help),
+		"",
+	)
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,37 +14,34 @@ import (
 	"github.com/gopxl/beep"
 	"github.com/gopxl/beep/speaker"
 	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
 )
 
 type wordModel struct {
 	backReference    tea.Model
 	wrongRightSorted bool
 
-	drills  *commons.TrainingModel
-	speed   float64
-	wordLen uint16
+	session  *drill.Session
+	speed    float64
+	playback commons.Playback
+	wordLen  uint16
+
+	visuals *morseVisuals
 
 	input        textinput.Model
 	resultsTable table.Model
 	rows         [][3]table.Row
 
-	userAnswers []string
 	showResults bool
-	score       int
 
 	wordPlayer   chan<- string
 	replaySignal chan<- struct{}
 	killSignal   chan<- struct{}
 }
 
-func NewWordModel(words []string, wordLen uint16, speed float64, backReference tea.Model) *wordModel {
-	drills := []commons.Drill{}
-
-	for _, word := range words {
-		drill := commons.Drill{Text: word}
-		drills = append(drills, drill)
-	}
-
+func NewWordModel(words []string, wordLen uint16, speed float64, playback commons.Playback, backReference tea.Model) *wordModel {
 	input := textinput.New()
 	input.CharLimit = 20
 	input.Width = 25
@@ -53,14 +50,12 @@ func NewWordModel(words []string, wordLen uint16, speed float64, backReference t
 
 	return &wordModel{
 		backReference: backReference,
-		drills: &commons.TrainingModel{
-			Drills:  drills,
-			Correct: make([]bool, len(drills)),
-		},
-		input:       input,
-		userAnswers: make([]string, len(words)),
-		speed:       speed,
-		wordLen:     wordLen,
+		session:       drill.NewSession(drill.WordItems(words), drill.CheckText),
+		input:         input,
+		speed:         speed,
+		playback:      playback,
+		visuals:       newMorseVisuals(playback),
+		wordLen:       wordLen,
 	}
 }
 
@@ -101,34 +96,7 @@ func initPlayingMorseCodeWords(speed float64) (
 					return doneMsg{}
 				}
 
-				runes := []rune(word)
-
-				firstRune := runes[0]
-				if firstRune < 'a' {
-					firstRune += 'a' - 'A'
-				}
-
-				morseCode := ""
-				if firstRune >= 'a' && firstRune <= 'z' {
-					morseCode += commons.MorseCodeLookup[firstRune]
-				}
-
-				for _, r := range runes[1:] {
-					if r == '-' {
-						morseCode += "-"
-						continue
-					}
-
-					if r < 'a' {
-						r += 'a' - 'A'
-					}
-
-					if r >= 'a' && r <= 'z' {
-						morseCode += " " + commons.MorseCodeLookup[r]
-					}
-				}
-
-				currentStreamer := commons.MorseCharSound(morseCode, speed)
+				currentStreamer := commons.MorseCharSound(drill.WordMorseCode(word), speed)
 				currentSound = beep.NewBuffer(commons.AudioFormat)
 				currentSound.Append(currentStreamer)
 
@@ -158,41 +126,52 @@ func (_m *wordModel) Init() tea.Cmd {
 	var playingCmd tea.Cmd
 	playingCmd, _m.wordPlayer, _m.replaySignal, _m.killSignal = initPlayingMorseCodeWords(_m.speed)
 
-	_m.wordPlayer <- _m.drills.Drills[_m.drills.CurrentDrill].Text
-	_m.replaySignal <- struct{}{}
+	item, _ := _m.session.Next()
+	_m.wordPlayer <- item.Text
+	_m.session.Start()
 
-	return tea.Batch(textinput.Blink, playingCmd)
+	return tea.Batch(textinput.Blink, playingCmd, _m.replay())
 }
 
-func (_m *wordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	drills := _m.drills
+func (_m *wordModel) replay() tea.Cmd {
+	item, ok := _m.session.Next()
+	if !ok {
+		return nil
+	}
+
+	return _m.visuals.replay(_m.replaySignal, item.MorseCode, _m.speed)
+}
 
+func (_m *wordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
 			if _m.backReference == nil {
 				return _m, tea.Quit
 			}
 
 			_m.killSignal <- struct{}{}
 			return _m.backReference, nil
-		case "ctrl+c":
+		case key.Matches(msg, commons.Keys.Quit):
 			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(drill.Pool(_m.session.Items()), _m.speed, _m)
+			return chartM, chartM.Init()
 		}
 	}
 
 	if _m.showResults {
-		if key, isKey := msg.(tea.KeyMsg); isKey {
-			switch key.String() {
-			case "enter", "esc":
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Confirm, commons.Keys.Back):
 				if _m.backReference == nil {
 					return _m, tea.Quit
 				}
 
 				_m.killSignal <- struct{}{}
 				return _m.backReference, nil
-			case "s":
+			case key.Matches(keyMsg, commons.Keys.Sort):
 				_m.resultsTable = _m.toggleSorted()
 			}
 		}
@@ -204,10 +183,10 @@ func (_m *wordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case " ":
-			_m.replaySignal <- struct{}{}
-			return _m, nil
+		switch {
+		case key.Matches(msg, commons.Keys.Replay):
+			_m.session.Replay()
+			return _m, _m.replay()
 		default:
 			keyMsg := msg.Runes
 			if len(keyMsg) != 1 {
@@ -220,53 +199,42 @@ func (_m *wordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return _m, nil
 
-		case "enter":
-			if drills.CurrentDrill >= len(drills.Drills) {
+		case key.Matches(msg, commons.Keys.Confirm):
+			if _m.session.Done() {
 				_m.showResults = true
 				return _m, nil
 			}
 
 			userAnswer := _m.input.Value()
-			currentWord := drills.Drills[drills.CurrentDrill].Text
-
 			if len(userAnswer) == 0 {
-				_m.replaySignal <- struct{}{}
-				return _m, nil
+				_m.session.Replay()
+				return _m, _m.replay()
 			}
 
-			_m.userAnswers[drills.CurrentDrill] = userAnswer
-			if userAnswer == string(currentWord) {
-				drills.Correct[drills.CurrentDrill] = true
-			}
-
-			drills.CurrentDrill += 1
+			answer := _m.session.Answer(userAnswer)
+			_m.visuals.reveal(answer.Item.Text, answer.Item.MorseCode)
 
-			if drills.CurrentDrill >= len(drills.Drills) {
+			next, ok := _m.session.Next()
+			if !ok {
 				close(_m.wordPlayer)
-				correctWords := 0
-				for _, correctAnswer := range _m.drills.Correct {
-					if correctAnswer {
-						correctWords += 1
-					}
-				}
 
 				_m.rows = _m.initResultsTable()
 				_m.wrongRightSorted = true
 				_m.resultsTable = _m.toggleSorted()
-
-				_m.score = correctWords
 				_m.showResults = true
 
 				return _m, nil
 			}
 
 			_m.input.Reset()
-			_m.wordPlayer <- drills.Drills[drills.CurrentDrill].Text
-			_m.replaySignal <- struct{}{}
+			_m.wordPlayer <- next.Text
 
-			return _m, nil
+			return _m, _m.replay()
 		}
 
+	case components.FlashMsg, components.VisualizerMsg:
+		return _m, _m.visuals.update(msg)
+
 	case doneMsg:
 		return _m, tea.Tick(time.Second*3, func(_ time.Time) tea.Msg {
 			return quitMsg{}
@@ -283,50 +251,26 @@ func (_m *wordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (_m wordModel) initResultsTable() [][3]table.Row {
-	drills := _m.drills
-
 	rows := [][3]table.Row{}
 	maxWordWidth := 4
 	maxUserWordWidth := 5
 
-	for i, drill := range drills.Drills {
-		maxWordWidth = max(maxWordWidth, len(drill.Text))
-		maxUserWordWidth = max(maxUserWordWidth, len(_m.userAnswers[i]))
+	for i, answer := range _m.session.Results().Answers {
+		word := answer.Item.Text
+		maxWordWidth = max(maxWordWidth, len(word))
+		maxUserWordWidth = max(maxUserWordWidth, len(answer.Given))
 
 		correctString := "yes"
-		if !drills.Correct[i] {
+		if !answer.Correct {
 			correctString = "no"
 		}
 
-		userAnswer := _m.userAnswers[i]
-		realAnswer := []rune(drill.Text)
-
-		correctionString := ""
-		for i, userRune := range userAnswer {
-			if i >= len(realAnswer) {
-				correctionString += "+"
-				continue
-			}
-
-			realRune := realAnswer[i]
-			if realRune == userRune {
-				correctionString += " "
-			} else {
-				correctionString += "?"
-			}
-		}
-
-		userDisplayedAnswer := userAnswer
-		missingLetters := len(drill.Text) - len(userAnswer)
-
-		if missingLetters > 0 {
-			userDisplayedAnswer += strings.Repeat("_", missingLetters)
-			correctionString += strings.Repeat("?", missingLetters)
-		}
+		userDisplayedAnswer, correctionString := correctionRows(word, answer.Given)
+		maxUserWordWidth = max(maxUserWordWidth, len(userDisplayedAnswer))
 
 		firstRow := table.Row{
 			fmt.Sprint(i + 1),
-			drill.Text,
+			word,
 			correctString,
 			userDisplayedAnswer,
 		}
@@ -348,7 +292,7 @@ func (_m wordModel) initResultsTable() [][3]table.Row {
 	}
 
 	wordResultsColumns[wordWidthIdx].Width = maxWordWidth
-	wordResultsColumns[inputStrIdx].Width = maxWordWidth
+	wordResultsColumns[inputStrIdx].Width = max(maxWordWidth, maxUserWordWidth)
 
 	return rows
 }
@@ -411,11 +355,12 @@ func (_m *wordModel) toggleSorted() table.Model {
 		table.WithColumns(wordResultsColumns),
 		table.WithRows(rows),
 		table.WithHeight(min(10, len(_m.rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
 	)
 }
 
 func (_m *wordModel) View() string {
-	drills := _m.drills
+	current, total := _m.session.Progress()
 
 	trainingSpecification := fmt.Sprintf("%v letter limit", _m.wordLen)
 	if _m.wordLen == 0 {
@@ -423,12 +368,24 @@ func (_m *wordModel) View() string {
 	}
 
 	if _m.showResults {
-		iterations := len(drills.Drills)
+		results := _m.session.Results()
 
 		scoreText := "(all correct!)"
-		if _m.score != iterations {
-			mistakes := len(drills.Drills) - _m.score
-			scoreText = fmt.Sprintf("(%v/%v mistakes)", mistakes, iterations)
+		if !results.AllCorrect() {
+			letterCorrects, letterTotal := 0, 0
+			for _, answer := range results.Answers {
+				corrects, total := countAlignedLetters(answer.Item.Text, answer.Given)
+				letterCorrects += corrects
+				letterTotal += total
+			}
+
+			scoreText = fmt.Sprintf(
+				"(%v/%v mistakes, %v/%v letters correct)",
+				len(results.Mistakes()),
+				results.Total(),
+				letterCorrects,
+				letterTotal,
+			)
 		}
 
 		return lipgloss.JoinVertical(
@@ -436,12 +393,12 @@ func (_m *wordModel) View() string {
 			fmt.Sprintf(
 				"Decode word training results (%v, %v iterations):",
 				trainingSpecification,
-				len(drills.Drills),
+				results.Total(),
 			),
 			"",
 			_m.resultsTable.View(),
 			"",
-			fmt.Sprintf("%v (escape/enter to go back, s to toggle sort, ctrl+c to exit)", scoreText),
+			fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Sort, commons.Keys.Quit)),
 			"",
 		)
 	}
@@ -452,12 +409,15 @@ func (_m *wordModel) View() string {
 		fmt.Sprintf(
 			"Decode word training (%v) (%v of %v)",
 			trainingSpecification,
-			drills.CurrentDrill+1,
-			len(drills.Drills),
+			current+1,
+			total,
 		),
-		_m.input.View(),
+		_m.visuals.view(_m.input.View()),
 		"",
-		"(escape to go back, space to repeat sound, enter to confirm, ctrl+c to exit)",
+		fmt.Sprintf(
+			"(%v)",
+			commons.KeyHelp(commons.Keys.Back, commons.Keys.Replay, commons.Keys.Confirm, commons.Keys.Chart, commons.Keys.Quit),
+		),
 		"",
 	)
 }
@@ -0,0 +1,323 @@
+package decode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/spf13/cobra"
+)
+
+// The --plain mode of the drills, line by line for screen readers. The items
+// and the grading are the same as the ones of the models.
+
+func plainSession(cmd *cobra.Command) *commons.PlainSession {
+	return commons.NewPlainSession(cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+func playAt(speed float64, morseCode string) func() {
+	return func() {
+		commons.PlayMorseCode(morseCode, speed, commons.DefaultPitch)
+	}
+}
+
+// Asks for the answer to the current item of the drill, playing it first. The
+// plays after the first one are counted as replays.
+func askItem(session *commons.PlainSession, drills *drill.Session, prompt string, speed float64) (answer string, ok bool) {
+	item, _ := drills.Next()
+	played := false
+
+	drills.Show()
+
+	return session.AskOrReplay(prompt, func() {
+		if played {
+			drills.Replay()
+		}
+
+		played = true
+		commons.PlayMorseCode(item.MorseCode, speed, commons.DefaultPitch)
+	})
+}
+
+func runPlainLetters(session *commons.PlainSession, trainingLetters string, lettersUsed string, speed float64) error {
+	drills := drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckText)
+	_, total := drills.Progress()
+
+	session.Say(
+		"Decode letter training, %v letters, %v iterations. Type the letter you hear, or press enter to hear it again.",
+		len(lettersUsed),
+		total,
+	)
+
+	for !drills.Done() {
+		current, _ := drills.Progress()
+
+		userAnswer, ok := askItem(session, drills, fmt.Sprintf("Letter %v of %v.", current+1, total), speed)
+		if !ok {
+			break
+		}
+
+		answer := drills.Answer(userAnswer)
+		if answer.Correct {
+			session.Say("Correct, %v.", answer.Item.Text)
+			continue
+		}
+
+		session.Say("Wrong, it was %v, %v. You answered %v.", answer.Item.Text, commons.SpellMorseCode(answer.Item.MorseCode), answer.Given)
+	}
+
+	sayResults(session, "letters", drills.Results(), givenAnswer)
+	return session.Err()
+}
+
+func runPlainWords(session *commons.PlainSession, words []string, speed float64) error {
+	drills := drill.NewSession(drill.WordItems(words), drill.CheckText)
+	_, total := drills.Progress()
+
+	session.Say(
+		"Decode word training, %v words. Type the word you hear, or press enter to hear it again.",
+		total,
+	)
+
+	for !drills.Done() {
+		current, _ := drills.Progress()
+
+		userAnswer, ok := askItem(session, drills, fmt.Sprintf("Word %v of %v.", current+1, total), speed)
+		if !ok {
+			break
+		}
+
+		answer := drills.Answer(strings.ToLower(userAnswer))
+		if answer.Correct {
+			session.Say("Correct, %v.", answer.Item.Text)
+			continue
+		}
+
+		corrects, letters := countAlignedLetters(answer.Item.Text, answer.Given)
+		session.Say(
+			"Wrong, it was %v. You answered %v, with %v letters right.",
+			answer.Item.Text,
+			answer.Given,
+			commons.CountOf(corrects, letters),
+		)
+	}
+
+	sayResults(session, "words", drills.Results(), givenAnswer)
+	return session.Err()
+}
+
+func runPlainNumbers(session *commons.PlainSession, items []numberItem, kind numberKind, speed float64) error {
+	drills := drill.NewSession(numberDrillItems(items), numberCheck(kind))
+	_, total := drills.Progress()
+
+	session.Say(
+		"Decode %v training, %v iterations. Type what you hear, or press enter to hear it again.",
+		kind,
+		total,
+	)
+
+	for !drills.Done() {
+		current, _ := drills.Progress()
+		item := items[current]
+
+		userAnswer, ok := askItem(session, drills, fmt.Sprintf("Item %v of %v.", current+1, total), speed)
+		if !ok {
+			break
+		}
+
+		if drills.Answer(userAnswer).Correct {
+			session.Say("Correct, %v.", item.Value)
+			continue
+		}
+
+		sent := ""
+		if item.Sent != item.Value {
+			sent = fmt.Sprintf(", sent as %v", item.Sent)
+		}
+
+		session.Say("Wrong, it was %v%v. You answered %v.", item.Value, sent, userAnswer)
+	}
+
+	sayResults(session, "items", drills.Results(), givenAnswer)
+	return session.Err()
+}
+
+func runPlainTree(session *commons.PlainSession, trainingLetters string, lettersUsed string, speed float64) error {
+	drills := drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckMorseCode)
+	_, total := drills.Progress()
+
+	session.Say(
+		"Decode tree training, %v letters, %v iterations. Type the path down the morse code tree to the letter you hear, "+
+			"with a dot for each left turn and a dash for each right turn. Press enter to hear it again.",
+		len(lettersUsed),
+		total,
+	)
+
+	for !drills.Done() {
+		current, _ := drills.Progress()
+
+		userAnswer, ok := askItem(session, drills, fmt.Sprintf("Letter %v of %v.", current+1, total), speed)
+		if !ok {
+			break
+		}
+
+		path := strings.ReplaceAll(strings.ReplaceAll(userAnswer, " ", ""), "-", ",")
+		answer := drills.Answer(path)
+		spelledCode := commons.SpellMorseCode(answer.Item.MorseCode)
+
+		if answer.Correct {
+			session.Say("Correct, %v is %v.", answer.Item.Text, spelledCode)
+			continue
+		}
+
+		session.Say("Wrong, it was %v, %v. You went %v.", answer.Item.Text, spelledCode, commons.SpellMorseCode(path))
+	}
+
+	sayResults(session, "letters", drills.Results(), func(answer drill.Answer) string {
+		return commons.SpellMorseCode(answer.Given)
+	})
+
+	return session.Err()
+}
+
+// For the quotes and the texts, each quote graded like in 'decode quotes'.
+// onAdvance (if any) is called with the index of the next quote, after each
+// quote is answered. Its error is said as a warning.
+func runPlainQuotes(session *commons.PlainSession, title string, itemName string, quotes []string, start int, speed float64, onAdvance func(next int) error) error {
+	session.Say(
+		"%v, %v %vs. Type what you hear on one line, or press enter to hear it again.",
+		title,
+		len(quotes)-start,
+		strings.ToLower(itemName),
+	)
+
+	corrects, total, answered := 0, 0, 0
+
+	for i := start; i < len(quotes); i++ {
+		quote := quotes[i]
+		morseCode := QuoteMorseCode(quote)
+
+		answer, ok := session.AskOrReplay(fmt.Sprintf("%v %v of %v.", itemName, i+1, len(quotes)), playAt(speed, morseCode))
+		if !ok {
+			break
+		}
+
+		results, _, _, _ := GradeQuote(answer, quote)
+		corrects, total, answered = corrects+results.Corrects, total+results.Total, answered+1
+
+		session.Say("It was: %v", quote)
+		session.Say("%v", quoteResultsSentence(results))
+
+		if onAdvance != nil {
+			if err := onAdvance(i + 1); err != nil {
+				session.Say("Warning: %v", err)
+			}
+		}
+	}
+
+	session.Say("Results: %v %vs, with %v letters right.", answered, strings.ToLower(itemName), commons.CountOf(corrects, total))
+	return session.Err()
+}
+
+func runPlainHeadCopy(session *commons.PlainSession, quote string, quotePool []string, speed float64) error {
+	session.Say("Head copy training. Listen to the quote without writing it down, then answer the questions about it.")
+	playAt(speed, QuoteMorseCode(quote))()
+
+	questions := newComprehensionQuestions(quote, quotePool)
+	understood := 0
+
+	for i, question := range questions {
+		choices := []string{}
+		for j, choice := range question.Choices {
+			choices = append(choices, fmt.Sprintf("%v: %v", j+1, choice))
+		}
+
+		prompt := fmt.Sprintf(
+			"Question %v of %v. %v Type the number of the answer. %v.",
+			i+1,
+			len(questions),
+			question.Prompt,
+			strings.Join(choices, ", "),
+		)
+
+		for {
+			answer, ok := session.Ask(prompt)
+			if !ok {
+				return session.Err()
+			}
+
+			choice, err := strconv.Atoi(answer)
+			if err != nil || choice < 1 || choice > len(question.Choices) {
+				prompt = fmt.Sprintf("Type a number from 1 to %v.", len(question.Choices))
+				continue
+			}
+
+			if choice-1 == question.Answer {
+				understood += 1
+				session.Say("Correct.")
+			} else {
+				session.Say("Wrong, it was %v.", question.Choices[question.Answer])
+			}
+
+			break
+		}
+	}
+
+	answer, ok := session.Ask("Now type the quote as you remember it, on one line.")
+	if !ok {
+		return session.Err()
+	}
+
+	results, _, _, _ := GradeQuote(answer, quote)
+	session.Say("It was: %v", quote)
+	session.Say("%v", quoteResultsSentence(results))
+
+	if len(questions) != 0 {
+		session.Say("Results: %v questions right.", commons.CountOf(understood, len(questions)))
+	}
+
+	return session.Err()
+}
+
+func quoteResultsSentence(results QuoteTrainingResults) string {
+	if results.AllCorrect() {
+		return "All correct!"
+	}
+
+	return fmt.Sprintf(
+		"%v letters and %v words right, with %v wrong, %v extra and %v missed letters.",
+		commons.CountOf(results.Corrects, results.Total),
+		commons.CountOf(results.WordCorrects, results.WordTotal),
+		results.Substitutions,
+		results.Insertions,
+		results.Deletions,
+	)
+}
+
+func givenAnswer(answer drill.Answer) string {
+	return answer.Given
+}
+
+// Tells the score, and the mistakes like "h as e", with the given answers
+// told by tellGiven.
+func sayResults(session *commons.PlainSession, itemsName string, results drill.Results, tellGiven func(answer drill.Answer) string) {
+	if results.Total() == 0 {
+		session.Say("Nothing was answered.")
+		return
+	}
+
+	score := commons.CountOf(results.Score, results.Total())
+	if results.AllCorrect() {
+		session.Say("Results: %v %v right. All correct!", score, itemsName)
+		return
+	}
+
+	mistakes := []string{}
+	for _, answer := range results.Mistakes() {
+		mistakes = append(mistakes, fmt.Sprintf("%v as %v", answer.Item.Text, tellGiven(answer)))
+	}
+
+	session.Say("Results: %v %v right. Mistakes: %v.", score, itemsName, strings.Join(mistakes, ", "))
+}
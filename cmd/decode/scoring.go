@@ -0,0 +1,133 @@
+package decode
+
+import (
+	"fmt"
+	"strings"
+
+	diacritics "github.com/Regis24GmbH/go-diacritics"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+// The grading of the drills beyond right or wrong (see the drill package),
+// shared by the models and the --plain mode.
+
+// Returns the user answer with gaps for the missed characters, and the markers
+// of the mistakes to be displayed under it.
+func correctionRows(realAnswer string, userAnswer string) (userDisplayedAnswer string, correctionString string) {
+	realRunes := []rune(strings.ToLower(realAnswer))
+	userRunes := []rune(strings.ToLower(userAnswer))
+
+	steps := commons.Align(realRunes, userRunes)
+	_, correctionString, userDisplayedAnswer = commons.RenderAlignment(realRunes, userRunes, steps, '_')
+
+	return userDisplayedAnswer, correctionString
+}
+
+// Counts the correct letters of the answers, after aligning them.
+func countAlignedLetters(realAnswer string, userAnswer string) (corrects int, total int) {
+	realRunes := []rune(strings.ToLower(realAnswer))
+	userRunes := []rune(strings.ToLower(userAnswer))
+
+	for _, step := range commons.Align(realRunes, userRunes) {
+		if step.Op == commons.AlignMatch {
+			corrects += 1
+		}
+	}
+
+	return corrects, len(realRunes)
+}
+
+type QuoteTrainingResults struct {
+	Display string
+
+	// Letter accuracy
+	Corrects int
+	Total    int
+
+	// Word accuracy
+	WordCorrects int
+	WordTotal    int
+
+	Substitutions int
+	Insertions    int
+	Deletions     int
+}
+
+func (results QuoteTrainingResults) AllCorrect() bool {
+	return results.Corrects == results.Total && results.Insertions == 0
+}
+
+func (results QuoteTrainingResults) ScoreText() string {
+	if results.AllCorrect() {
+		return "(all correct!)"
+	}
+
+	return fmt.Sprintf(
+		"(%v/%v mistakes, %v/%v words correct)",
+		results.Total-results.Corrects,
+		results.Total,
+		results.WordCorrects,
+		results.WordTotal,
+	)
+}
+
+func quoteWords(str string) []string {
+	return commons.MorseWords(diacritics.Normalize(str))
+}
+
+// The quotes to decode, with the morse code played for them.
+func QuoteItems(quotes []string) []drill.Item {
+	items := []drill.Item{}
+	for _, quote := range quotes {
+		items = append(items, drill.Item{Text: quote, MorseCode: QuoteMorseCode(quote)})
+	}
+
+	return items
+}
+
+// The answer has all of the letters of the quote, graded like GradeQuote.
+func CheckQuote(item drill.Item, answer string) bool {
+	results, _, _, _ := GradeQuote(answer, item.Text)
+	return results.AllCorrect()
+}
+
+// Grades the answer by its letters and by its words, without the case, the
+// punctuation and the diacritics. Also returns the letters that were aligned,
+// for displaying them.
+func GradeQuote(userAnswerStr string, realAnswerStr string) (results QuoteTrainingResults, realAnswer []rune, userAnswer []rune, steps []commons.AlignStep) {
+	realWords := quoteWords(realAnswerStr)
+	userWords := quoteWords(userAnswerStr)
+
+	realAnswer = []rune(strings.Join(realWords, " "))
+	userAnswer = []rune(strings.Join(userWords, " "))
+
+	steps = commons.Align(realAnswer, userAnswer)
+	ops := commons.CountAlignOps(steps)
+
+	results = QuoteTrainingResults{
+		WordTotal:     len(realWords),
+		Substitutions: ops[commons.AlignSubstitution],
+		Insertions:    ops[commons.AlignInsertion],
+		Deletions:     ops[commons.AlignDeletion],
+	}
+
+	for _, step := range steps {
+		if step.RealIdx < 0 || realAnswer[step.RealIdx] == ' ' {
+			continue
+		}
+
+		results.Total += 1
+		if step.Op == commons.AlignMatch {
+			results.Corrects += 1
+		}
+	}
+
+	for _, step := range commons.Align(realWords, userWords) {
+		if step.Op == commons.AlignMatch {
+			results.WordCorrects += 1
+		}
+	}
+
+	return results, realAnswer, userAnswer, steps
+}
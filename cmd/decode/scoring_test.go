@@ -0,0 +1,82 @@
+package decode
+
+import (
+	"testing"
+)
+
+func TestGradeQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		want QuoteTrainingResults
+	}{
+		{
+			"same but the case and the punctuation",
+			"The cat, sat!",
+			QuoteTrainingResults{Corrects: 9, Total: 9, WordCorrects: 3, WordTotal: 3},
+		},
+		{
+			"substitution",
+			"the cot sat",
+			QuoteTrainingResults{Corrects: 8, Total: 9, WordCorrects: 2, WordTotal: 3, Substitutions: 1},
+		},
+		{
+			"insertion",
+			"the cats sat",
+			QuoteTrainingResults{Corrects: 9, Total: 9, WordCorrects: 2, WordTotal: 3, Insertions: 1},
+		},
+		{
+			"deletion",
+			"the ct sat",
+			QuoteTrainingResults{Corrects: 8, Total: 9, WordCorrects: 2, WordTotal: 3, Deletions: 1},
+		},
+		{
+			// The gap after the word is missed too.
+			"missed word",
+			"cat sat",
+			QuoteTrainingResults{Corrects: 6, Total: 9, WordCorrects: 2, WordTotal: 3, Deletions: 4},
+		},
+		{
+			"empty answer",
+			"",
+			QuoteTrainingResults{Corrects: 0, Total: 9, WordCorrects: 0, WordTotal: 3, Deletions: 11},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _, _, _ := GradeQuote(test.user, "The cat sat.")
+			if got != test.want {
+				t.Errorf("GradeQuote(%q) = %+v, want %+v", test.user, got, test.want)
+			}
+		})
+	}
+}
+
+func TestGradeQuoteDigits(t *testing.T) {
+	got, _, _, _ := GradeQuote("chapter", "Chapter 3.")
+	want := QuoteTrainingResults{Corrects: 7, Total: 8, WordCorrects: 1, WordTotal: 2, Deletions: 2}
+
+	if got != want {
+		t.Errorf("GradeQuote() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGradeQuoteAllCorrect(t *testing.T) {
+	tests := []struct {
+		user string
+		want bool
+	}{
+		{"the cat sat", true},
+		{"the cot sat", false},
+		// Every letter is there, but not only them.
+		{"the cats sat", false},
+	}
+
+	for _, test := range tests {
+		results, _, _, _ := GradeQuote(test.user, "The cat sat.")
+		if got := results.AllCorrect(); got != test.want {
+			t.Errorf("AllCorrect() of %q = %v, want %v", test.user, got, test.want)
+		}
+	}
+}
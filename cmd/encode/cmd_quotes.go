@@ -0,0 +1,120 @@
+package encode
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	QuoteCmd.Flags().Uint16P("iterations", "n", 1, "Quotes to train with in one session.")
+	QuoteCmd.Flags().String("quotes", "", "Custom quote file to use for training.")
+
+	QuoteCmd.Flags().Uint16("min-length", 0, "Minimum length (in characters) of the quotes to train with.")
+	QuoteCmd.Flags().Uint16("max-length", 40, "Maximum length (in characters) of the quotes to train with. Zero for no limit.")
+}
+
+var QuoteCmd = &cobra.Command{
+	Use:     "quote",
+	Short:   "Train for encoding quotes.",
+	Aliases: []string{"quotes"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iterations, _ := cmd.Flags().GetUint16("iterations")
+		if iterations == 0 {
+			return fmt.Errorf("--iterations is set to zero.")
+		}
+
+		quotesFile, _ := cmd.Flags().GetString("quotes")
+		fileReader := io.Reader(strings.NewReader(assets.Quotes))
+
+		if len(quotesFile) != 0 {
+			file, err := os.Open(quotesFile)
+			if err != nil {
+				return fmt.Errorf("Error reading %v: %v", quotesFile, err)
+			}
+
+			defer file.Close()
+			fileReader = io.Reader(file)
+		} else {
+			quotesFile = "(the default quotes file)"
+		}
+
+		quotes, err := commons.LoadQuotes(fileReader)
+		if err != nil {
+			return fmt.Errorf("Error scanning %v: %v", quotesFile, err)
+		}
+
+		minLength, _ := cmd.Flags().GetUint16("min-length")
+		maxLength, _ := cmd.Flags().GetUint16("max-length")
+
+		if maxLength != 0 && minLength > maxLength {
+			return fmt.Errorf("--min-length is greater than --max-length.")
+		}
+
+		quotePool := commons.FilterQuotesByLength(quotes, int(minLength), int(maxLength))
+		if len(quotePool) == 0 {
+			return fmt.Errorf("There are no quotes in %v within the length limits.", quotesFile)
+		}
+
+		if len(quotePool) < int(iterations) {
+			cmd.PrintErrf("Warning: There are only %v quotes available, training with all of them.\n", len(quotePool))
+		}
+
+		random, _ := commons.NewRandom(0)
+		sessionQuotes := commons.PickQuotes(random, quotePool, int(iterations))
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainTexts(plainSession(cmd), "Encode quote training", "Quote", sessionQuotes)
+		}
+
+		p := tea.NewProgram(NewTextModel("Encode quote training", "Quote", sessionQuotes, nil))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'encode quotes' command gives the user drills to encode sentences to morse code.
+
+# How it works
+
+For each item, you will be given a quote to encode. Input the morse code of its letters
+using commas{,} as dashes and periods{.} as dots, with a space between the letters and
+an underscore{_} or a slash{/} between the words. The punctuation of the quote is left
+out. Enter to confirm the answer.
+
+======================================================================
+Encode quote training
+Quote (1 of 1): Do all things with love. - Og Mandino
+> ,.. ,,, _ ., .,.. .,.. / , .... .. ,. ,,. ...
+
+(escape to go back, space between letters, _ or / between words, ...)
+======================================================================
+
+At the end of the training session, you will be presented with the letters you got
+right in each quote. Move through the quotes to see which letters were encoded wrong,
+along with the correct morse code.
+
+===============================================================================
+Encode quote training results (1 iterations):
+
+ #    Text                                      Correct
+ 1    Do all things with love. - Og Mandino     11/28
+
+Quote #1: Do all things with love. - Og Mandino
+  word gap  missing
+  'w' .,,  missing
+  ...
+
+(17/28 characters wrong) (escape/enter to go back, up/down to see the mistakes, ctrl+c to exit)
+===============================================================================
+
+By default, only the quotes up to 40 characters are trained. Use --max-length
+(zero for no limit) and --min-length to change it.`,
+}
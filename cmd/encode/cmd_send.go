@@ -0,0 +1,120 @@
+package encode
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	SendCmd.Flags().String("keyer", commons.StraightKey.String(), "Key to send with: straight, iambic-a, or iambic-b.")
+	SendCmd.Flags().Float64P("wpm", "w", 20, "Words per minute to grade the timing against.")
+	SendCmd.Flags().String("paddles", "zx", "Keys of the dit and the dah paddles, for the iambic keyers.")
+	SendCmd.Flags().StringP("text", "t", "", "Text to send. Anything can be sent if empty.")
+}
+
+var SendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Practice sending morse code with a straight key or an iambic keyer.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return fmt.Errorf("Error: 'encode send' is not available with --plain, as it needs the keys to be held.")
+		}
+
+		keyerArg, _ := cmd.Flags().GetString("keyer")
+		mode, err := commons.ParseKeyerMode(keyerArg)
+		if err != nil {
+			return fmt.Errorf("Error: %v", err)
+		}
+
+		wpm, _ := cmd.Flags().GetFloat64("wpm")
+		if wpm <= 0 {
+			return fmt.Errorf("Error: --wpm should be more than zero.")
+		}
+
+		paddles, _ := cmd.Flags().GetString("paddles")
+		if paddleKeys := []rune(paddles); len(paddleKeys) != 2 || paddleKeys[0] == paddleKeys[1] {
+			return fmt.Errorf("Error: --paddles should be two different keys, like \"zx\".")
+		}
+
+		text, _ := cmd.Flags().GetString("text")
+
+		sidetone, err := commons.NewSidetone()
+		if err != nil {
+			return err
+		}
+
+		keyboard, err := enableKittyKeyboard(os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		defer keyboard.Close()
+
+		p := tea.NewProgram(
+			NewSendModel(keyboard.Keys(), mode, wpm, paddles, text, sidetone, nil),
+			tea.WithInput(nil),
+		)
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'encode send' command is for practicing the sending of morse code, with its timing.
+Key with the space bar as a straight key, or with two keys as the paddles of an iambic
+keyer. What you send is decoded as you go.
+
+This needs a terminal supporting the kitty keyboard protocol (like kitty, foot, WezTerm,
+Ghostty or Alacritty), as the other terminals do not tell when a key is released.
+
+# How it works
+
+==========================================================================
+Sending practice (straight, 20 wpm)
+  the quick
+> the qu
+        ---
+
+(hold space to key, enter to finish, backspace to start over, escape to go back, ctrl+c to exit)
+==========================================================================
+
+With --text, the text to send is shown above what was decoded, with the markers
+of 'decode quotes' under it. Without it, the dits and dahs that were sent are shown.
+
+Enter finishes the practice, and the timing of what was sent is graded against
+--wpm, in dits at that speed:
+
+=============================================================
+Sending practice (straight, 20 wpm) results:
+
+ Timing         Sent   Average     Ideal     Verdict
+ Dit            9      1.12 dits   1 dits    good
+ Dah            5      2.41 dits   3 dits    too short
+ Dah/dit ratio  5      2.15        3         too short
+ Element gap    10     1.05 dits   1 dits    good
+ Letter gap     4      3.63 dits   3 dits    good
+ Word gap       1      9.20 dits   7 dits    too long
+
+Sent at about 19.4 wpm.
+(enter/escape to go back, backspace to start over, ctrl+c to exit)
+=============================================================
+
+A beep is decoded as a dit if it is shorter than 2 dits, and a silence ends a
+letter after 2 dits and a word after 5 dits. The timing is good within 25% of
+the ideal.
+
+# Keyers
+
+  straight   hold the space bar for as long as the dit or the dah (default)
+  iambic-a   hold the dit or the dah paddle (--paddles, "zx" by default) to send
+             them repeatedly, and squeeze both to alternate them
+  iambic-b   like iambic-a, but the other paddle pressed during a dit or a dah
+             is sent after it, even if released by then
+
+The keys of this practice are fixed, as they are read from the kitty keyboard
+protocol: the "keys.<name>" of the config (see 'dihdah config --help') are not
+used here.`,
+}
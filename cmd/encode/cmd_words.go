@@ -0,0 +1,130 @@
+package encode
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	WordCmd.Flags().Uint16P("iterations", "n", 5, "Training iterations.")
+	WordCmd.Flags().Uint16P("w-length", "m", 5, "Length of maximum word length for training. Zero for no limit.")
+	WordCmd.Flags().String("words", "", "Custom word file to train on.")
+}
+
+var WordCmd = &cobra.Command{
+	Use:     "word",
+	Short:   "Train for encoding words.",
+	Aliases: []string{"words"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iterations, _ := cmd.Flags().GetUint16("iterations")
+		if iterations == 0 {
+			return fmt.Errorf("--iterations is set to zero.")
+		}
+
+		wordLength, _ := cmd.Flags().GetUint16("w-length")
+
+		wordFile, _ := cmd.Flags().GetString("words")
+		fileReader := io.Reader(strings.NewReader(assets.Words))
+
+		if len(wordFile) != 0 {
+			file, err := os.Open(wordFile)
+			if err != nil {
+				return fmt.Errorf("Error opening %v: %v", wordFile, err)
+			}
+
+			defer file.Close()
+			fileReader = io.Reader(file)
+		} else {
+			wordFile = "(the default word file)"
+		}
+
+		allWords, err := commons.LoadWords(fileReader)
+		if err != nil {
+			return fmt.Errorf("Error reading through %v: %v", wordFile, err)
+		}
+
+		wordPool := WordPool(allWords, int(wordLength))
+		if len(wordPool) == 0 {
+			return fmt.Errorf("There are no words in %v within the length limit.", wordFile)
+		}
+
+		random, _ := commons.NewRandom(0)
+		words := commons.PickWords(random, wordPool, int(iterations))
+
+		title := "Encode word training"
+		if wordLength != 0 {
+			title = fmt.Sprintf("Encode word training (%v letter limit)", wordLength)
+		}
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainTexts(plainSession(cmd), title, "Word", words)
+		}
+
+		p := tea.NewProgram(NewTextModel(title, "Word", words, nil))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'encode words' command gives the user drills to encode whole words to morse code.
+
+# How it works
+
+For each item, you will be given a word to encode. Input the morse code of its letters
+using commas{,} as dashes and periods{.} as dots, with a space between the letters.
+Enter to confirm the answer. A correct answer is played back.
+
+=============================================
+Encode word training (5 letter limit)
+Word (1 of 5): egg
+> . ,,. ,,.
+
+(escape to go back, space between letters, ...)
+=============================================
+
+At the end of the training session, you will be presented with the letters you got
+right in each word. Move through the words to see which letters were encoded wrong,
+along with the correct morse code.
+
+===============================================================================
+Encode word training (5 letter limit) results (5 iterations):
+
+ #    Text          Correct
+ 1    egg           3/3
+ 2    bird          3/4
+ ...
+
+Word #2: bird
+  'r' .,.  answered .,,
+
+(1/19 characters wrong) (escape/enter to go back, up/down to see the mistakes, ctrl+c to exit)
+===============================================================================
+
+The answer is aligned against the word with the least amount of edits, so a missed
+or an extra letter only counts as one mistake. Missed letters are shown as "missing",
+and letters that should not be there as "extra".`,
+}
+
+// The words of the word file up to the word length (zero for no limit), in
+// lowercase and without their hyphens.
+func WordPool(allWords []string, wordLength int) []string {
+	wordPool := []string(nil)
+	for _, word := range allWords {
+		word = strings.ToLower(strings.ReplaceAll(word, "-", ""))
+		if len(word) == 0 || (wordLength != 0 && len(word) > wordLength) {
+			continue
+		}
+
+		wordPool = append(wordPool, word)
+	}
+
+	return wordPool
+}
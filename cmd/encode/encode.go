@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +20,9 @@ var NewLettersPerLevel = [...]string{
 	"lazy",
 }
 
+// Pitch in hertz that wrong answers are played back at.
+const DefaultMistakePitch = 600.0
+
 func init() {
 	Cmd.Flags().UintP("iterations", "n", 0, "How many items for the training session.")
 	Cmd.Flags().BoolP("recap", "a", false, "To train for all letters in the letter pool at once.")
@@ -29,8 +33,21 @@ func init() {
 	))
 	Cmd.Flags().String("letters", "", "Custom alphabet pool to train. You probably should start by using --level.")
 
+	Cmd.Flags().DurationP("time-limit", "t", 0, "Time to answer each letter, like 5s. Letters not answered in time are wrong.")
+	Cmd.Flags().Duration("session-time", 0, "Total time of the session, like 1m, answering as many letters as possible.")
+
+	Cmd.Flags().Float64("mistake-pitch", DefaultMistakePitch, "Pitch (in hertz) to play a wrong answer at, before the correct one. Set to 1000 for the same pitch.")
+	Cmd.PersistentFlags().Bool("plain", false, "Line by line prompts and answers instead of the full screen drills, for screen readers.")
+
 	Cmd.MarkFlagsOneRequired("level", "letters")
 	Cmd.MarkFlagsMutuallyExclusive("level", "letters")
+	Cmd.MarkFlagsMutuallyExclusive("time-limit", "session-time")
+	Cmd.MarkFlagsMutuallyExclusive("session-time", "iterations")
+	Cmd.MarkFlagsMutuallyExclusive("session-time", "recap")
+
+	Cmd.AddCommand(SendCmd)
+	Cmd.AddCommand(WordCmd)
+	Cmd.AddCommand(QuoteCmd)
 }
 
 var Cmd = &cobra.Command{
@@ -74,35 +91,35 @@ var Cmd = &cobra.Command{
 				return fmt.Errorf("Error: --letters is empty.")
 			}
 
-			for i := range levelArg {
-				letters += NewLettersPerLevel[i]
-			}
+			letters = LevelLetters(int(levelArg))
 		}
 
 		dedupedLetters := DedupCleanLetters(letters)
 
-		doAllLetters, _ := cmd.Flags().GetBool("recap")
-		if doAllLetters {
-			p := tea.NewProgram(NewLetterModel(dedupedLetters, nil))
-			if _, err := p.Run(); err != nil {
-				return fmt.Errorf("Error running the program: %v", err)
-			}
+		timing := Timing{}
+		timing.PerLetter, _ = cmd.Flags().GetDuration("time-limit")
+		timing.Session, _ = cmd.Flags().GetDuration("session-time")
 
-			return nil
+		if timing.PerLetter < 0 || timing.Session < 0 {
+			return fmt.Errorf("Error: --time-limit and --session-time should not be negative.")
 		}
 
-		iterations, _ := cmd.Flags().GetUint("iterations")
-		if iterations == 0 {
-			iterations = max(uint(len(dedupedLetters)/2), 3)
+		mistakePitch, _ := cmd.Flags().GetFloat64("mistake-pitch")
+		if mistakePitch <= 0 {
+			return fmt.Errorf("Error: --mistake-pitch should be more than zero.")
 		}
 
-		trainingLetters := ""
-		for range iterations {
-			randomLetter := letters[rand.Intn(len(letters))]
-			trainingLetters += string(randomLetter)
+		doAllLetters, _ := cmd.Flags().GetBool("recap")
+		iterations, _ := cmd.Flags().GetUint("iterations")
+
+		random, _ := commons.NewRandom(0)
+		trainingLetters := TrainingLetters(random, letters, int(iterations), doAllLetters || timing.Session != 0)
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			return runPlainLetters(plainSession(cmd), trainingLetters, dedupedLetters, timing, mistakePitch)
 		}
 
-		p := tea.NewProgram(NewLetterModel(trainingLetters, nil))
+		p := tea.NewProgram(NewLetterModel(trainingLetters, timing, mistakePitch, nil))
 
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("Error running the program: %v", err)
@@ -127,24 +144,98 @@ Letter 'h' (1 of 3)
 (escape/ctrl+c to go back, enter to confirm)
 =============================================
 
+When the answer is wrong, what you entered is played at a lower pitch (see
+--mistake-pitch), followed by the correct morse code. Both are also shown aligned,
+with the same markers as 'decode quotes' ("?" wrong, "+" extra, "-" missed):
+
+=============================================
+Encode training (3 letters)
+Letter 't' (2 of 3)
+>
+
+Last mistake:
+'l'  .,.. (expected)
+     .,.  (yours)
+        -
+
+(escape to go back, enter to confirm, ctrl+o for the chart, ctrl+c to exit)
+=============================================
+
 At the end of the training session, you will be presented with the correct morse
-code characters. Use that as learning and/or feedback for the next training.
+code characters, next to your answer if it was wrong. Use that as learning and/or feedback for the next training.
 
 ====================================================
 Encode training results (3 letters, 3 iterations):
 
- #    Character   Correct?  Answer
- 1    h           yes       ....
- 2    t           yes       ,
- 3    t           yes       ,
+ #    Character   Correct?  Answer            Time
+ 1    h           yes       ....              1.3s
+ 2    t           yes       ,                 0.8s
+ 3    t           yes       ,                 0.6s
 
+Slowest letters: h 1.3s, t 0.7s
 (all correct!) (escape / ctrl+c / enter to go back)
 ====================================================
 
+# Timed training
+
+With --time-limit, each letter has to be answered before the countdown ends, or it
+counts as wrong. With --session-time, the session goes on for that long, with as many
+letters as you can answer. The letters are then played in no particular order.
+
+=====================================================
+Timed encode training (5 letters, 1m0s session)
+Letter 'o' (12) (0:31 left)
+> ,,,
+
+(escape to go back, enter to confirm, ctrl+o for the chart, ctrl+c to exit)
+=====================================================
+
+Either way, the time taken for each letter is shown in the results, along with the
+slowest letters, the characters per minute and the accuracy. Timed sessions are saved,
+and ranked against the previous sessions with the same letters and timing (by correct
+characters per minute).
+
+=====================================================
+Encode training results (5 letters, 23 iterations):
+
+ #    Character   Correct?  Answer            Time
+ 4    r           no        .,, vs .,.        2.8s
+ 1    t           yes       ,                 0.9s
+ ...
+
+Slowest letters: r 2.4s, b 1.9s, o 1.4s
+23.0 characters per minute, 91% accuracy. New best! (previous best: 20.5 cpm, 88% accuracy on 2026-10-12)
+(2/23 mistakes) (escape/enter to go back, s to toggle sort, ctrl+c to exit)
+=====================================================
+
+# Plain mode
+
+With --plain, the drills are line by line instead of full screen, for screen
+readers: each prompt is printed on its own line, the answers are typed on a line
+and confirmed with enter, and the results are told as sentences, without colors
+or tables. A hyphen{-} can be used as a dash too.
+
+  $ dihdah encode --level 1 --plain
+  Encode training, 3 letters. Type the morse code of each letter, with a period for a dot and a comma or a hyphen for a dash.
+  Letter 1 of 3: h.
+  ....
+  Correct.
+  Letter 2 of 3: t.
+  .
+  Wrong, t is dash. You entered dot.
+  ...
+  Results: 2 of 3 letters right. Mistakes: t as dot.
+
+'encode send' is not available in plain mode, as it needs the keys to be held.
+
 # Extras
 
-The 'encode' command is analogous to 'decode letters', but since the 'encode' command only has
-letter drills, it was decided to only have 'encode'.
+The 'encode' command is analogous to 'decode letters'. After learning the alphabet, these
+subcommands are for encoding more than letters:
+  - 'dihdah encode words': Gives the user drills to encode whole words.
+  - 'dihdah encode quotes': Gives the user drills to encode sentences.
+  - 'dihdah encode send': Practice sending morse code with its timing, with a straight key or
+    an iambic keyer.
 
 This is the default letter pool if you specify --level/-l:
     ========================================
@@ -166,6 +257,44 @@ NOTES:
     run --level with --recap before proceeding with the next --level.`,
 }
 
+// The letters of the levels up to the given one.
+func LevelLetters(level int) string {
+	letters := ""
+	for i := range min(level, len(NewLettersPerLevel)) {
+		letters += NewLettersPerLevel[i]
+	}
+
+	return letters
+}
+
+// Picks the letters to train: random letters of the pool (by default half as
+// many as there are letters, at least 3), or all of them shuffled for a recap
+// or a timed session.
+func TrainingLetters(random *rand.Rand, letters string, iterations int, recap bool) string {
+	dedupedLetters := DedupCleanLetters(letters)
+
+	if recap {
+		allLettersRand := []rune(dedupedLetters)
+		random.Shuffle(len(allLettersRand), func(i, j int) {
+			allLettersRand[i], allLettersRand[j] = allLettersRand[j], allLettersRand[i]
+		})
+
+		return string(allLettersRand)
+	}
+
+	if iterations == 0 {
+		iterations = max(len(dedupedLetters)/2, 3)
+	}
+
+	trainingLetters := ""
+	for range iterations {
+		randomLetter := letters[random.Intn(len(letters))]
+		trainingLetters += string(randomLetter)
+	}
+
+	return trainingLetters
+}
+
 func DedupCleanLetters(str string) string {
 	runes := []rune(strings.ToLower(str))
 	firstLetter := runes[0]
@@ -0,0 +1,140 @@
+package encode
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+const encodeHistoryFile = "encode_history.json"
+
+// Time limits of a timed encode session. The zero value is not timed.
+type Timing struct {
+	// Countdown for answering each letter.
+	PerLetter time.Duration
+
+	// Total time of the session, with as many letters as can be answered.
+	Session time.Duration
+}
+
+func (timing Timing) Timed() bool {
+	return timing.PerLetter != 0 || timing.Session != 0
+}
+
+func (timing Timing) String() string {
+	if timing.Session != 0 {
+		return fmt.Sprintf("%v session", timing.Session)
+	}
+
+	return fmt.Sprintf("%v per letter", timing.PerLetter)
+}
+
+// A timed encode session, saved to rank the next sessions against.
+type timedSession struct {
+	// The letter pool and the timing, as only the sessions with the same
+	// ones are compared.
+	Key string `json:"key"`
+
+	Date     time.Time `json:"date"`
+	Letters  int       `json:"letters"`
+	Correct  int       `json:"correct"`
+	Duration float64   `json:"durationSeconds"`
+}
+
+func newTimedSession(pool string, timing Timing, letters int, correct int, duration time.Duration) timedSession {
+	poolRunes := []rune(DedupCleanLetters(pool))
+	slices.Sort(poolRunes)
+
+	return timedSession{
+		Key:      fmt.Sprintf("%v (%v)", string(poolRunes), timing),
+		Date:     time.Now(),
+		Letters:  letters,
+		Correct:  correct,
+		Duration: duration.Seconds(),
+	}
+}
+
+// Characters per minute.
+func (session timedSession) Cpm() float64 {
+	if session.Duration == 0 {
+		return 0
+	}
+
+	return float64(session.Letters) / (session.Duration / 60)
+}
+
+func (session timedSession) Accuracy() float64 {
+	if session.Letters == 0 {
+		return 0
+	}
+
+	return float64(session.Correct) / float64(session.Letters)
+}
+
+// Correct characters per minute, which the sessions are ranked by.
+func (session timedSession) score() float64 {
+	return session.Cpm() * session.Accuracy()
+}
+
+// Saves the session to the history, and describes how it ranks against the
+// previous sessions with the same letters and timing.
+func recordTimedSession(session timedSession) (string, error) {
+	history := []timedSession{}
+	if err := commons.LoadStoredJSON(encodeHistoryFile, &history); err != nil {
+		return "", err
+	}
+
+	previous := slices.DeleteFunc(slices.Clone(history), func(s timedSession) bool {
+		return s.Key != session.Key
+	})
+
+	history = append(history, session)
+	if err := commons.SaveStoredJSON(encodeHistoryFile, history); err != nil {
+		return "", err
+	}
+
+	return describeRank(session, previous), nil
+}
+
+// Describes how the session ranks against the previous ones. A tie with the
+// best session is an equal best, not a new one.
+func describeRank(session timedSession, previous []timedSession) string {
+	if len(previous) == 0 {
+		return "First session with these letters and timing."
+	}
+
+	best := slices.MaxFunc(previous, func(a, b timedSession) int {
+		return cmp.Compare(a.score(), b.score())
+	})
+
+	rank := 1
+	for _, s := range previous {
+		if s.score() > session.score() {
+			rank += 1
+		}
+	}
+
+	text := strings.Builder{}
+	switch {
+	case rank == 1 && session.score() == best.score():
+		text.WriteString("Equal best! ")
+	case rank == 1:
+		text.WriteString("New best! ")
+	default:
+		fmt.Fprintf(&text, "Ranked #%v of %v sessions. ", rank, len(previous)+1)
+	}
+
+	fmt.Fprintf(
+		&text,
+		"(previous best: %.1f cpm, %.0f%% accuracy on %v)",
+		best.Cpm(),
+		best.Accuracy()*100,
+		best.Date.Format(time.DateOnly),
+	)
+
+	return text.String()
+}
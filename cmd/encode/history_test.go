@@ -0,0 +1,35 @@
+package encode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribeRank(t *testing.T) {
+	// 60 and 30 correct letters per minute.
+	fast := timedSession{Letters: 60, Correct: 60, Duration: 60}
+	slow := timedSession{Letters: 30, Correct: 30, Duration: 60}
+
+	tests := []struct {
+		name     string
+		session  timedSession
+		previous []timedSession
+		want     string
+	}{
+		{"first", fast, nil, "First session"},
+		{"better", fast, []timedSession{slow}, "New best!"},
+		{"tie with the best", fast, []timedSession{slow, fast}, "Equal best!"},
+		{"worse", slow, []timedSession{fast}, "Ranked #2 of 2 sessions."},
+		// The ties below are not counted above the session.
+		{"tie below the best", slow, []timedSession{fast, slow}, "Ranked #2 of 3 sessions."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := describeRank(test.session, test.previous)
+			if !strings.HasPrefix(got, test.want) {
+				t.Errorf("describeRank() = %q, want it to start with %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package encode
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Kitty keyboard protocol flags: disambiguate the escape codes (1), report the
+// press/repeat/release event types (2), and report all keys as escape codes (8)
+// so that the text keys are released too.
+const kittyKeyboardFlags = 1 | 2 | 8
+
+const kittyQueryTimeout = time.Second
+
+type kittyEventType int
+
+const (
+	kittyPress kittyEventType = iota + 1
+	kittyRepeat
+	kittyRelease
+)
+
+const (
+	kittyEnter     = 13
+	kittyEscape    = 27
+	kittyBackspace = 127
+
+	kittyCtrlModifier = 4
+)
+
+// A key event of the kitty keyboard protocol, like "CSI 32;1:3u" for releasing
+// the space key.
+type kittyKey struct {
+	code      rune
+	modifiers int
+	event     kittyEventType
+	at        time.Time
+}
+
+func (key kittyKey) ctrl() bool {
+	return key.modifiers&kittyCtrlModifier != 0
+}
+
+type kittyKeyboard struct {
+	in        *os.File
+	out       io.Writer
+	prevState *term.State
+
+	keys    chan kittyKey
+	replies chan string
+}
+
+// Puts the terminal in raw mode and enables the kitty keyboard protocol, if the
+// terminal supports it. The keys are then received from Keys().
+func enableKittyKeyboard(in *os.File, out io.Writer) (*kittyKeyboard, error) {
+	if !term.IsTerminal(in.Fd()) {
+		return nil, fmt.Errorf("Error: The input is not a terminal.")
+	}
+
+	prevState, err := term.MakeRaw(in.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("Error entering raw mode: %v", err)
+	}
+
+	keyboard := &kittyKeyboard{
+		in:        in,
+		out:       out,
+		prevState: prevState,
+		keys:      make(chan kittyKey, 64),
+		replies:   make(chan string, 4),
+	}
+
+	go keyboard.readLoop()
+
+	// The terminals that support the protocol reply to its query before the
+	// reply to the primary device attributes, which every terminal sends.
+	fmt.Fprint(out, "\x1b[?u\x1b[c")
+
+	supported := false
+	timeout := time.After(kittyQueryTimeout)
+
+waitReplies:
+	for {
+		select {
+		case reply := <-keyboard.replies:
+			if strings.HasSuffix(reply, "u") {
+				supported = true
+				continue
+			}
+
+			break waitReplies
+		case <-timeout:
+			break waitReplies
+		}
+	}
+
+	if !supported {
+		term.Restore(in.Fd(), prevState)
+		return nil, fmt.Errorf("Error: The terminal does not support the kitty keyboard protocol, which is needed for detecting key releases.")
+	}
+
+	fmt.Fprintf(out, "\x1b[>%vu", kittyKeyboardFlags)
+	return keyboard, nil
+}
+
+func (keyboard *kittyKeyboard) Keys() <-chan kittyKey {
+	return keyboard.keys
+}
+
+// Disables the protocol and restores the terminal.
+func (keyboard *kittyKeyboard) Close() error {
+	fmt.Fprint(keyboard.out, "\x1b[<u")
+	return term.Restore(keyboard.in.Fd(), keyboard.prevState)
+}
+
+func (keyboard *kittyKeyboard) readLoop() {
+	buf := make([]byte, 256)
+	pending := []byte(nil)
+
+	for {
+		n, err := keyboard.in.Read(buf)
+		if err != nil {
+			return
+		}
+
+		at := time.Now()
+		pending = append(pending, buf[:n]...)
+
+		var keys []kittyKey
+		var replies []string
+		keys, replies, pending = parseKittySequences(pending)
+
+		for _, reply := range replies {
+			select {
+			case keyboard.replies <- reply:
+			default:
+			}
+		}
+
+		for _, key := range keys {
+			key.at = at
+			keyboard.keys <- key
+		}
+	}
+}
+
+// Parses the CSI sequences of the input, returning the incomplete sequence at
+// the end to be parsed with the next input. The replies to the queries (with
+// parameters starting with '?') are returned as the parameters with the final
+// byte. Anything else is dropped.
+func parseKittySequences(input []byte) (keys []kittyKey, replies []string, rest []byte) {
+	for len(input) != 0 {
+		if input[0] != '\x1b' {
+			input = input[1:]
+			continue
+		}
+
+		if len(input) < 2 {
+			return keys, replies, input
+		}
+
+		if input[1] != '[' {
+			input = input[1:]
+			continue
+		}
+
+		end := 2
+		for end < len(input) && (input[end] < 0x40 || input[end] > 0x7e) {
+			end += 1
+		}
+
+		if end == len(input) {
+			return keys, replies, input
+		}
+
+		params, final := string(input[2:end]), input[end]
+		input = input[end+1:]
+
+		if strings.HasPrefix(params, "?") {
+			replies = append(replies, params+string(final))
+			continue
+		}
+
+		if final != 'u' {
+			continue
+		}
+
+		if key, ok := parseKittyKey(params); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, replies, nil
+}
+
+// Parses the "code[:alternates][;modifiers[:event]]" parameters of a key.
+func parseKittyKey(params string) (kittyKey, bool) {
+	fields := strings.Split(params, ";")
+
+	code, err := strconv.Atoi(strings.Split(fields[0], ":")[0])
+	if err != nil {
+		return kittyKey{}, false
+	}
+
+	key := kittyKey{code: rune(code), event: kittyPress}
+	if len(fields) < 2 {
+		return key, true
+	}
+
+	modifierFields := strings.Split(fields[1], ":")
+	if modifiers, err := strconv.Atoi(modifierFields[0]); err == nil {
+		key.modifiers = modifiers - 1
+	}
+
+	if len(modifierFields) > 1 {
+		if event, err := strconv.Atoi(modifierFields[1]); err == nil {
+			key.event = kittyEventType(event)
+		}
+	}
+
+	return key, true
+}
@@ -1,11 +1,16 @@
 package encode
 
 import (
+	"cmp"
 	"fmt"
+	"math/rand"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,13 +18,15 @@ import (
 	"github.com/gopxl/beep"
 	"github.com/gopxl/beep/speaker"
 	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
 )
 
 type letterModel struct {
 	backReference    tea.Model
 	wrongRightSorted bool
 
-	drill       *commons.Drill
+	session     *drill.Session
 	lettersUsed string
 
 	input        textinput.Model
@@ -27,17 +34,18 @@ type letterModel struct {
 	rows         []table.Row
 
 	showResults bool
-	score       int
 
-	charPlayer chan<- rune
-}
+	timing   Timing
+	rankText string
 
-func NewLetterModel(trainingLetters string, backReference tea.Model) *letterModel {
-	drills := &commons.Drill{
-		Text:    trainingLetters,
-		Correct: make([]bool, len(trainingLetters)),
-	}
+	mistakePitch float64
+	lastMistake  string
+
+	codePlayer chan<- playedCode
+}
 
+// Wrong answers are played back at the mistake pitch, before the correct answer.
+func NewLetterModel(trainingLetters string, timing Timing, mistakePitch float64, backReference tea.Model) *letterModel {
 	input := textinput.New()
 	input.CharLimit = 32
 	input.Width = 10
@@ -45,18 +53,26 @@ func NewLetterModel(trainingLetters string, backReference tea.Model) *letterMode
 	input.Focus()
 
 	return &letterModel{
-		drill:         drills,
+		session:       drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckMorseCode),
 		backReference: backReference,
 		input:         input,
 		lettersUsed:   trainingLetters,
+		timing:        timing,
+		mistakePitch:  mistakePitch,
 	}
 }
 
 type doneMsg struct{}
 
-func initPlayingMorseCode(speed float64) (tea.Cmd, chan<- rune) {
+// A morse code to play, in the notation of MorseCharSound.
+type playedCode struct {
+	code  string
+	pitch float64
+}
+
+func initPlayingMorseCode(speed float64) (tea.Cmd, chan<- playedCode) {
 	playing := sync.WaitGroup{}
-	chars := make(chan rune, 256)
+	chars := make(chan playedCode, 256)
 
 	playingCmd := func() tea.Msg {
 		for {
@@ -65,7 +81,11 @@ func initPlayingMorseCode(speed float64) (tea.Cmd, chan<- rune) {
 			playing.Wait()
 			playing.Add(1)
 
-			morseCode := commons.MorseCharSound(commons.MorseCodeLookup[c], speed)
+			if c.pitch == 0 {
+				c.pitch = commons.DefaultPitch
+			}
+
+			morseCode := commons.MorseCharSoundAt(c.code, speed, c.pitch)
 			delayBuffer := commons.SoundAssets[commons.ShortDelay]
 
 			speaker.Play(
@@ -87,40 +107,57 @@ func initPlayingMorseCode(speed float64) (tea.Cmd, chan<- rune) {
 
 func (_m *letterModel) Init() tea.Cmd {
 	var playingCmd tea.Cmd
-	playingCmd, _m.charPlayer = initPlayingMorseCode(1)
+	playingCmd, _m.codePlayer = initPlayingMorseCode(1)
+
+	_m.session.Start()
+
+	if _m.timing.Timed() {
+		return tea.Batch(playingCmd, textinput.Blink, timerTick())
+	}
 
 	return tea.Batch(playingCmd, textinput.Blink)
 }
 
 type quitMsg struct{}
 
-func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	drill := _m.drill
+type timerTickMsg struct{}
+
+const timerTickInterval = time.Millisecond * 100
+
+func timerTick() tea.Cmd {
+	return tea.Tick(timerTickInterval, func(_ time.Time) tea.Msg {
+		return timerTickMsg{}
+	})
+}
 
+func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
 			if _m.backReference == nil {
 				return _m, tea.Quit
 			}
 
 			return _m.backReference, nil
-		case "ctrl+c":
+		case key.Matches(msg, commons.Keys.Quit):
 			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.lettersUsed, 1, _m)
+			return chartM, chartM.Init()
 		}
 	}
 
 	if _m.showResults {
-		if key, isKey := msg.(tea.KeyMsg); isKey {
-			switch key.String() {
-			case "enter":
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey {
+			switch {
+			case key.Matches(keyMsg, commons.Keys.Confirm):
 				if _m.backReference == nil {
 					return _m, tea.Quit
 				}
 
 				return _m.backReference, nil
-			case "s":
+			case key.Matches(keyMsg, commons.Keys.Sort):
 				_m.resultsTable = _m.toggleSorted()
 			}
 		}
@@ -132,7 +169,7 @@ func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
+		switch {
 		default:
 			keyMsg := msg.Runes
 			if len(keyMsg) != 1 {
@@ -145,47 +182,30 @@ func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return _m, nil
 
-		case "enter":
-			if drill.Current >= len(drill.Text) {
+		case key.Matches(msg, commons.Keys.Confirm):
+			if _m.session.Done() {
 				_m.showResults = true
 				return _m, nil
 			}
 
-			currentChar := drill.Text[drill.Current]
-
-			userAnswer := _m.input.Value()
-			morseCodeAnswer := commons.MorseCodeLookup[rune(currentChar)]
-
-			if userAnswer == morseCodeAnswer {
-				drill.Correct[drill.Current] = true
-				_m.charPlayer <- rune(currentChar)
-			}
-
-			drill.Current += 1
-			for drill.Current < len(drill.Text) {
-				currentChar := drill.Text[drill.Current]
-				if currentChar >= 'a' && currentChar <= 'z' {
-					break
-				}
-
-				drill.Current += 1
-			}
-
-			if drill.Current >= len(drill.Text) {
-				close(_m.charPlayer)
-
-				_m.rows = _m.initResultsTable()
-				_m.wrongRightSorted = true
-				_m.resultsTable = _m.toggleSorted()
+			_m.answer(_m.input.Value(), false)
+			return _m, nil
+		}
 
-				_m.score, _ = countCorrectLetters(drill.Text, drill.Correct)
-				_m.showResults = true
-			}
+	case timerTickMsg:
+		switch {
+		case _m.timing.Session != 0 && _m.session.Elapsed() >= _m.timing.Session:
+			_m.finish()
+		case _m.timing.PerLetter != 0 && _m.session.ItemElapsed() >= _m.timing.PerLetter:
+			_m.answer(_m.input.Value(), true)
+		}
 
-			_m.input.Reset()
+		if _m.showResults {
 			return _m, nil
 		}
 
+		return _m, timerTick()
+
 	case quitMsg:
 		return _m, tea.Quit
 	}
@@ -196,32 +216,99 @@ func (_m *letterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return _m, cmd
 }
 
-func (_m letterModel) initResultsTable() []table.Row {
-	drill := _m.drill
+func (_m *letterModel) answer(userAnswer string, timedOut bool) {
+	var answer drill.Answer
+	if timedOut {
+		answer = _m.session.TimeOut(userAnswer)
+	} else {
+		answer = _m.session.Answer(userAnswer)
+	}
 
-	j := 1
-	rows := []table.Row{}
+	morseCodeAnswer := answer.Item.MorseCode
 
-	for i := 0; i < len(drill.Text); i++ {
-		currentChar := rune(drill.Text[i])
-		if currentChar < 'a' || currentChar > 'z' {
-			continue
+	_m.lastMistake = ""
+	if answer.Correct {
+		_m.codePlayer <- playedCode{code: morseCodeAnswer}
+	} else {
+		// What was entered, then what was expected, with a word gap between them
+		if len(userAnswer) != 0 {
+			_m.codePlayer <- playedCode{code: userAnswer + string(commons.MorseSpaceIndicator), pitch: _m.mistakePitch}
 		}
 
+		_m.codePlayer <- playedCode{code: morseCodeAnswer}
+		_m.lastMistake = mistakeView(rune(answer.Item.Text[0]), userAnswer, morseCodeAnswer)
+	}
+
+	// A session with a total time goes on until the time is up.
+	if _m.session.Done() && _m.timing.Session != 0 {
+		randomLetter := _m.lettersUsed[rand.Intn(len(_m.lettersUsed))]
+		_m.session.Add(drill.LetterItems(string(randomLetter))...)
+	}
+
+	_m.input.Reset()
+
+	if _m.session.Done() {
+		_m.finish()
+	}
+}
+
+// Ends the session, dropping the letter being answered if the session time is up.
+func (_m *letterModel) finish() {
+	_m.session.Finish()
+	close(_m.codePlayer)
+
+	_m.rows = _m.initResultsTable()
+	_m.wrongRightSorted = true
+	_m.resultsTable = _m.toggleSorted()
+
+	_m.showResults = true
+
+	results := _m.session.Results()
+	if _m.timing.Timed() && results.Total() != 0 {
+		session := newTimedSession(_m.lettersUsed, _m.timing, results.Total(), results.Score, _m.session.Elapsed())
+
+		rankText, err := recordTimedSession(session)
+		if err != nil {
+			rankText = fmt.Sprintf("(cannot save the session: %v)", err)
+		}
+
+		_m.rankText = fmt.Sprintf(
+			"%.1f characters per minute, %.0f%% accuracy. %v",
+			session.Cpm(),
+			session.Accuracy()*100,
+			rankText,
+		)
+	}
+}
+
+func (_m letterModel) initResultsTable() []table.Row {
+	rows := []table.Row{}
+
+	for i, answer := range _m.session.Results().Answers {
 		correctString := "yes"
-		if !drill.Correct[i] {
+		if !answer.Correct {
 			correctString = "no"
 		}
 
+		timeString := fmt.Sprintf("%.1fs", answer.Latency.Seconds())
+		if answer.TimedOut {
+			timeString = "time up"
+		}
+
+		answerString := answer.Item.MorseCode
+		if !answer.Correct {
+			answerString = fmt.Sprintf("%v vs %v", cmp.Or(answer.Given, "(none)"), answerString)
+		}
+
 		row := table.Row{
-			fmt.Sprint(j),
-			string(currentChar),
+			fmt.Sprint(i + 1),
+			answer.Item.Text,
 			correctString,
-			commons.MorseCodeLookup[currentChar],
+			answerString,
+			timeString,
 		}
 
 		rows = append(rows, row)
-		j += 1
 	}
 
 	return rows
@@ -231,7 +318,8 @@ var resultsColumns = []table.Column{
 	{Title: "#", Width: 3},
 	{Title: "Character", Width: 10},
 	{Title: "Correct?", Width: 8},
-	{Title: "Answer", Width: 7},
+	{Title: "Answer", Width: 16},
+	{Title: "Time", Width: 7},
 }
 
 func (_m *letterModel) toggleSorted() table.Model {
@@ -248,6 +336,7 @@ func (_m *letterModel) toggleSorted() table.Model {
 		table.WithColumns(resultsColumns),
 		table.WithRows(_m.rows),
 		table.WithHeight(min(10, len(_m.rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
 	)
 }
 
@@ -276,67 +365,108 @@ func compareRowNums(rowA, rowB table.Row) int {
 	}
 }
 
-func countCorrectLetters(text string, correct []bool) (int, error) {
-	if len([]rune(text)) != len(correct) {
-		return -1, fmt.Errorf("Corrects slice is not equal to length of text.")
+// Shows the entered and the expected elements aligned, with the markers of
+// commons.Align under the entered ones, like:
+//
+//	'l'  .,.. (expected)
+//	     .,.  (yours)
+//	        -
+func mistakeView(char rune, userAnswer string, morseCode string) string {
+	if len(userAnswer) == 0 {
+		return fmt.Sprintf("'%v'  %v (expected, nothing was entered)", string(char), morseCode)
 	}
 
-	correctCount := 0
-	for i := 0; i < len(text); i++ {
-		currentChar := rune(text[i])
-		if currentChar < 'a' || currentChar > 'z' {
-			continue
-		}
+	real, user := []rune(morseCode), []rune(userAnswer)
+	realRow, markerRow, userRow := commons.RenderAlignment(real, user, commons.Align(real, user), ' ')
+	indent := strings.Repeat(" ", len([]rune(string(char)))+4)
 
-		if correct[i] {
-			correctCount += 1
-		}
+	return strings.Join([]string{
+		fmt.Sprintf("'%v'  %v (expected)", string(char), realRow),
+		fmt.Sprintf("%v%v (yours)", indent, userRow),
+		indent + markerRow,
+	}, "\n")
+}
+
+func (_m *letterModel) timerView() string {
+	if _m.timing.Session != 0 {
+		left := max(0, _m.timing.Session-_m.session.Elapsed())
+		return fmt.Sprintf("%v:%02d left", int(left.Minutes()), int(left.Seconds())%60)
 	}
 
-	return correctCount, nil
+	left := max(0, _m.timing.PerLetter-_m.session.ItemElapsed())
+	return fmt.Sprintf("%.1fs left", left.Seconds())
 }
 
 func (_m *letterModel) View() string {
-	drill := _m.drill
 	if _m.showResults {
-		iterations := len(drill.Text)
+		results := _m.session.Results()
 
 		scoreText := "(all correct!)"
-		if _m.score != iterations {
-			mistakes := len(drill.Text) - _m.score
-			scoreText = fmt.Sprintf("(%v/%v mistakes)", mistakes, iterations)
+		if !results.AllCorrect() {
+			scoreText = fmt.Sprintf("(%v/%v mistakes)", len(results.Mistakes()), results.Total())
 		}
 
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
+		lines := []string{
 			fmt.Sprintf(
 				"Encode training results (%v letters, %v iterations):",
 				len(_m.lettersUsed),
-				len(drill.Text),
+				results.Total(),
 			),
 			"",
 			_m.resultsTable.View(),
 			"",
-			fmt.Sprintf("%v (escape/enter to go back, s to toggle sort, ctrl+c to exit)", scoreText),
-			"",
+		}
+
+		if results.Total() != 0 {
+			lines = append(lines, fmt.Sprintf("Slowest letters: %v", slowestLetters(results.Answers, 3)))
+		}
+
+		if len(_m.rankText) != 0 {
+			lines = append(lines, _m.rankText)
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			append(
+				lines,
+				fmt.Sprintf("%v (%v)", scoreText, commons.KeyHelp(commons.Keys.Back, commons.Keys.Sort, commons.Keys.Quit)),
+				"",
+			)...,
 		)
 	}
 
-	var charView string
-	if drill.Current < len(drill.Text) {
-		charView = string(drill.Text[drill.Current])
-	} else {
-		charView = "done"
+	charView := "done"
+	if item, ok := _m.session.Next(); ok {
+		charView = item.Text
+	}
+
+	current, total := _m.session.Progress()
+
+	title := fmt.Sprintf("Encode training (%v letters)", len(_m.lettersUsed))
+	progressText := fmt.Sprintf("(%v of %v)", current+1, total)
+
+	if _m.timing.Timed() {
+		title = fmt.Sprintf("Timed encode training (%v letters, %v)", len(_m.lettersUsed), _m.timing)
+		progressText = fmt.Sprintf("(%v) (%v)", current+1, _m.timerView())
+
+		if _m.timing.Session == 0 {
+			progressText = fmt.Sprintf("(%v of %v) (%v)", current+1, total, _m.timerView())
+		}
+	}
+
+	mistakeText := ""
+	if len(_m.lastMistake) != 0 {
+		mistakeText = "\nLast mistake:\n" + _m.lastMistake + "\n"
 	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		"",
-		fmt.Sprintf("Encode training (%v letters)", len(_m.lettersUsed)),
-		fmt.Sprintf("Letter '%v' (%v of %v)", charView, drill.Current+1, len(drill.Text)),
+		title,
+		fmt.Sprintf("Letter '%v' %v", charView, progressText),
 		_m.input.View(),
-		"",
-		"(escape to go back, enter to confirm, ctrl+c to exit)",
+		mistakeText,
+		fmt.Sprintf("(%v)", commons.KeyHelp(commons.Keys.Back, commons.Keys.Confirm, commons.Keys.Chart, commons.Keys.Quit)),
 		"",
 	)
 }
@@ -0,0 +1,238 @@
+package encode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/noAbbreviation/dihdah/ui/chart"
+)
+
+type textModel struct {
+	backReference tea.Model
+
+	// Like "Encode word training (5 letter limit)".
+	title    string
+	itemName string
+
+	session *drill.Session
+
+	input        textinput.Model
+	resultsTable table.Model
+	showResults  bool
+
+	codePlayer chan<- playedCode
+}
+
+// For encoding words and quotes, in the notation of 'decode' with spaces
+// between the characters and '_' or '/' between the words.
+func NewTextModel(title string, itemName string, texts []string, backReference tea.Model) *textModel {
+	input := textinput.New()
+	input.CharLimit = 1024
+	input.Width = 60
+	input.Placeholder = "... ,,, ..."
+	input.Focus()
+
+	return &textModel{
+		backReference: backReference,
+		title:         title,
+		itemName:      itemName,
+		session:       drill.NewSession(TextItems(texts), CheckTextCode),
+		input:         input,
+	}
+}
+
+func (_m *textModel) Init() tea.Cmd {
+	var playingCmd tea.Cmd
+	playingCmd, _m.codePlayer = initPlayingMorseCode(1)
+
+	_m.session.Start()
+	return tea.Batch(playingCmd, textinput.Blink)
+}
+
+func (_m *textModel) pool() string {
+	texts := []string{}
+	for _, item := range _m.session.Items() {
+		texts = append(texts, item.Text)
+	}
+
+	return strings.Join(texts, "")
+}
+
+func (_m *textModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back):
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			return _m.backReference, nil
+		case key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Chart):
+			chartM := chart.NewChartModel(_m.pool(), 1, _m)
+			return chartM, chartM.Init()
+		}
+	}
+
+	if _m.showResults {
+		if keyMsg, isKey := msg.(tea.KeyMsg); isKey && key.Matches(keyMsg, commons.Keys.Confirm) {
+			if _m.backReference == nil {
+				return _m, tea.Quit
+			}
+
+			return _m.backReference, nil
+		}
+
+		var cmd tea.Cmd
+		_m.resultsTable, cmd = _m.resultsTable.Update(msg)
+		return _m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		default:
+			keyMsg := msg.Runes
+			if len(keyMsg) != 1 {
+				break
+			}
+
+			if strings.ContainsRune(".,-_/ ", keyMsg[0]) {
+				break
+			}
+
+			return _m, nil
+
+		case key.Matches(msg, commons.Keys.Confirm):
+			if len(strings.TrimSpace(_m.input.Value())) == 0 {
+				return _m, nil
+			}
+
+			answer := _m.session.Answer(_m.input.Value())
+			if answer.Correct {
+				_m.codePlayer <- playedCode{code: answer.Item.MorseCode}
+			}
+
+			_m.input.Reset()
+
+			if _m.session.Done() {
+				close(_m.codePlayer)
+
+				_m.resultsTable = _m.initResultsTable()
+				_m.showResults = true
+			}
+
+			return _m, nil
+		}
+
+	case quitMsg:
+		return _m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	_m.input, cmd = _m.input.Update(msg)
+
+	return _m, cmd
+}
+
+func (_m *textModel) initResultsTable() table.Model {
+	rows := []table.Row{}
+	for i, answer := range _m.session.Results().Answers {
+		correct, total := gradeText(answer.Item, answer.Given).score()
+
+		text := answer.Item.Text
+		if len([]rune(text)) > 40 {
+			text = string([]rune(text)[:37]) + "..."
+		}
+
+		rows = append(rows, table.Row{
+			fmt.Sprint(i + 1),
+			text,
+			fmt.Sprintf("%v/%v", correct, total),
+		})
+	}
+
+	return table.New(
+		table.WithFocused(true),
+		table.WithColumns(textResultsColumns),
+		table.WithRows(rows),
+		table.WithHeight(min(10, len(rows)+1)),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+}
+
+var textResultsColumns = []table.Column{
+	{Title: "#", Width: 3},
+	{Title: "Text", Width: 40},
+	{Title: "Correct", Width: 7},
+}
+
+func (_m *textModel) View() string {
+	if _m.showResults {
+		answers := _m.session.Results().Answers
+
+		correct, total := 0, 0
+		for _, answer := range answers {
+			itemCorrect, itemTotal := gradeText(answer.Item, answer.Given).score()
+			correct, total = correct+itemCorrect, total+itemTotal
+		}
+
+		scoreText := "(all correct!)"
+		if correct != total {
+			scoreText = fmt.Sprintf("(%v/%v characters wrong)", total-correct, total)
+		}
+
+		selected := answers[_m.resultsTable.Cursor()]
+		mistakes := gradeText(selected.Item, selected.Given).mistakes()
+		if len(mistakes) == 0 {
+			mistakes = []string{"(no mistakes)"}
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("%v results (%v iterations):", _m.title, len(answers)),
+			"",
+			_m.resultsTable.View(),
+			"",
+			fmt.Sprintf("%v #%v: %v", _m.itemName, _m.resultsTable.Cursor()+1, selected.Item.Text),
+			"  "+strings.Join(mistakes, "\n  "),
+			"",
+			fmt.Sprintf(
+				"%v (%v, %v/%v to see the mistakes, %v)",
+				scoreText,
+				commons.KeyHelp(commons.Keys.Back),
+				commons.Keys.Up.Help().Key,
+				commons.Keys.Down.Help().Key,
+				commons.KeyHelp(commons.Keys.Quit),
+			),
+			"",
+		)
+	}
+
+	item, _ := _m.session.Next()
+	current, total := _m.session.Progress()
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		_m.title,
+		fmt.Sprintf("%v (%v of %v): %v", _m.itemName, current+1, total, item.Text),
+		_m.input.View(),
+		"",
+		fmt.Sprintf(
+			"(%v, space between letters, _ or / between words, %v)",
+			commons.KeyHelp(commons.Keys.Back),
+			commons.KeyHelp(commons.Keys.Confirm, commons.Keys.Chart, commons.Keys.Quit),
+		),
+		"",
+	)
+}
@@ -0,0 +1,320 @@
+package encode
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gopxl/beep/speaker"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+const sendTickInterval = time.Millisecond * 10
+
+// The keys are read from the kitty keyboard protocol instead of bubbletea, so
+// they are fixed rather than the ones of commons.Keys. Only for the help.
+var sendKeys = struct {
+	Finish  key.Binding
+	Restart key.Binding
+	Back    key.Binding
+	Quit    key.Binding
+}{
+	Finish:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "to finish")),
+	Restart: key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "to start over")),
+	Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("escape", "to go back")),
+	Quit:    key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "to exit")),
+}
+
+type sendModel struct {
+	backReference tea.Model
+
+	mode   commons.KeyerMode
+	wpm    float64
+	ditKey rune
+	dahKey rune
+	text   string
+
+	keys     <-chan kittyKey
+	sidetone *commons.Sidetone
+
+	start    time.Time
+	events   []commons.KeyEvent
+	keyDown  bool
+	analysis commons.KeyingAnalysis
+
+	resultsTable table.Model
+	showResults  bool
+}
+
+type sendKeyMsg kittyKey
+type sendTickMsg struct{}
+
+// The keys come from the kitty keyboard protocol, as bubbletea does not report
+// key releases. The text to send is optional.
+func NewSendModel(keys <-chan kittyKey, mode commons.KeyerMode, wpm float64, paddles string, text string, sidetone *commons.Sidetone, backReference tea.Model) *sendModel {
+	paddleKeys := []rune(paddles)
+
+	return &sendModel{
+		backReference: backReference,
+		mode:          mode,
+		wpm:           wpm,
+		ditKey:        paddleKeys[0],
+		dahKey:        paddleKeys[1],
+		text:          strings.ToLower(text),
+		keys:          keys,
+		sidetone:      sidetone,
+	}
+}
+
+func (_m *sendModel) Init() tea.Cmd {
+	speaker.Play(_m.sidetone)
+	return tea.Batch(_m.waitForKey(), sendTick())
+}
+
+func (_m *sendModel) waitForKey() tea.Cmd {
+	return func() tea.Msg {
+		return sendKeyMsg(<-_m.keys)
+	}
+}
+
+func sendTick() tea.Cmd {
+	return tea.Tick(sendTickInterval, func(_ time.Time) tea.Msg {
+		return sendTickMsg{}
+	})
+}
+
+func (_m *sendModel) goBack() (tea.Model, tea.Cmd) {
+	_m.sidetone.SetOn(false)
+
+	speaker.Clear()
+
+	if _m.backReference == nil {
+		return _m, tea.Quit
+	}
+
+	return _m.backReference, nil
+}
+
+func (_m *sendModel) restart() {
+	_m.events = nil
+	_m.keyDown = false
+	_m.analysis = commons.KeyingAnalysis{}
+	_m.showResults = false
+	_m.sidetone.SetOn(false)
+}
+
+// The signal sent until now.
+func (_m *sendModel) signal(now time.Time) []commons.SignalElement {
+	if len(_m.events) == 0 {
+		return nil
+	}
+
+	at := now.Sub(_m.start)
+	if _m.mode == commons.StraightKey {
+		events := _m.events
+		if _m.keyDown {
+			events = append(events[:len(events):len(events)], commons.KeyEvent{Down: false, At: at})
+		}
+
+		return commons.StraightKeySignal(events)
+	}
+
+	return commons.IambicKeySignal(_m.events, _m.mode, commons.WpmDitDuration(_m.wpm), at)
+}
+
+func (_m *sendModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sendKeyMsg:
+		key := kittyKey(msg)
+		model, cmd := _m.updateKey(key)
+
+		if model != tea.Model(_m) {
+			return model, cmd
+		}
+
+		return _m, tea.Batch(cmd, _m.waitForKey())
+	case sendTickMsg:
+		if _m.showResults {
+			return _m, sendTick()
+		}
+
+		now := time.Now()
+		signal := _m.signal(now)
+		_m.analysis = commons.AnalyzeKeying(signal, _m.wpm)
+
+		if _m.mode != commons.StraightKey && len(_m.events) != 0 {
+			_m.sidetone.SetOn(commons.SignalOnAt(signal, now.Sub(_m.start)))
+		}
+
+		return _m, sendTick()
+	}
+
+	return _m, nil
+}
+
+func (_m *sendModel) updateKey(key kittyKey) (tea.Model, tea.Cmd) {
+	if key.event != kittyRelease {
+		switch {
+		case key.code == 'c' && key.ctrl():
+			return _m, tea.Quit
+		case key.code == kittyEscape:
+			return _m.goBack()
+		case key.code == kittyBackspace:
+			_m.restart()
+			return _m, nil
+		}
+	}
+
+	if _m.showResults {
+		if key.event == kittyPress && key.code == kittyEnter {
+			return _m.goBack()
+		}
+
+		return _m, nil
+	}
+
+	if key.event == kittyPress && key.code == kittyEnter {
+		_m.keyDown = false
+		_m.sidetone.SetOn(false)
+
+		_m.analysis = commons.AnalyzeKeying(_m.signal(key.at), _m.wpm)
+		_m.resultsTable = _m.initResultsTable()
+		_m.showResults = true
+
+		return _m, nil
+	}
+
+	if key.event == kittyRepeat {
+		return _m, nil
+	}
+
+	paddle := commons.DitPaddle
+	switch {
+	case _m.mode == commons.StraightKey && key.code == ' ':
+		_m.keyDown = key.event == kittyPress
+		_m.sidetone.SetOn(_m.keyDown)
+	case _m.mode != commons.StraightKey && key.code == _m.ditKey:
+	case _m.mode != commons.StraightKey && key.code == _m.dahKey:
+		paddle = commons.DahPaddle
+	default:
+		return _m, nil
+	}
+
+	if len(_m.events) == 0 {
+		if key.event != kittyPress {
+			return _m, nil
+		}
+
+		_m.start = key.at
+	}
+
+	_m.events = append(_m.events, commons.KeyEvent{
+		Paddle: paddle,
+		Down:   key.event == kittyPress,
+		At:     key.at.Sub(_m.start),
+	})
+
+	return _m, nil
+}
+
+func (_m *sendModel) initResultsTable() table.Model {
+	rows := []table.Row{}
+	for _, grade := range _m.analysis.Grades(_m.wpm) {
+		unit := " dits"
+		if grade.Name == "Dah/dit ratio" {
+			unit = ""
+		}
+
+		rows = append(rows, table.Row{
+			grade.Name,
+			fmt.Sprint(grade.Count),
+			fmt.Sprintf("%.2f%v", grade.Measured, unit),
+			fmt.Sprintf("%v%v", grade.Ideal, unit),
+			grade.Verdict(),
+		})
+	}
+
+	return table.New(
+		table.WithFocused(true),
+		table.WithColumns(sendResultsColumns),
+		table.WithRows(rows),
+		table.WithHeight(len(rows)+1),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+}
+
+var sendResultsColumns = []table.Column{
+	{Title: "Timing", Width: 13},
+	{Title: "Sent", Width: 5},
+	{Title: "Average", Width: 10},
+	{Title: "Ideal", Width: 8},
+	{Title: "Verdict", Width: 9},
+}
+
+func (_m *sendModel) sentText() []string {
+	lines := []string{}
+	if len(_m.text) == 0 {
+		return append(lines, "> "+_m.analysis.Text, "  "+_m.analysis.MorseCode)
+	}
+
+	real, user := []rune(_m.text), []rune(_m.analysis.Text)
+	realRow, markerRow, userRow := commons.RenderAlignment(real, user, commons.Align(real, user), ' ')
+
+	return append(lines, "  "+realRow, "> "+userRow, "  "+markerRow)
+}
+
+func (_m *sendModel) View() string {
+	title := fmt.Sprintf("Sending practice (%v, %v wpm)", _m.mode, _m.wpm)
+
+	if _m.showResults {
+		speedText := "(nothing was sent)"
+		if wpm := _m.analysis.Wpm(); wpm != 0 {
+			speedText = fmt.Sprintf("Sent at about %.1f wpm.", wpm)
+		}
+
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("%v results:", title),
+			"",
+			lipgloss.JoinVertical(lipgloss.Left, _m.sentText()...),
+			"",
+			_m.resultsTable.View(),
+			"",
+			speedText,
+			fmt.Sprintf(
+				"(%v)",
+				commons.KeyHelp(
+					key.NewBinding(key.WithKeys("enter", "esc"), key.WithHelp("enter/escape", "to go back")),
+					sendKeys.Restart,
+					sendKeys.Quit,
+				),
+			),
+			"",
+		)
+	}
+
+	paddleHelp := []key.Binding{key.NewBinding(key.WithKeys(" "), key.WithHelp("hold space", "to key"))}
+	if _m.mode != commons.StraightKey {
+		paddleHelp = []key.Binding{
+			key.NewBinding(key.WithKeys(string(_m.ditKey)), key.WithHelp(string(_m.ditKey), "for dits")),
+			key.NewBinding(key.WithKeys(string(_m.dahKey)), key.WithHelp(string(_m.dahKey), "for dahs")),
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		title,
+		lipgloss.JoinVertical(lipgloss.Left, _m.sentText()...),
+		"",
+		fmt.Sprintf(
+			"(%v)",
+			commons.KeyHelp(append(paddleHelp, sendKeys.Finish, sendKeys.Restart, sendKeys.Back, sendKeys.Quit)...),
+		),
+		"",
+	)
+}
@@ -0,0 +1,164 @@
+package encode
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+	"github.com/spf13/cobra"
+)
+
+// The --plain mode of the drills, line by line for screen readers. The items
+// and the grading are the same as the ones of the models.
+
+func plainSession(cmd *cobra.Command) *commons.PlainSession {
+	return commons.NewPlainSession(cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+func runPlainLetters(session *commons.PlainSession, trainingLetters string, lettersUsed string, timing Timing, mistakePitch float64) error {
+	title := fmt.Sprintf("Encode training, %v letters", len(lettersUsed))
+	if timing.Timed() {
+		title = fmt.Sprintf("Timed encode training, %v letters, %v", len(lettersUsed), timing)
+	}
+
+	session.Say("%v. Type the morse code of each letter, with a period for a dot and a comma or a hyphen for a dash.", title)
+	drills := drill.NewSession(drill.LetterItems(trainingLetters), drill.CheckMorseCode)
+
+	for !drills.Done() {
+		item, _ := drills.Next()
+		current, total := drills.Progress()
+
+		prompt := fmt.Sprintf("Letter %v of %v: %v.", current+1, total, item.Text)
+		if timing.Session != 0 {
+			left := max(0, timing.Session-drills.Elapsed())
+			prompt = fmt.Sprintf("Letter %v: %v. %v seconds left.", current+1, item.Text, int(left.Seconds()))
+		}
+
+		drills.Show()
+		userAnswer, ok := session.Ask(prompt)
+		if !ok {
+			break
+		}
+
+		// The letter being answered when the time is up is dropped.
+		if timing.Session != 0 && drills.Elapsed() >= timing.Session {
+			session.Say("The time is up.")
+			break
+		}
+
+		userAnswer = NormalizeCode(userAnswer)
+		spelledCode := commons.SpellMorseCode(item.MorseCode)
+
+		var answer drill.Answer
+		if timing.PerLetter != 0 && drills.ItemElapsed() > timing.PerLetter {
+			answer = drills.TimeOut(userAnswer)
+		} else {
+			answer = drills.Answer(userAnswer)
+		}
+
+		switch {
+		case answer.Correct:
+			session.Say("Correct.")
+		case answer.TimedOut:
+			session.Say("Too late, after %.1f seconds. %v is %v.", answer.Latency.Seconds(), item.Text, spelledCode)
+		default:
+			session.Say("Wrong, %v is %v. You entered %v.", item.Text, spelledCode, commons.SpellMorseCode(userAnswer))
+
+			// What was entered, then what was expected, like in the model
+			if len(userAnswer) != 0 {
+				commons.PlayMorseCode(userAnswer+string(commons.MorseSpaceIndicator), 1, mistakePitch)
+			}
+		}
+
+		commons.PlayMorseCode(item.MorseCode, 1, commons.DefaultPitch)
+
+		// A session with a total time goes on until the time is up.
+		if drills.Done() && timing.Session != 0 {
+			drills.Add(drill.LetterItems(string(lettersUsed[rand.Intn(len(lettersUsed))]))...)
+		}
+	}
+
+	drills.Finish()
+
+	results := drills.Results()
+	if results.Total() == 0 {
+		session.Say("Nothing was answered.")
+		return session.Err()
+	}
+
+	score := commons.CountOf(results.Score, results.Total())
+	if results.AllCorrect() {
+		session.Say("Results: %v letters right. All correct!", score)
+	} else {
+		mistakes := []string{}
+		for _, answer := range results.Mistakes() {
+			if answer.TimedOut {
+				mistakes = append(mistakes, fmt.Sprintf("%v too late", answer.Item.Text))
+				continue
+			}
+
+			mistakes = append(mistakes, fmt.Sprintf("%v as %v", answer.Item.Text, commons.SpellMorseCode(answer.Given)))
+		}
+
+		session.Say("Results: %v letters right. Mistakes: %v.", score, strings.Join(mistakes, ", "))
+	}
+
+	session.Say("Slowest letters: %v.", slowestLetters(results.Answers, 3))
+
+	if timing.Timed() {
+		timedSession := newTimedSession(lettersUsed, timing, results.Total(), results.Score, drills.Elapsed())
+
+		rankText, err := recordTimedSession(timedSession)
+		if err != nil {
+			rankText = fmt.Sprintf("(cannot save the session: %v)", err)
+		}
+
+		session.Say("%.1f characters per minute, %.0f%% accuracy. %v", timedSession.Cpm(), timedSession.Accuracy()*100, rankText)
+	}
+
+	return session.Err()
+}
+
+// For the words and the quotes, graded like in the model.
+func runPlainTexts(session *commons.PlainSession, title string, itemName string, texts []string) error {
+	session.Say(
+		"%v, %v %vs. Type the morse code of each, with a period for a dot, a comma or a hyphen for a dash, "+
+			"a space between the letters and a slash between the words.",
+		title,
+		len(texts),
+		strings.ToLower(itemName),
+	)
+
+	drills := drill.NewSession(TextItems(texts), CheckTextCode)
+	correct, total := 0, 0
+
+	for !drills.Done() {
+		item, _ := drills.Next()
+		current, _ := drills.Progress()
+
+		given, ok := session.Ask(fmt.Sprintf("%v %v of %v: %v", itemName, current+1, len(texts), item.Text))
+		if !ok {
+			break
+		}
+
+		answer := drills.Answer(given)
+		graded := gradeText(answer.Item, answer.Given)
+
+		itemCorrect, itemTotal := graded.score()
+		correct, total = correct+itemCorrect, total+itemTotal
+
+		if answer.Correct {
+			session.Say("Correct.")
+			commons.PlayMorseCode(item.MorseCode, 1, commons.DefaultPitch)
+			continue
+		}
+
+		session.Say("%v characters right. Mistakes: %v.", commons.CountOf(itemCorrect, itemTotal), strings.Join(graded.mistakes(), "; "))
+	}
+
+	answered := drills.Results().Total()
+	session.Say("Results: %v %vs, with %v characters right.", answered, strings.ToLower(itemName), commons.CountOf(correct, total))
+	return session.Err()
+}
@@ -0,0 +1,129 @@
+package encode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+// The grading of the drills beyond right or wrong (see the drill package),
+// shared by the models and the --plain mode.
+
+// Lists the letters by their average time to answer, the slowest first.
+func slowestLetters(answers []drill.Answer, count int) string {
+	letters := drill.Results{Answers: answers}.Stats().Items
+
+	slowest := []string{}
+	for _, letter := range letters[:min(count, len(letters))] {
+		slowest = append(slowest, fmt.Sprintf("%v %.1fs", letter.Text, letter.AverageLatency.Seconds()))
+	}
+
+	return strings.Join(slowest, ", ")
+}
+
+// Also takes hyphens for the dahs, which are easier to find on a keyboard
+// without looking.
+func NormalizeCode(answer string) string {
+	return strings.ReplaceAll(answer, "-", ",")
+}
+
+// The words or the quotes to encode, with the morse code expected for them.
+func TextItems(texts []string) []drill.Item {
+	items := []drill.Item{}
+	for _, text := range texts {
+		items = append(items, drill.Item{Text: text, MorseCode: commons.ToMorseCode(text)})
+	}
+
+	return items
+}
+
+// The answer has the code of every character of the text, and the word gaps
+// between them.
+func CheckTextCode(item drill.Item, answer string) bool {
+	return len(gradeText(item, answer).mistakes()) == 0
+}
+
+// Counts the characters of the text encoded correctly in the answer, out of
+// all of them.
+func TextCodeScore(item drill.Item, answer string) (correct int, total int) {
+	return gradeText(item, answer).score()
+}
+
+// A word or a quote to encode, with the codes of its characters (and the word
+// gaps between them).
+type encodeItem struct {
+	text     string
+	expected []string
+	answer   []string
+	steps    []commons.AlignStep
+}
+
+func newEncodeItem(text string) encodeItem {
+	return encodeItem{
+		text:     text,
+		expected: commons.ParseMorseCode(commons.ToMorseCode(text)),
+	}
+}
+
+// Grades the answer to a word or a quote, for its score and its mistakes.
+func gradeText(item drill.Item, answer string) encodeItem {
+	encoded := newEncodeItem(item.Text)
+	encoded.grade(answer)
+
+	return encoded
+}
+
+// The answer is normalized here, so that the models, the --plain mode and the
+// API grade it the same.
+func (item *encodeItem) grade(answer string) {
+	item.answer = commons.ParseMorseCode(NormalizeCode(answer))
+	item.steps = commons.Align(item.expected, item.answer)
+}
+
+// Counts the characters (not the word gaps) encoded correctly, out of all of them.
+func (item encodeItem) score() (correct int, total int) {
+	for _, code := range item.expected {
+		if code != string(commons.MorseSpaceIndicator) {
+			total += 1
+		}
+	}
+
+	for _, step := range item.steps {
+		if step.Op == commons.AlignMatch && item.expected[step.RealIdx] != string(commons.MorseSpaceIndicator) {
+			correct += 1
+		}
+	}
+
+	return correct, total
+}
+
+// Describes each character that was encoded wrong, missing or extra.
+func (item encodeItem) mistakes() []string {
+	describe := func(code string) string {
+		if code == string(commons.MorseSpaceIndicator) {
+			return "word gap"
+		}
+
+		return fmt.Sprintf("'%v' %v", string(commons.MorseCodeChar(code)), code)
+	}
+
+	lines := []string{}
+	for _, step := range item.steps {
+		switch step.Op {
+		case commons.AlignSubstitution:
+			lines = append(lines, fmt.Sprintf(
+				"%v  answered %v",
+				describe(item.expected[step.RealIdx]),
+				item.answer[step.UserIdx],
+			))
+		case commons.AlignDeletion:
+			lines = append(lines, fmt.Sprintf("%v  missing", describe(item.expected[step.RealIdx])))
+		case commons.AlignInsertion:
+			lines = append(lines, fmt.Sprintf("extra %v", item.answer[step.UserIdx]))
+		}
+	}
+
+	return lines
+}
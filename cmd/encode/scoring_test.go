@@ -0,0 +1,44 @@
+package encode
+
+import (
+	"testing"
+
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+func TestCheckTextCode(t *testing.T) {
+	tests := []struct {
+		text   string
+		answer string
+		want   bool
+	}{
+		{"sos", "... ,,, ...", true},
+		{"sos", "... ,,, ..", false},
+		{"hi there", ".... .. / , .... . .,. .", true},
+		{"hi there", ".... .. , .... . .,. .", false},
+		// The punctuation inside the words is not sent, and is no word gap.
+		{"Don't", ",.. ,,, ,. ,", true},
+		{"well-known", ".,, . .,.. .,.. ,., ,. ,,, .,, ,.", true},
+		{"well-known", ".,, . .,.. .,.. / ,., ,. ,,, .,, ,.", false},
+		// The hyphens are taken for the dahs.
+		{"sos", "... --- ...", true},
+		{"hi there", ".... .. / - .... . .-. .", true},
+	}
+
+	for _, test := range tests {
+		items := TextItems([]string{test.text})
+		if got := CheckTextCode(items[0], test.answer); got != test.want {
+			t.Errorf("CheckTextCode(%q, %q) = %v, want %v", test.text, test.answer, got, test.want)
+		}
+	}
+}
+
+func TestTextCodeScore(t *testing.T) {
+	item := drill.Item{Text: "it's ok"}
+
+	// The last letter is wrong, the word gap is not counted.
+	correct, total := TextCodeScore(item, ".. , ... / ,,, .")
+	if correct != 4 || total != 5 {
+		t.Errorf("TextCodeScore() = %v, %v, want 4, 5", correct, total)
+	}
+}
@@ -0,0 +1,70 @@
+package mopp
+
+import (
+	"fmt"
+	"net"
+)
+
+// Enough for the longest words, as the packets have one word each.
+const maxPacketSize = 512
+
+// A connection to a MOPP server (or to the reflector), sending the text typed
+// and receiving the packets of the others.
+type Client struct {
+	conn   *net.UDPConn
+	wpm    int
+	serial int
+}
+
+func Dial(address string, wpm int) (*Client, error) {
+	serverAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot find %v: %v", address, err)
+	}
+
+	conn, err := net.DialUDP("udp", nil, serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot connect to %v: %v", address, err)
+	}
+
+	return &Client{conn: conn, wpm: wpm}, nil
+}
+
+// Sends the words of the text, returning what was sent of it.
+func (client *Client) Send(text string) (Packet, error) {
+	sent := Packet{Wpm: client.wpm}
+
+	for _, packet := range TextPackets(text, client.wpm, client.serial) {
+		if _, err := client.conn.Write(packet.Bytes()); err != nil {
+			return sent, fmt.Errorf("Error sending: %v", err)
+		}
+
+		client.serial = (packet.Serial + 1) & maxHeader
+		sent.MorseCode += packet.MorseCode
+	}
+
+	return sent, nil
+}
+
+// Waits for the next packet. The data that is no MOPP packet is skipped.
+func (client *Client) Receive() (Packet, error) {
+	buffer := make([]byte, maxPacketSize)
+
+	for {
+		n, err := client.conn.Read(buffer)
+		if err != nil {
+			return Packet{}, fmt.Errorf("Error receiving: %w", err)
+		}
+
+		packet, err := ParsePacket(buffer[:n])
+		if err != nil {
+			continue
+		}
+
+		return packet, nil
+	}
+}
+
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
@@ -0,0 +1,82 @@
+package mopp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	JoinCmd.Flags().IntP("wpm", "w", 20, "Words per minute to send at. What is received plays at the speed it was sent.")
+}
+
+var JoinCmd = &cobra.Command{
+	Use:   "join [host[:port]]",
+	Short: "Joins a MOPP server, playing what is received and sending what is typed.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wpm, _ := cmd.Flags().GetInt("wpm")
+		if wpm < 5 || wpm > maxHeader {
+			return fmt.Errorf("Error: --wpm should be from 5 to %v.", maxHeader)
+		}
+
+		address := "127.0.0.1"
+		if len(args) == 1 {
+			address = args[0]
+		}
+
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, strconv.Itoa(DefaultPort))
+		}
+
+		client, err := Dial(address, wpm)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		model := NewNetModel(client, address, wpm)
+
+		// The servers only pass the packets on to the clients they know of,
+		// which say hi first like on the Morserino-32.
+		sent, err := client.Send("hi")
+		if err != nil {
+			return err
+		}
+		model.addLine(true, sent.Text())
+
+		p := tea.NewProgram(model)
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'net join' command joins a MOPP server (the one on this computer by default),
+plays the morse code received from the others, and sends the text typed as morse code.
+
+# How it works
+
+=========================================================
+Morse over the network (127.0.0.1:7373, sending at 20 wpm)
+
+you: hi
+     hi tnx fer call
+you: gm es tnx
+
+> cq cq de
+
+(enter to send, escape to go back, ctrl+c to exit)
+=========================================================
+
+The words received are played at the speed they were sent, one after the other,
+and only shown once they are played, to be copied by ear first. The text typed is
+sent a word at a time when enter is pressed, at --wpm; the characters without morse
+code are left out.
+
+Joining sends "hi", as the servers only pass the morse code on to the clients
+they have heard from.`,
+}
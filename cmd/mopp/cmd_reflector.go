@@ -0,0 +1,47 @@
+package mopp
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ReflectorCmd.Flags().Uint16P("port", "p", DefaultPort, "Port to listen on.")
+	ReflectorCmd.Flags().String("host", "127.0.0.1", "Address to listen on. The default is only reachable from this computer.")
+	ReflectorCmd.Flags().Bool("echo", false, "Also send the morse code back to the one who sent it, for trying it alone.")
+}
+
+var ReflectorCmd = &cobra.Command{
+	Use:   "reflector",
+	Short: "Runs a tiny MOPP server, passing the morse code of each client on to the others.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		port, _ := cmd.Flags().GetUint16("port")
+		host, _ := cmd.Flags().GetString("host")
+		echo, _ := cmd.Flags().GetBool("echo")
+
+		reflector, err := ListenReflector(net.JoinHostPort(host, strconv.Itoa(int(port))), echo, func(format string, args ...any) {
+			cmd.Printf(format+"\n", args...)
+		})
+		if err != nil {
+			return err
+		}
+		defer reflector.Close()
+
+		cmd.Printf("Reflecting MOPP packets on %v (ctrl+c to stop)\n", reflector.Addr())
+		return reflector.Serve()
+	},
+	Long: `The 'net reflector' command runs a tiny MOPP server: the morse code sent by each client
+is passed on to the other clients, like the chat servers for the Morserino-32.
+
+  $ dihdah net reflector --host 0.0.0.0
+  Reflecting MOPP packets on 0.0.0.0:7373 (ctrl+c to stop)
+  192.168.1.20:50122 joined (1 connected)
+  192.168.1.20:50122: hi (20 wpm)
+
+A client joins by sending anything, and is dropped after 10 minutes without sending.
+With --echo, the morse code also goes back to the one who sent it, to try 'net join'
+alone on this computer.`,
+}
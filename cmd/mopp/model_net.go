@@ -0,0 +1,208 @@
+package mopp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+const (
+	netLineWidth = 60
+	netMaxLines  = 12
+)
+
+type netModel struct {
+	client  *Client
+	address string
+	wpm     int
+
+	input textinput.Model
+	lines []netLine
+
+	// The packets received and not played yet. Their text is only shown
+	// after they are played, for copying them by ear first.
+	queue   []Packet
+	playing bool
+
+	err error
+}
+
+type netLine struct {
+	sent bool
+	text string
+}
+
+type packetMsg Packet
+type retryReceiveMsg struct{}
+type packetPlayedMsg Packet
+type receiveErrMsg struct {
+	err    error
+	closed bool
+}
+
+func NewNetModel(client *Client, address string, wpm int) *netModel {
+	input := textinput.New()
+	input.CharLimit = 256
+	input.Width = netLineWidth
+	input.Placeholder = "cq cq de ..."
+	input.Focus()
+
+	return &netModel{
+		client:  client,
+		address: address,
+		wpm:     wpm,
+		input:   input,
+	}
+}
+
+func (_m *netModel) Init() tea.Cmd {
+	return tea.Batch(_m.waitForPacket(), textinput.Blink)
+}
+
+func (_m *netModel) waitForPacket() tea.Cmd {
+	return func() tea.Msg {
+		packet, err := _m.client.Receive()
+		if err != nil {
+			return receiveErrMsg{err: err, closed: errors.Is(err, net.ErrClosed)}
+		}
+
+		return packetMsg(packet)
+	}
+}
+
+// Plays the received packets one after the other, at the speed they were sent.
+func (_m *netModel) playNext() tea.Cmd {
+	if _m.playing || len(_m.queue) == 0 {
+		return nil
+	}
+
+	packet := _m.queue[0]
+	_m.queue = _m.queue[1:]
+	_m.playing = true
+
+	wpm := packet.Wpm
+	if wpm == 0 {
+		wpm = _m.wpm
+	}
+
+	return func() tea.Msg {
+		commons.PlayMorseCode(packet.MorseCode, commons.WpmSpeed(float64(wpm)), commons.DefaultPitch)
+		return packetPlayedMsg(packet)
+	}
+}
+
+// The received words go on the same line until it is full, like a stream of
+// text.
+func (_m *netModel) addLine(sent bool, text string) {
+	if last := len(_m.lines) - 1; !sent && last >= 0 && !_m.lines[last].sent &&
+		len(_m.lines[last].text)+len(text) <= netLineWidth {
+		_m.lines[last].text += text
+		return
+	}
+
+	_m.lines = append(_m.lines, netLine{sent: sent, text: text})
+	if len(_m.lines) > netMaxLines {
+		_m.lines = _m.lines[len(_m.lines)-netMaxLines:]
+	}
+}
+
+func (_m *netModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case packetMsg:
+		_m.err = nil
+		_m.queue = append(_m.queue, Packet(msg))
+		return _m, tea.Batch(_m.waitForPacket(), _m.playNext())
+	case packetPlayedMsg:
+		_m.playing = false
+		_m.addLine(false, Packet(msg).Text())
+		return _m, _m.playNext()
+	case receiveErrMsg:
+		_m.err = msg.err
+		if msg.closed {
+			return _m, nil
+		}
+
+		// Like when the server is not running yet, or restarts: the errors
+		// of the packets sent to it come back, and the next ones may go
+		// through.
+		return _m, tea.Tick(time.Second, func(time.Time) tea.Msg {
+			return retryReceiveMsg{}
+		})
+	case retryReceiveMsg:
+		return _m, _m.waitForPacket()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back), key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		case key.Matches(msg, commons.Keys.Confirm):
+			text := strings.TrimSpace(_m.input.Value())
+			if len(text) == 0 {
+				return _m, nil
+			}
+
+			sent, err := _m.client.Send(text)
+			if err != nil {
+				_m.err = err
+				return _m, nil
+			}
+
+			_m.err = nil
+			_m.addLine(true, sent.Text())
+			_m.input.Reset()
+			return _m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	_m.input, cmd = _m.input.Update(msg)
+	return _m, cmd
+}
+
+func (_m *netModel) View() string {
+	lines := []string{}
+	for _, line := range _m.lines {
+		if line.sent {
+			lines = append(lines, "you: "+line.text)
+		} else {
+			lines = append(lines, "     "+line.text)
+		}
+	}
+
+	for len(lines) < netMaxLines {
+		lines = append(lines, "")
+	}
+
+	status := ""
+	if _m.playing {
+		status = fmt.Sprintf("(receiving, %v more words waiting)", len(_m.queue))
+	}
+
+	if _m.err != nil {
+		status = _m.err.Error()
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		fmt.Sprintf("Morse over the network (%v, sending at %v wpm)", _m.address, _m.wpm),
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+		status,
+		_m.input.View(),
+		"",
+		fmt.Sprintf(
+			"(%v, %v)",
+			commons.KeyHelp(commons.WithDesc(commons.Keys.Confirm, "to send")),
+			commons.KeyHelp(commons.Keys.Back, commons.Keys.Quit),
+		),
+		"",
+	)
+}
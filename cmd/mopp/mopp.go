@@ -0,0 +1,32 @@
+package mopp
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// The port of the MOPP servers for the Morserino-32.
+const DefaultPort = 7373
+
+func init() {
+	Cmd.AddCommand(JoinCmd)
+	Cmd.AddCommand(ReflectorCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "net",
+	Short: "Live morse code over the network, with the MOPP protocol of the Morserino-32.",
+	Long: `The 'net' command sends and receives morse code over the internet (or a local network)
+with MOPP, the Morse over Packet Protocol of the Morserino-32. Clubs practice live with it
+through MOPP servers, which pass what each one sends on to the others.
+
+These are the things the user can do in here:
+  - 'dihdah net join [host[:port]]': Joins a MOPP server, to receive and send morse code.
+  - 'dihdah net reflector': Runs a tiny MOPP server on this computer.
+
+To try it all on one computer, run a reflector that also sends everything back:
+
+  $ dihdah net reflector --echo
+  $ dihdah net join          (in another terminal)
+
+The port is 7373 unless given otherwise, like the MOPP servers for the Morserino-32.`,
+}
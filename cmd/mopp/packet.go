@@ -0,0 +1,189 @@
+package mopp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// A packet of the Morse over Packet Protocol (MOPP), as sent by the
+// Morserino-32 and the chat servers for it: the protocol version, a serial
+// number and the speed, then two bits for each element.
+type Packet struct {
+	// Both from 0 to 63.
+	Serial int
+	Wpm    int
+
+	// In the notation of the drills, with spaces between the characters and
+	// '_' after a word.
+	MorseCode string
+}
+
+const (
+	protocolVersion = 0b01
+
+	elementDit     = 0b01
+	elementDah     = 0b10
+	endOfCharacter = 0b00
+	endOfWord      = 0b11
+
+	headerBits = 14
+	maxHeader  = 63
+)
+
+// The packets of the words of the text, one each like the Morserino-32 does.
+// The characters without morse code are left out.
+func TextPackets(text string, wpm int, serial int) []Packet {
+	packets := []Packet{}
+	for _, word := range strings.Fields(text) {
+		morseCode := commons.ToMorseCode(word)
+		if len(morseCode) == 0 {
+			continue
+		}
+
+		packets = append(packets, Packet{
+			Serial:    serial & maxHeader,
+			Wpm:       wpm,
+			MorseCode: morseCode + string(commons.MorseSpaceIndicator),
+		})
+
+		serial += 1
+	}
+
+	return packets
+}
+
+func (packet Packet) Bytes() []byte {
+	bits := packetBits{}
+	bits.write(protocolVersion, 2)
+	bits.write(packet.Serial&maxHeader, 6)
+	bits.write(min(max(packet.Wpm, 0), maxHeader), 6)
+
+	inCharacter := false
+	endCharacter := func(mark int) {
+		if inCharacter {
+			bits.write(mark, 2)
+		}
+
+		inCharacter = false
+	}
+
+	for _, r := range packet.MorseCode {
+		switch r {
+		case '.':
+			bits.write(elementDit, 2)
+			inCharacter = true
+		case ',':
+			bits.write(elementDah, 2)
+			inCharacter = true
+		case ' ', '-':
+			endCharacter(endOfCharacter)
+		case commons.MorseSpaceIndicator:
+			endCharacter(endOfWord)
+		}
+	}
+
+	endCharacter(endOfCharacter)
+	return bits.bytes
+}
+
+func ParsePacket(data []byte) (Packet, error) {
+	bits := packetBits{bytes: data}
+	if len(data)*8 < headerBits {
+		return Packet{}, fmt.Errorf("Error: the packet is too short.")
+	}
+
+	if version := bits.read(0, 2); version != protocolVersion {
+		return Packet{}, fmt.Errorf("Error: unknown MOPP version %v.", version)
+	}
+
+	packet := Packet{
+		Serial: bits.read(2, 6),
+		Wpm:    bits.read(8, 6),
+	}
+
+	morseCode := strings.Builder{}
+	inCharacter := false
+
+	// The padding of the last byte reads as ends of characters, which end
+	// nothing.
+	for at := headerBits; at+2 <= len(data)*8; at += 2 {
+		switch bits.read(at, 2) {
+		case elementDit:
+			morseCode.WriteRune('.')
+			inCharacter = true
+		case elementDah:
+			morseCode.WriteRune(',')
+			inCharacter = true
+		case endOfCharacter:
+			if inCharacter {
+				morseCode.WriteRune(' ')
+			}
+
+			inCharacter = false
+		case endOfWord:
+			code := strings.TrimSuffix(morseCode.String(), " ")
+			morseCode.Reset()
+			morseCode.WriteString(code + string(commons.MorseSpaceIndicator))
+
+			inCharacter = false
+		}
+	}
+
+	packet.MorseCode = strings.TrimSuffix(morseCode.String(), " ")
+	return packet, nil
+}
+
+// The characters of the morse code, with a space after each word. The codes
+// that are no characters are shown as '?'.
+func (packet Packet) Text() string {
+	text := strings.Builder{}
+
+	words := strings.SplitAfter(packet.MorseCode, string(commons.MorseSpaceIndicator))
+	for _, word := range words {
+		endsWord := strings.HasSuffix(word, string(commons.MorseSpaceIndicator))
+
+		for _, code := range strings.Fields(strings.TrimSuffix(word, string(commons.MorseSpaceIndicator))) {
+			text.WriteRune(commons.MorseCodeChar(code))
+		}
+
+		if endsWord {
+			text.WriteRune(' ')
+		}
+	}
+
+	return text.String()
+}
+
+// The bits of a packet, the first bit being the highest of the first byte.
+type packetBits struct {
+	bytes  []byte
+	length int
+}
+
+func (bits *packetBits) write(value int, count int) {
+	for i := count - 1; i >= 0; i-- {
+		if bits.length%8 == 0 {
+			bits.bytes = append(bits.bytes, 0)
+		}
+
+		if value&(1<<i) != 0 {
+			bits.bytes[bits.length/8] |= 0x80 >> (bits.length % 8)
+		}
+
+		bits.length += 1
+	}
+}
+
+func (bits packetBits) read(at int, count int) int {
+	value := 0
+	for i := at; i < at+count; i++ {
+		value <<= 1
+		if bits.bytes[i/8]&(0x80>>(i%8)) != 0 {
+			value |= 1
+		}
+	}
+
+	return value
+}
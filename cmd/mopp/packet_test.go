@@ -0,0 +1,107 @@
+package mopp
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// Written out from the MOPP description: 2 bits of version (01), 6 of serial,
+// 6 of speed, then 01 for a dit, 10 for a dah, 00 after a character and 11
+// after a word, padded with zeros.
+var knownPackets = []struct {
+	name   string
+	packet Packet
+	bytes  []byte
+	text   string
+}{
+	{
+		name:   "e",
+		packet: Packet{Serial: 0, Wpm: 20, MorseCode: "._"},
+		bytes:  []byte{0x40, 0x51, 0xc0},
+		text:   "e ",
+	},
+	{
+		name:   "hi",
+		packet: Packet{Serial: 1, Wpm: 20, MorseCode: ".... .._"},
+		bytes:  []byte{0x41, 0x51, 0x54, 0x5c},
+		text:   "hi ",
+	},
+	{
+		name:   "cq at the highest serial and speed",
+		packet: Packet{Serial: 63, Wpm: 63, MorseCode: ",.,. ,,.,_"},
+		bytes:  []byte{0x7f, 0xfe, 0x64, 0xa6, 0xc0},
+		text:   "cq ",
+	},
+}
+
+func TestPacketBytes(t *testing.T) {
+	for _, known := range knownPackets {
+		t.Run(known.name, func(t *testing.T) {
+			if got := known.packet.Bytes(); !bytes.Equal(got, known.bytes) {
+				t.Errorf("Bytes() = %#v, want %#v", got, known.bytes)
+			}
+		})
+	}
+}
+
+func TestParsePacket(t *testing.T) {
+	for _, known := range knownPackets {
+		t.Run(known.name, func(t *testing.T) {
+			packet, err := ParsePacket(known.bytes)
+			if err != nil {
+				t.Fatalf("ParsePacket() error: %v", err)
+			}
+
+			if packet != known.packet {
+				t.Errorf("ParsePacket() = %+v, want %+v", packet, known.packet)
+			}
+
+			if text := packet.Text(); text != known.text {
+				t.Errorf("Text() = %q, want %q", text, known.text)
+			}
+		})
+	}
+}
+
+func TestParsePacketErrors(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":         {},
+		"too short":     {0x40},
+		"other version": {0x80, 0x51, 0xc0},
+	} {
+		if _, err := ParsePacket(data); err == nil {
+			t.Errorf("ParsePacket() of the %v packet is no error, want one", name)
+		}
+	}
+}
+
+func TestTextPackets(t *testing.T) {
+	packets := TextPackets("CQ de, ñ k1", 25, 62)
+
+	// One word each, without the characters that have no morse code, and the
+	// serial going around after 63.
+	want := []Packet{
+		{Serial: 62, Wpm: 25, MorseCode: ",.,. ,,.,_"},
+		{Serial: 63, Wpm: 25, MorseCode: ",.. ._"},
+		{Serial: 0, Wpm: 25, MorseCode: ",., .,,,,_"},
+	}
+
+	if !slices.Equal(packets, want) {
+		t.Fatalf("TextPackets() = %+v, want %+v", packets, want)
+	}
+
+	for _, packet := range packets {
+		parsed, err := ParsePacket(packet.Bytes())
+		if err != nil || parsed != packet {
+			t.Errorf("ParsePacket(Bytes()) = %+v, %v, want %+v", parsed, err, packet)
+		}
+	}
+}
+
+func TestPacketTextUnknownCode(t *testing.T) {
+	packet := Packet{MorseCode: "........ ._"}
+	if text := packet.Text(); text != "?e " {
+		t.Errorf("Text() = %q, want %q", text, "?e ")
+	}
+}
@@ -0,0 +1,99 @@
+package mopp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Clients that sent nothing for this long stop receiving the packets.
+const clientTimeout = time.Minute * 10
+
+// A tiny MOPP server, relaying the packets of each client to the others, like
+// the chat servers for the Morserino-32.
+type Reflector struct {
+	conn    *net.UDPConn
+	clients map[string]reflectorClient
+
+	// Also relays the packets back to their sender, for practicing alone.
+	echo bool
+
+	// Told about the clients joining and leaving, and what they send.
+	log func(format string, args ...any)
+}
+
+type reflectorClient struct {
+	addr     *net.UDPAddr
+	lastSeen time.Time
+}
+
+func ListenReflector(address string, echo bool, log func(format string, args ...any)) (*Reflector, error) {
+	listenAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot find %v: %v", address, err)
+	}
+
+	conn, err := net.ListenUDP("udp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot listen on %v: %v", address, err)
+	}
+
+	return &Reflector{
+		conn:    conn,
+		clients: map[string]reflectorClient{},
+		echo:    echo,
+		log:     log,
+	}, nil
+}
+
+func (reflector *Reflector) Addr() net.Addr {
+	return reflector.conn.LocalAddr()
+}
+
+// Relays the packets until the reflector is closed.
+func (reflector *Reflector) Serve() error {
+	buffer := make([]byte, maxPacketSize)
+
+	for {
+		n, senderAddr, err := reflector.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return fmt.Errorf("Error receiving: %v", err)
+		}
+
+		packet, err := ParsePacket(buffer[:n])
+		if err != nil {
+			continue
+		}
+
+		now := time.Now()
+		sender := senderAddr.String()
+
+		for name, client := range reflector.clients {
+			if now.Sub(client.lastSeen) > clientTimeout {
+				delete(reflector.clients, name)
+				reflector.log("%v left (%v connected)", name, len(reflector.clients))
+			}
+		}
+
+		if _, ok := reflector.clients[sender]; !ok {
+			reflector.clients[sender] = reflectorClient{addr: senderAddr}
+			reflector.log("%v joined (%v connected)", sender, len(reflector.clients))
+		}
+
+		reflector.clients[sender] = reflectorClient{addr: senderAddr, lastSeen: now}
+		reflector.log("%v: %v (%v wpm)", sender, strings.TrimSpace(packet.Text()), packet.Wpm)
+
+		for name, client := range reflector.clients {
+			if name == sender && !reflector.echo {
+				continue
+			}
+
+			reflector.conn.WriteToUDP(buffer[:n], client.addr)
+		}
+	}
+}
+
+func (reflector *Reflector) Close() error {
+	return reflector.conn.Close()
+}
@@ -0,0 +1,137 @@
+package mopp
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func startReflector(t *testing.T, address string, echo bool) *Reflector {
+	t.Helper()
+
+	reflector, err := ListenReflector(address, echo, t.Logf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reflector.Close() })
+
+	go reflector.Serve()
+	return reflector
+}
+
+func dial(t *testing.T, address string) *Client {
+	t.Helper()
+
+	client, err := Dial(address, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func send(t *testing.T, client *Client, text string) {
+	t.Helper()
+
+	if _, err := client.Send(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Receives the text of the next packet, failing after a second.
+func receive(t *testing.T, client *Client) string {
+	t.Helper()
+
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	packet, err := client.Receive()
+	if err != nil {
+		t.Fatalf("Receive() error: %v", err)
+	}
+
+	return packet.Text()
+}
+
+func expectNothing(t *testing.T, client *Client) {
+	t.Helper()
+
+	client.conn.SetReadDeadline(time.Now().Add(time.Millisecond * 200))
+	if packet, err := client.Receive(); err == nil {
+		t.Errorf("Receive() = %q, want nothing", packet.Text())
+	}
+}
+
+func TestReflectorRelays(t *testing.T) {
+	reflector := startReflector(t, "127.0.0.1:0", false)
+	address := reflector.Addr().String()
+
+	alice, bob := dial(t, address), dial(t, address)
+
+	// The reflector knows of the clients once they sent something.
+	send(t, alice, "hi")
+	send(t, bob, "hi")
+
+	if text := receive(t, alice); text != "hi " {
+		t.Errorf("alice received %q, want the hi of bob", text)
+	}
+
+	send(t, alice, "cq de")
+	for _, want := range []string{"cq ", "de "} {
+		if text := receive(t, bob); text != want {
+			t.Errorf("bob received %q, want %q", text, want)
+		}
+	}
+
+	// Nothing comes back to the sender.
+	expectNothing(t, alice)
+}
+
+func TestReflectorEcho(t *testing.T) {
+	reflector := startReflector(t, "127.0.0.1:0", true)
+	alice := dial(t, reflector.Addr().String())
+
+	send(t, alice, "test")
+	if text := receive(t, alice); text != "test " {
+		t.Errorf("alice received %q, want the echo of test", text)
+	}
+}
+
+func TestClientAfterRefused(t *testing.T) {
+	// A free port, with nothing listening on it for now.
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.LocalAddr().String()
+	listener.Close()
+
+	alice := dial(t, address)
+	send(t, alice, "hi")
+
+	alice.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := alice.Receive(); err == nil || errors.Is(err, net.ErrClosed) {
+		t.Fatalf("Receive() without a server = %v, want an error other than closed", err)
+	}
+
+	// Once the reflector runs, the same client receives again.
+	startReflector(t, address, false)
+	bob := dial(t, address)
+
+	send(t, alice, "hi")
+	send(t, bob, "qrv")
+
+	if text := receive(t, alice); text != "qrv " {
+		t.Errorf("alice received %q, want the qrv of bob", text)
+	}
+}
+
+func TestClientClosed(t *testing.T) {
+	reflector := startReflector(t, "127.0.0.1:0", false)
+	alice := dial(t, reflector.Addr().String())
+	alice.Close()
+
+	if _, err := alice.Receive(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Receive() after Close() = %v, want net.ErrClosed", err)
+	}
+}
@@ -0,0 +1,84 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+const dialTimeout = time.Second * 5
+
+// A player's connection to the host of a race.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	encoder *json.Encoder
+
+	Race Race
+}
+
+// Joins the race of the host under the name, returning once welcomed.
+func Join(address string, name string) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot connect to %v: %v", address, err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxMessageSize)
+
+	client := &Client{
+		conn:    conn,
+		scanner: scanner,
+		encoder: json.NewEncoder(conn),
+	}
+
+	if err := client.encoder.Encode(message{Type: helloMessage, Name: name}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Error sending: %v", err)
+	}
+
+	welcome, err := client.receive()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if welcome.Type != welcomeMessage || welcome.Race == nil {
+		conn.Close()
+		return nil, fmt.Errorf("Error: %v did not welcome us.", address)
+	}
+
+	client.Race = *welcome.Race
+	return client, nil
+}
+
+// Waits for the next message of the host. The errors it sends are returned as
+// such.
+func (client *Client) receive() (message, error) {
+	msg, err := readMessage(client.scanner)
+	if err != nil {
+		return message{}, err
+	}
+
+	if msg.Type == errorMessage {
+		return message{}, fmt.Errorf("%v", msg.Error)
+	}
+
+	return msg, nil
+}
+
+// Sends the answer to the word at the index, which the host grades.
+func (client *Client) Answer(index int, answer string) error {
+	if err := client.encoder.Encode(message{Type: answerMessage, Index: index, Answer: answer}); err != nil {
+		return fmt.Errorf("Error sending: %v", err)
+	}
+
+	return nil
+}
+
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
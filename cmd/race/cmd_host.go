@@ -0,0 +1,106 @@
+package race
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	HostCmd.Flags().String("name", "", "Name to race under. The user of dihdah (or the one logged in) by default.")
+	HostCmd.Flags().StringP("kind", "k", "words", "What to decode: words, or groups of random letters.")
+	HostCmd.Flags().Uint16P("level", "l", 1, "Level of the words (the word length, like 'decode words') or of the letters of the groups (like 'decode letter').")
+	HostCmd.Flags().Uint16P("iterations", "n", 10, "Words (or groups) in the race.")
+	HostCmd.Flags().Uint16("group-size", 5, "Letters in each group, for --kind groups.")
+	HostCmd.Flags().Float64P("speed", "s", 1, "Speed ratio the morse code is played at.")
+	HostCmd.Flags().Int64("seed", 0, "Picks the same words again. A new seed by default.")
+	HostCmd.Flags().Uint16P("port", "p", DefaultPort, "Port to listen on.")
+	HostCmd.Flags().String("host", "0.0.0.0", "Address to listen on. The default is reachable from the local network; 127.0.0.1 keeps it to this computer.")
+}
+
+var HostCmd = &cobra.Command{
+	Use:   "host",
+	Short: "Hosts a decoding race, which the others join over the local network.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		if len(name) == 0 {
+			name = defaultName()
+		}
+
+		kind, _ := cmd.Flags().GetString("kind")
+		level, _ := cmd.Flags().GetUint16("level")
+		groupSize, _ := cmd.Flags().GetUint16("group-size")
+		seed, _ := cmd.Flags().GetInt64("seed")
+
+		iterations, _ := cmd.Flags().GetUint16("iterations")
+		if iterations == 0 {
+			return fmt.Errorf("Error: --iterations is set to zero.")
+		}
+
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		if speed <= 0 {
+			return fmt.Errorf("Error: --speed should be more than zero.")
+		}
+
+		race, err := NewRace(kind, int(level), int(iterations), int(groupSize), speed, seed)
+		if err != nil {
+			return err
+		}
+
+		port, _ := cmd.Flags().GetUint16("port")
+		host, _ := cmd.Flags().GetString("host")
+
+		// The TUI takes the terminal, and shows who joined by itself.
+		server, err := Listen(net.JoinHostPort(host, strconv.Itoa(int(port))), race, func(string, ...any) {})
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+
+		go server.Serve()
+
+		client, err := Join(server.JoinAddr(), name)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		p := tea.NewProgram(NewRaceModel(client, name, server.Start))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'race host' command hosts a decoding race on this computer, and takes part in it.
+The others join with 'dihdah race join <this computer's address>', and the race starts
+when the host presses enter.
+
+# How it works
+
+=========================================================
+Decoding race: 10 words of up to 5 letters (seed 1718)
+
+ #   Player               Correct   Answered  Time
+ 1   alice (you)          4         5/10      21.3s
+ 2   bob                  3         4/10      18.9s
+
+Word 6 of 10
+sea: correct
+> ??????????
+
+(space to repeat sound, enter to answer, escape to go back, ctrl+c to exit)
+=========================================================
+
+Everyone hears the same words (or groups of letters with --kind groups), picked by the
+host with --seed, and played by their own computer at --speed. The host grades the
+answers and times them from the start of the race. The standings are by the most words
+right, then the most answered, then the least time.
+
+The race listens on all the addresses of this computer by default, so that it can be
+joined from the local network. To keep it to this computer, use --host 127.0.0.1.`,
+}
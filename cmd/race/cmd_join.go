@@ -0,0 +1,56 @@
+package race
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	JoinCmd.Flags().String("name", "", "Name to race under. The user of dihdah (or the one logged in) by default.")
+}
+
+var JoinCmd = &cobra.Command{
+	Use:   "join [host[:port]]",
+	Short: "Joins the decoding race of a host.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		if len(name) == 0 {
+			name = defaultName()
+		}
+
+		address := "127.0.0.1"
+		if len(args) == 1 {
+			address = args[0]
+		}
+
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, strconv.Itoa(DefaultPort))
+		}
+
+		client, err := Join(address, name)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		p := tea.NewProgram(NewRaceModel(client, name, nil))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("Error running the program: %v", err)
+		}
+
+		return nil
+	},
+	Long: `The 'race join' command joins the decoding race of a host (the one on this computer
+by default), before it starts. The race starts when the host says so; until then, the
+players that joined are shown.
+
+  $ dihdah race join 192.168.1.20 --name bob
+
+The words of the race are played by this computer, so everyone hears them alike.
+See 'dihdah race host --help' for how the race goes.`,
+}
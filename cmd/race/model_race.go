@@ -0,0 +1,277 @@
+package race
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+type raceModel struct {
+	client *Client
+	name   string
+
+	// Only the host starts the race.
+	start func()
+
+	session   *drill.Session
+	started   bool
+	standings []Standing
+
+	input textinput.Model
+
+	// The index of the word playing, -1 if none. The next word waits for it
+	// to end.
+	playing int
+
+	err error
+}
+
+type serverMsg message
+type playedMsg struct{}
+type receiveErrMsg struct {
+	err error
+}
+
+func NewRaceModel(client *Client, name string, start func()) *raceModel {
+	input := textinput.New()
+	input.CharLimit = 20
+	input.Width = 25
+	input.Placeholder = "??????????"
+	input.Focus()
+
+	return &raceModel{
+		client:  client,
+		name:    name,
+		start:   start,
+		session: drill.NewSession(drill.WordItems(client.Race.Words), drill.CheckText),
+		input:   input,
+		playing: -1,
+	}
+}
+
+func (_m *raceModel) Init() tea.Cmd {
+	return tea.Batch(_m.waitForMessage(), textinput.Blink)
+}
+
+func (_m *raceModel) waitForMessage() tea.Cmd {
+	return func() tea.Msg {
+		msg, err := _m.client.receive()
+		if err != nil {
+			return receiveErrMsg{err: err}
+		}
+
+		return serverMsg(msg)
+	}
+}
+
+// Plays the current word, once the one playing has ended.
+func (_m *raceModel) play() tea.Cmd {
+	current, _ := _m.session.Progress()
+	item, ok := _m.session.Next()
+	if !ok || _m.playing >= 0 {
+		return nil
+	}
+
+	_m.playing = current
+	speed := _m.client.Race.Speed
+
+	return func() tea.Msg {
+		commons.PlayMorseCode(item.MorseCode, speed, commons.DefaultPitch)
+		return playedMsg{}
+	}
+}
+
+// Everyone still racing has answered every word.
+func (_m *raceModel) raceOver() bool {
+	if !_m.session.Done() {
+		return false
+	}
+
+	for _, standing := range _m.standings {
+		if !standing.Done && !standing.Left {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (_m *raceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case serverMsg:
+		switch msg.Type {
+		case standingsMessage:
+			_m.standings = msg.Standings
+		case startMessage:
+			_m.started = true
+			_m.session.Start()
+			return _m, tea.Batch(_m.waitForMessage(), _m.play())
+		}
+
+		return _m, _m.waitForMessage()
+	case playedMsg:
+		current, _ := _m.session.Progress()
+		replayed := _m.playing == current
+
+		_m.playing = -1
+		if replayed {
+			return _m, nil
+		}
+
+		return _m, _m.play()
+	case receiveErrMsg:
+		_m.err = msg.err
+		return _m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, commons.Keys.Back), key.Matches(msg, commons.Keys.Quit):
+			return _m, tea.Quit
+		}
+
+		if !_m.started {
+			if key.Matches(msg, commons.Keys.Confirm) && _m.start != nil {
+				_m.start()
+			}
+
+			return _m, nil
+		}
+
+		if _m.session.Done() {
+			return _m, nil
+		}
+
+		switch {
+		case key.Matches(msg, commons.Keys.Replay):
+			_m.session.Replay()
+			return _m, _m.play()
+		case key.Matches(msg, commons.Keys.Confirm):
+			given := _m.input.Value()
+			if len(given) == 0 {
+				_m.session.Replay()
+				return _m, _m.play()
+			}
+
+			current, _ := _m.session.Progress()
+			_m.session.Answer(given)
+			_m.input.Reset()
+
+			if err := _m.client.Answer(current, given); err != nil {
+				_m.err = err
+			}
+
+			return _m, _m.play()
+		}
+	}
+
+	var cmd tea.Cmd
+	_m.input, cmd = _m.input.Update(msg)
+	return _m, cmd
+}
+
+var standingColumns = []table.Column{
+	{Title: "#", Width: 3},
+	{Title: "Player", Width: maxNameLength},
+	{Title: "Correct", Width: 8},
+	{Title: "Answered", Width: 9},
+	{Title: "Time", Width: 8},
+}
+
+func (_m *raceModel) standingsTable() string {
+	total := len(_m.client.Race.Words)
+
+	rows := []table.Row{}
+	for i, standing := range _m.standings {
+		name := standing.Name
+		if name == _m.name {
+			name += " (you)"
+		}
+
+		status := fmt.Sprintf("%v/%v", standing.Answered, total)
+		switch {
+		case standing.Left:
+			status = "left"
+		case standing.Done:
+			status = "done"
+		}
+
+		rows = append(rows, table.Row{
+			fmt.Sprint(i + 1),
+			name,
+			fmt.Sprint(standing.Correct),
+			status,
+			standing.Time().Round(time.Second / 10).String(),
+		})
+	}
+
+	standingsTable := table.New(
+		table.WithColumns(standingColumns),
+		table.WithRows(rows),
+		table.WithHeight(len(rows)+1),
+		table.WithStyles(commons.CurrentTheme.TableStyles()),
+	)
+
+	return standingsTable.View()
+}
+
+func (_m *raceModel) View() string {
+	race := _m.client.Race
+	current, total := _m.session.Progress()
+
+	status := ""
+	help := commons.KeyHelp(commons.Keys.Back, commons.Keys.Quit)
+
+	switch {
+	case !_m.started && _m.start != nil:
+		status = fmt.Sprintf("Waiting for the players to join (%v so far).", len(_m.standings))
+		help = commons.KeyHelp(commons.WithDesc(commons.Keys.Confirm, "to start the race"), commons.Keys.Back, commons.Keys.Quit)
+	case !_m.started:
+		status = fmt.Sprintf("Waiting for the host to start the race (%v players).", len(_m.standings))
+	case _m.raceOver():
+		status = "The race is over!"
+	case _m.session.Done():
+		status = "You are done, waiting for the others..."
+	default:
+		status = fmt.Sprintf("Word %v of %v", current+1, total)
+		help = commons.KeyHelp(commons.Keys.Replay, commons.WithDesc(commons.Keys.Confirm, "to answer"), commons.Keys.Back, commons.Keys.Quit)
+	}
+
+	lastAnswer := ""
+	if answer, ok := _m.session.LastAnswer(); ok {
+		if answer.Correct {
+			lastAnswer = commons.CurrentTheme.Correct().Render(fmt.Sprintf("%v: correct", answer.Item.Text))
+		} else {
+			lastAnswer = commons.CurrentTheme.Wrong().Render(fmt.Sprintf("%v: wrong (you typed %v)", answer.Item.Text, answer.Given))
+		}
+	}
+
+	input := ""
+	if _m.started && !_m.session.Done() {
+		input = _m.input.View()
+	}
+
+	if _m.err != nil {
+		status = _m.err.Error()
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		fmt.Sprintf("Decoding race: %v (seed %v)", race.Title, race.Seed),
+		"",
+		_m.standingsTable(),
+		"",
+		status,
+		lastAnswer,
+		input,
+		"",
+		fmt.Sprintf("(%v)", help),
+		"",
+	)
+}
@@ -0,0 +1,127 @@
+package race
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/cmd/decode"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// What is raced: the same words for everyone, played at the same speed. The
+// groups of letters are words too.
+type Race struct {
+	Title string   `json:"title"`
+	Words []string `json:"words"`
+	Speed float64  `json:"speed"`
+	Seed  int64    `json:"seed"`
+}
+
+// How a player is doing in the race, as the host counts it.
+type Standing struct {
+	Name     string `json:"name"`
+	Correct  int    `json:"correct"`
+	Answered int    `json:"answered"`
+
+	// From the start of the race to the last answer.
+	TimeMs int64 `json:"timeMs"`
+
+	Done bool `json:"done,omitempty"`
+	Left bool `json:"left,omitempty"`
+}
+
+func (standing Standing) Time() time.Duration {
+	return time.Duration(standing.TimeMs) * time.Millisecond
+}
+
+// The most correct answers first, then the most answers, then the fastest.
+func compareStandings(a, b Standing) int {
+	switch {
+	case a.Correct != b.Correct:
+		return b.Correct - a.Correct
+	case a.Answered != b.Answered:
+		return b.Answered - a.Answered
+	case a.TimeMs != b.TimeMs:
+		return int(a.TimeMs - b.TimeMs)
+	}
+
+	return strings.Compare(a.Name, b.Name)
+}
+
+// The messages between the host and the players, one JSON object per line. A
+// player says hello with its name, and is welcomed with the race. The host
+// sends the standings whenever they change, and start when the race starts;
+// the players send their answers in order.
+type message struct {
+	Type string `json:"type"`
+
+	Name      string     `json:"name,omitempty"`
+	Race      *Race      `json:"race,omitempty"`
+	Standings []Standing `json:"standings,omitempty"`
+	Index     int        `json:"index,omitempty"`
+	Answer    string     `json:"answer,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+const (
+	helloMessage     = "hello"
+	welcomeMessage   = "welcome"
+	standingsMessage = "standings"
+	startMessage     = "start"
+	answerMessage    = "answer"
+	errorMessage     = "error"
+)
+
+// Picks the words of a race: words of the word file up to the word length of
+// the level, or groups of random letters of the levels up to it.
+func NewRace(kind string, level int, count int, groupSize int, speed float64, seed int64) (Race, error) {
+	random, seed := commons.NewRandom(seed)
+	race := Race{Speed: speed, Seed: seed}
+
+	switch kind {
+	case "words":
+		if level < 1 || level > len(decode.MaxWordLenPerLevel) {
+			return Race{}, fmt.Errorf("Error: the level for words should be from 1 to %v.", len(decode.MaxWordLenPerLevel))
+		}
+
+		allWords, err := commons.LoadWords(strings.NewReader(assets.Words))
+		if err != nil {
+			return Race{}, fmt.Errorf("Error reading through the default word file: %v", err)
+		}
+
+		wordLength := decode.MaxWordLenPerLevel[level-1]
+		race.Words = commons.PickWords(random, decode.WordPool(allWords, wordLength), count)
+		race.Title = fmt.Sprintf("%v words of up to %v letters", len(race.Words), wordLength)
+	case "groups":
+		if level < 1 || level > len(decode.NewLettersPerLevel) {
+			return Race{}, fmt.Errorf("Error: the level for groups should be from 1 to %v.", len(decode.NewLettersPerLevel))
+		}
+
+		if groupSize < 1 {
+			return Race{}, fmt.Errorf("Error: --group-size should be at least 1.")
+		}
+
+		letters := decode.LevelLetters(level)
+		for range count {
+			race.Words = append(race.Words, letterGroup(random, letters, groupSize))
+		}
+
+		race.Title = fmt.Sprintf("%v groups of %v letters of level %v", count, groupSize, level)
+	default:
+		return Race{}, fmt.Errorf("Error: unknown kind %q, it should be words or groups.", kind)
+	}
+
+	return race, nil
+}
+
+func letterGroup(random *rand.Rand, letters string, size int) string {
+	group := make([]byte, size)
+	for i := range group {
+		group[i] = letters[random.Intn(len(letters))]
+	}
+
+	return string(group)
+}
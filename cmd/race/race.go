@@ -0,0 +1,48 @@
+package race
+
+import (
+	"os/user"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+const DefaultPort = 7474
+
+func init() {
+	Cmd.AddCommand(HostCmd)
+	Cmd.AddCommand(JoinCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "race",
+	Short: "Head-to-head decoding races over the local network.",
+	Long: `The 'race' command runs decoding races between a few players over the local network.
+The host picks the words (or the groups of letters) of the race, everyone hears the same
+ones on their own computer, and the fastest to decode them right wins.
+
+These are the things the user can do in here:
+  - 'dihdah race host': Hosts a race, and takes part in it.
+  - 'dihdah race join [host[:port]]': Joins the race of a host.
+
+To try it on one computer, host in one terminal and join in another:
+
+  $ dihdah race host --name alice
+  $ dihdah race join --name bob          (in another terminal)
+
+The port is 7474 unless given otherwise.`,
+}
+
+// The name to race under when none is given: the user of dihdah, or else the
+// one logged in.
+func defaultName() string {
+	if name := commons.CurrentUser(); name != commons.DefaultUser {
+		return name
+	}
+
+	if current, err := user.Current(); err == nil && len(current.Username) != 0 {
+		return current.Username[:min(len(current.Username), maxNameLength)]
+	}
+
+	return "player"
+}
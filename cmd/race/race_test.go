@@ -0,0 +1,290 @@
+package race
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, race Race) *Server {
+	t.Helper()
+
+	// The players leave after the test too, as the server is closed.
+	server, err := Listen("127.0.0.1:0", race, func(string, ...any) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	go server.Serve()
+	return server
+}
+
+func join(t *testing.T, server *Server, name string) *Client {
+	t.Helper()
+
+	client, err := Join(server.Addr().String(), name)
+	if err != nil {
+		t.Fatalf("Join(%v) error: %v", name, err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// Receives the messages until one of the type, failing after a second.
+func receiveUntil(t *testing.T, client *Client, messageType string, until func(msg message) bool) message {
+	t.Helper()
+
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	defer client.conn.SetReadDeadline(time.Time{})
+
+	for {
+		msg, err := client.receive()
+		if err != nil {
+			t.Fatalf("waiting for %v: %v", messageType, err)
+		}
+
+		if msg.Type == messageType && (until == nil || until(msg)) {
+			return msg
+		}
+	}
+}
+
+// The standings once the player answered as many words.
+func standingsAfter(t *testing.T, client *Client, name string, answered int) []Standing {
+	t.Helper()
+
+	msg := receiveUntil(t, client, standingsMessage, func(msg message) bool {
+		index := slices.IndexFunc(msg.Standings, func(standing Standing) bool { return standing.Name == name })
+		return index >= 0 && msg.Standings[index].Answered == answered
+	})
+
+	return msg.Standings
+}
+
+func findStanding(standings []Standing, name string) Standing {
+	index := slices.IndexFunc(standings, func(standing Standing) bool { return standing.Name == name })
+	if index < 0 {
+		return Standing{}
+	}
+
+	return standings[index]
+}
+
+func TestRace(t *testing.T) {
+	race := Race{Title: "test", Words: []string{"cq", "de", "k"}, Speed: 1, Seed: 1}
+	server := startServer(t, race)
+
+	alice := join(t, server, "alice")
+	bob := join(t, server, "bob")
+
+	if !slices.Equal(alice.Race.Words, race.Words) || bob.Race.Title != "test" {
+		t.Errorf("welcomed with %+v and %+v, want %+v", alice.Race, bob.Race, race)
+	}
+
+	players := receiveUntil(t, alice, standingsMessage, func(msg message) bool { return len(msg.Standings) == 2 })
+	if findStanding(players.Standings, "bob").Name != "bob" {
+		t.Errorf("standings = %+v, want alice and bob", players.Standings)
+	}
+
+	// The answers before the start are not graded.
+	alice.Answer(0, "cq")
+
+	server.Start()
+	receiveUntil(t, alice, startMessage, nil)
+	receiveUntil(t, bob, startMessage, nil)
+
+	alice.Answer(0, "CQ")
+	standings := standingsAfter(t, bob, "alice", 1)
+	if standing := findStanding(standings, "alice"); standing.Correct != 1 || standing.TimeMs < 0 {
+		t.Errorf("alice = %+v, want 1 of 1 right", standing)
+	}
+
+	// Answers out of order are ignored: again the first word, then the third
+	// before the second.
+	alice.Answer(0, "cq")
+	alice.Answer(2, "k")
+	alice.Answer(1, "dx")
+	standings = standingsAfter(t, bob, "alice", 2)
+
+	if standing := findStanding(standings, "alice"); standing.Correct != 1 || standing.Done {
+		t.Errorf("alice = %+v, want 1 of 2 right", standing)
+	}
+
+	alice.Answer(2, "k")
+	standings = standingsAfter(t, bob, "alice", 3)
+	if standing := findStanding(standings, "alice"); standing.Correct != 2 || !standing.Done {
+		t.Errorf("alice = %+v, want done with 2 of 3 right", standing)
+	}
+
+	// Answers after the last word are ignored too.
+	alice.Answer(3, "k")
+
+	for i, word := range race.Words {
+		bob.Answer(i, word)
+	}
+
+	standings = standingsAfter(t, alice, "bob", 3)
+	if names := []string{standings[0].Name, standings[1].Name}; !slices.Equal(names, []string{"bob", "alice"}) {
+		t.Errorf("standings = %+v, want bob first with all right", standings)
+	}
+
+	if standing := findStanding(standings, "alice"); standing.Answered != 3 || standing.Correct != 2 {
+		t.Errorf("alice = %+v, want still 2 of 3 right", standing)
+	}
+
+	bob.Close()
+	standings = receiveUntil(t, alice, standingsMessage, func(msg message) bool {
+		return findStanding(msg.Standings, "bob").Left
+	}).Standings
+
+	if standing := findStanding(standings, "bob"); standing.Correct != 3 {
+		t.Errorf("bob = %+v, want kept in the standings after leaving", standing)
+	}
+}
+
+func TestJoinRejections(t *testing.T) {
+	server := startServer(t, Race{Words: []string{"cq"}})
+	join(t, server, "alice")
+
+	for _, name := range []string{"Alice", "", strings.Repeat("a", maxNameLength+1)} {
+		if _, err := Join(server.Addr().String(), name); err == nil {
+			t.Errorf("Join(%q) is no error, want one", name)
+		}
+	}
+
+	// Leaving before the start frees the name.
+	bob := join(t, server, "bob")
+	bob.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		client, err := Join(server.Addr().String(), "bob")
+		if err == nil {
+			client.Close()
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("Join(bob) after bob left = %v, want no error", err)
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	server.Start()
+
+	if _, err := Join(server.Addr().String(), "carol"); err == nil || !strings.Contains(err.Error(), "started") {
+		t.Errorf("Join(carol) after the start = %v, want an error", err)
+	}
+}
+
+func TestJoinAddr(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:0", "0.0.0.0:0"} {
+		server, err := Listen(address, Race{Words: []string{"cq"}}, func(string, ...any) {})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go server.Serve()
+
+		client, err := Join(server.JoinAddr(), "alice")
+		if err != nil {
+			t.Errorf("Join(%v) listening on %v error: %v", server.JoinAddr(), address, err)
+		} else {
+			client.Close()
+		}
+
+		server.Close()
+	}
+}
+
+func TestCompareStandings(t *testing.T) {
+	standings := []Standing{
+		{Name: "slow", Correct: 3, Answered: 3, TimeMs: 9000},
+		{Name: "behind", Correct: 2, Answered: 2, TimeMs: 1000},
+		{Name: "fast", Correct: 3, Answered: 3, TimeMs: 5000},
+		{Name: "wrong", Correct: 2, Answered: 3, TimeMs: 2000},
+		{Name: "b-tied", Correct: 1, Answered: 1, TimeMs: 100},
+		{Name: "a-tied", Correct: 1, Answered: 1, TimeMs: 100},
+	}
+
+	slices.SortFunc(standings, compareStandings)
+
+	names := []string{}
+	for _, standing := range standings {
+		names = append(names, standing.Name)
+	}
+
+	// The most right, then the most answered, then the fastest, then by name.
+	want := []string{"fast", "slow", "wrong", "behind", "a-tied", "b-tied"}
+	if !slices.Equal(names, want) {
+		t.Errorf("sorted = %v, want %v", names, want)
+	}
+}
+
+func TestNewRace(t *testing.T) {
+	tests := []struct {
+		kind      string
+		level     int
+		groupSize int
+	}{
+		{"words", 2, 0},
+		{"groups", 3, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kind, func(t *testing.T) {
+			first, err := NewRace(test.kind, test.level, 8, test.groupSize, 1, 42)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, _ := NewRace(test.kind, test.level, 8, test.groupSize, 1, 42)
+			if !slices.Equal(first.Words, second.Words) || first.Seed != 42 {
+				t.Errorf("words with seed 42 = %v, then %v, want the same", first.Words, second.Words)
+			}
+
+			other, _ := NewRace(test.kind, test.level, 8, test.groupSize, 1, 43)
+			if slices.Equal(first.Words, other.Words) {
+				t.Errorf("words with seeds 42 and 43 are both %v, want others", first.Words)
+			}
+
+			if len(first.Words) != 8 {
+				t.Errorf("%v words, want 8", len(first.Words))
+			}
+		})
+	}
+}
+
+func TestNewRaceGroups(t *testing.T) {
+	race, err := NewRace("groups", 1, 4, 3, 1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range race.Words {
+		if len(group) != 3 || strings.Trim(group, "the") != "" {
+			t.Errorf("group %q, want 3 letters of level 1 (t, h and e)", group)
+		}
+	}
+}
+
+func TestNewRaceErrors(t *testing.T) {
+	for _, args := range []struct {
+		kind             string
+		level, groupSize int
+	}{
+		{"letters", 1, 5},
+		{"words", 0, 5},
+		{"words", 5, 5},
+		{"groups", 0, 5},
+		{"groups", 1, 0},
+	} {
+		if _, err := NewRace(args.kind, args.level, 5, args.groupSize, 1, 1); err == nil {
+			t.Errorf("NewRace(%v, level %v, group size %v) is no error, want one", args.kind, args.level, args.groupSize)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noAbbreviation/dihdah/drill"
+)
+
+const (
+	maxMessageSize = 1 << 20
+	writeTimeout   = time.Second * 5
+	maxNameLength  = 20
+)
+
+// Hosts a race: welcomes the players until the race starts, grades their
+// answers, and keeps everyone told of the standings.
+type Server struct {
+	listener net.Listener
+	race     Race
+	items    []drill.Item
+
+	mutex   sync.Mutex
+	players []*racer
+	started bool
+	start   time.Time
+
+	// Told about the players joining and leaving, and the start.
+	log func(format string, args ...any)
+}
+
+type racer struct {
+	conn     net.Conn
+	encoder  *json.Encoder
+	standing Standing
+}
+
+func Listen(address string, race Race, log func(format string, args ...any)) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("Error: cannot listen on %v: %v", address, err)
+	}
+
+	return &Server{
+		listener: listener,
+		race:     race,
+		items:    drill.WordItems(race.Words),
+		log:      log,
+	}, nil
+}
+
+func (server *Server) Addr() net.Addr {
+	return server.listener.Addr()
+}
+
+// Where the host joins its own race: the address listened on, or the loopback
+// one when listening on every address (which cannot be dialed everywhere).
+func (server *Server) JoinAddr() string {
+	addr, ok := server.Addr().(*net.TCPAddr)
+	if !ok || !addr.IP.IsUnspecified() {
+		return server.Addr().String()
+	}
+
+	loopback := net.IPv4(127, 0, 0, 1)
+	if addr.IP.To4() == nil {
+		loopback = net.IPv6loopback
+	}
+
+	return net.JoinHostPort(loopback.String(), strconv.Itoa(addr.Port))
+}
+
+// Welcomes the players until the server is closed.
+func (server *Server) Serve() error {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return fmt.Errorf("Error accepting: %v", err)
+		}
+
+		go server.handle(conn)
+	}
+}
+
+// Starts the race for the players that joined. Nobody can join after it.
+func (server *Server) Start() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.started {
+		return
+	}
+
+	server.started = true
+	server.start = time.Now()
+	server.log("The race started with %v players", len(server.players))
+
+	server.broadcast(message{Type: startMessage})
+}
+
+func (server *Server) Close() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	for _, player := range server.players {
+		player.conn.Close()
+	}
+
+	return server.listener.Close()
+}
+
+func (server *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxMessageSize)
+
+	encoder := json.NewEncoder(conn)
+
+	hello, err := readMessage(scanner)
+	if err != nil || hello.Type != helloMessage {
+		return
+	}
+
+	player, err := server.join(conn, encoder, strings.TrimSpace(hello.Name))
+	if err != nil {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		encoder.Encode(message{Type: errorMessage, Error: err.Error()})
+		return
+	}
+
+	for {
+		msg, err := readMessage(scanner)
+		if err != nil {
+			break
+		}
+
+		if msg.Type == answerMessage {
+			server.answer(player, msg.Index, msg.Answer)
+		}
+	}
+
+	server.leave(player)
+}
+
+func (server *Server) join(conn net.Conn, encoder *json.Encoder, name string) (*racer, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.started {
+		return nil, fmt.Errorf("Error: the race has already started.")
+	}
+
+	if len(name) == 0 || len(name) > maxNameLength {
+		return nil, fmt.Errorf("Error: the name should be from 1 to %v characters.", maxNameLength)
+	}
+
+	for _, player := range server.players {
+		if strings.EqualFold(player.standing.Name, name) {
+			return nil, fmt.Errorf("Error: %v is already in the race, pick another name.", name)
+		}
+	}
+
+	joined := &racer{conn: conn, encoder: encoder, standing: Standing{Name: name}}
+	server.players = append(server.players, joined)
+	server.log("%v joined from %v (%v in the race)", name, conn.RemoteAddr(), len(server.players))
+
+	server.send(joined, message{Type: welcomeMessage, Race: &server.race})
+	server.broadcast(message{Type: standingsMessage, Standings: server.standings()})
+
+	return joined, nil
+}
+
+// Grades the answer, if it is the one to the next word of the player.
+func (server *Server) answer(player *racer, index int, answer string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	standing := &player.standing
+	if !server.started || standing.Done || index != standing.Answered {
+		return
+	}
+
+	if drill.CheckText(server.items[index], answer) {
+		standing.Correct += 1
+	}
+
+	standing.Answered += 1
+	standing.TimeMs = time.Since(server.start).Milliseconds()
+	standing.Done = standing.Answered == len(server.items)
+
+	if standing.Done {
+		server.log("%v finished with %v/%v correct in %v", standing.Name, standing.Correct, standing.Answered, standing.Time())
+	}
+
+	server.broadcast(message{Type: standingsMessage, Standings: server.standings()})
+}
+
+// The players leaving before the start are forgotten; the ones leaving the race
+// are kept in the standings.
+func (server *Server) leave(player *racer) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.started {
+		player.standing.Left = true
+	} else {
+		server.players = slices.DeleteFunc(server.players, func(p *racer) bool {
+			return p == player
+		})
+	}
+
+	server.log("%v left (%v in the race)", player.standing.Name, len(server.players))
+	server.broadcast(message{Type: standingsMessage, Standings: server.standings()})
+}
+
+func (server *Server) standings() []Standing {
+	standings := []Standing{}
+	for _, player := range server.players {
+		standings = append(standings, player.standing)
+	}
+
+	slices.SortFunc(standings, compareStandings)
+	return standings
+}
+
+// Sends to every player still there. The mutex is held.
+func (server *Server) broadcast(msg message) {
+	for _, player := range server.players {
+		if !player.standing.Left {
+			server.send(player, msg)
+		}
+	}
+}
+
+// A player too slow to take the message is dropped, rather than holding up
+// the race. Its connection being closed, it leaves.
+func (server *Server) send(player *racer, msg message) {
+	player.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := player.encoder.Encode(msg); err != nil {
+		player.conn.Close()
+	}
+}
+
+func readMessage(scanner *bufio.Scanner) (message, error) {
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return message{}, fmt.Errorf("Error receiving: %v", err)
+		}
+
+		return message{}, fmt.Errorf("Error: the connection was closed.")
+	}
+
+	msg := message{}
+	if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+		return message{}, fmt.Errorf("Error reading a message: %v", err)
+	}
+
+	return msg, nil
+}
@@ -3,9 +3,14 @@ package cmd
 import (
 	"os"
 
+	"github.com/noAbbreviation/dihdah/cmd/config"
 	"github.com/noAbbreviation/dihdah/cmd/decode"
 	"github.com/noAbbreviation/dihdah/cmd/encode"
+	"github.com/noAbbreviation/dihdah/cmd/mopp"
+	"github.com/noAbbreviation/dihdah/cmd/race"
+	"github.com/noAbbreviation/dihdah/cmd/users"
 	"github.com/noAbbreviation/dihdah/ui"
+	"github.com/noAbbreviation/dihdah/web"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +21,9 @@ var Cmd = &cobra.Command{
 	Short:   "Drills for learning morse code characters",
 	Version: versionString,
 	Long:    ui.RootCmdLong,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return config.Apply(cmd)
+	},
 }
 
 func Execute() {
@@ -27,8 +35,16 @@ func Execute() {
 
 func init() {
 	Cmd.CompletionOptions.DisableDefaultCmd = true
+	Cmd.PersistentFlags().String("profile", "", "Profile of the config file to use, like beginner, contest or evening.")
+	Cmd.PersistentFlags().String("theme", "dark", "Colors of the drills: dark, light, high-contrast, or custom (see 'dihdah config --help').")
+	Cmd.PersistentFlags().String("user", "", "User to train as, each with their own settings and history. See 'dihdah users'.")
 
 	Cmd.AddCommand(encode.Cmd)
 	Cmd.AddCommand(decode.Cmd)
 	Cmd.AddCommand(ui.Cmd)
+	Cmd.AddCommand(config.Cmd)
+	Cmd.AddCommand(users.Cmd)
+	Cmd.AddCommand(web.Cmd)
+	Cmd.AddCommand(mopp.Cmd)
+	Cmd.AddCommand(race.Cmd)
 }
@@ -0,0 +1,22 @@
+package users
+
+import (
+	"fmt"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var AddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Adds a user, with letters, numbers, '-' and '_' in the name.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := commons.AddUser(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Added %v. Train as them with --user %v.\n", args[0], args[0])
+		return nil
+	},
+}
@@ -0,0 +1,38 @@
+package users
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var ExportCmd = &cobra.Command{
+	Use:   "export <name> [file]",
+	Short: "Saves the files of a user into a zip file, dihdah-<name>.zip by default.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		fileName := fmt.Sprintf("dihdah-%v.zip", name)
+		if len(args) == 2 {
+			fileName = args[1]
+		}
+
+		file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fmt.Errorf("Error creating the export file: %v", err)
+		}
+		defer file.Close()
+
+		if err := commons.ExportUser(name, file); err != nil {
+			file.Close()
+			os.Remove(fileName)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %v to %v.\n", name, fileName)
+		return nil
+	},
+}
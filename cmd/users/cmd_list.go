@@ -0,0 +1,26 @@
+package users
+
+import (
+	"fmt"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Shows the users.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		users, err := commons.Users()
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			fmt.Fprintln(cmd.OutOrStdout(), user)
+		}
+
+		return nil
+	},
+}
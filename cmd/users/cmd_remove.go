@@ -0,0 +1,41 @@
+package users
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RemoveCmd.Flags().BoolP("yes", "y", false, "Remove without asking first.")
+}
+
+var RemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Removes a user, with all of their settings, history and progress.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Remove %v with all of their files? Export them first with 'dihdah users export %v'. [y/N] ",
+				name,
+				name,
+			)
+
+			answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+				fmt.Fprintln(cmd.OutOrStdout(), "Nothing was removed.")
+				return nil
+			}
+		}
+
+		return commons.RemoveUser(name)
+	},
+}
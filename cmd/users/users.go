@@ -0,0 +1,40 @@
+package users
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Cmd.AddCommand(ListCmd)
+	Cmd.AddCommand(AddCmd)
+	Cmd.AddCommand(RemoveCmd)
+	Cmd.AddCommand(ExportCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "users",
+	Short: "Learner profiles, each with their own settings, history and progress.",
+	// Overrides the one of the root command, as these commands take the
+	// users as arguments instead of --user, and have no config to apply.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Long: `The 'users' command manages the learners using dihdah on this computer, so that their
+settings, history and progress do not mix together.
+
+These are the things the user can do in here:
+  - 'dihdah users list': Shows the users.
+  - 'dihdah users add <name>': Adds a user.
+  - 'dihdah users remove <name>': Removes a user, with all of their files.
+  - 'dihdah users export <name> [file]': Saves the files of a user into a zip file.
+
+Then run any command with --user to train as that user:
+
+  $ dihdah users add alice
+  $ dihdah encode --level 2 --user alice
+  $ dihdah ui --user alice
+
+Without --user, the default user is used, which has the files from before
+there were users. The TUI can also switch users from its main menu.
+
+Each user has their own config file (see 'dihdah config --help'), training
+history and text positions, in $XDG_CONFIG_HOME/dihdah/users/<name> on linux.`,
+}
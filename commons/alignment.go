@@ -0,0 +1,161 @@
+package commons
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type AlignOp int
+
+const (
+	AlignMatch AlignOp = iota
+	AlignSubstitution
+
+	// The user has an extra item that is not in the real answer.
+	AlignInsertion
+
+	// The user has missed an item of the real answer.
+	AlignDeletion
+)
+
+// Marker used for displaying the alignment operation under the answers.
+func (op AlignOp) Marker() rune {
+	return [...]rune{' ', '?', '+', '-'}[op]
+}
+
+// One step of an alignment. RealIdx is -1 for insertions, UserIdx is -1 for
+// deletions.
+type AlignStep struct {
+	Op      AlignOp
+	RealIdx int
+	UserIdx int
+}
+
+// Aligns the user answer against the real answer with the least amount of
+// substitutions, insertions and deletions (Levenshtein distance).
+func Align[T comparable](real []T, user []T) []AlignStep {
+	costs := make([][]int, len(real)+1)
+	for i := range costs {
+		costs[i] = make([]int, len(user)+1)
+		costs[i][0] = i
+	}
+
+	for j := range user {
+		costs[0][j+1] = j + 1
+	}
+
+	for i := range real {
+		for j := range user {
+			substitutionCost := 1
+			if real[i] == user[j] {
+				substitutionCost = 0
+			}
+
+			costs[i+1][j+1] = min(
+				costs[i][j]+substitutionCost,
+				costs[i][j+1]+1,
+				costs[i+1][j]+1,
+			)
+		}
+	}
+
+	steps := []AlignStep(nil)
+	i, j := len(real), len(user)
+
+	for i > 0 || j > 0 {
+		if i > 0 && j > 0 {
+			substitutionCost := 1
+			if real[i-1] == user[j-1] {
+				substitutionCost = 0
+			}
+
+			if costs[i][j] == costs[i-1][j-1]+substitutionCost {
+				op := AlignMatch
+				if substitutionCost != 0 {
+					op = AlignSubstitution
+				}
+
+				steps = append(steps, AlignStep{Op: op, RealIdx: i - 1, UserIdx: j - 1})
+				i, j = i-1, j-1
+				continue
+			}
+		}
+
+		if i > 0 && costs[i][j] == costs[i-1][j]+1 {
+			steps = append(steps, AlignStep{Op: AlignDeletion, RealIdx: i - 1, UserIdx: -1})
+			i -= 1
+			continue
+		}
+
+		steps = append(steps, AlignStep{Op: AlignInsertion, RealIdx: -1, UserIdx: j - 1})
+		j -= 1
+	}
+
+	for left, right := 0, len(steps)-1; left < right; left, right = left+1, right-1 {
+		steps[left], steps[right] = steps[right], steps[left]
+	}
+
+	return steps
+}
+
+// Counts each of the alignment operations.
+func CountAlignOps(steps []AlignStep) map[AlignOp]int {
+	counts := map[AlignOp]int{}
+	for _, step := range steps {
+		counts[step.Op] += 1
+	}
+
+	return counts
+}
+
+// Renders the user answer and the markers of the alignment, to be displayed
+// under the real answer (also rendered with gaps for the insertions).
+func RenderAlignment(real []rune, user []rune, steps []AlignStep, gap rune) (realRow, markerRow, userRow string) {
+	realRunes := make([]rune, 0, len(steps))
+	markerRunes := make([]rune, 0, len(steps))
+	userRunes := make([]rune, 0, len(steps))
+
+	for _, step := range steps {
+		realRune, userRune := gap, gap
+
+		if step.RealIdx >= 0 {
+			realRune = real[step.RealIdx]
+		}
+
+		if step.UserIdx >= 0 {
+			userRune = user[step.UserIdx]
+		}
+
+		realRunes = append(realRunes, realRune)
+		markerRunes = append(markerRunes, step.Op.Marker())
+		userRunes = append(userRunes, userRune)
+	}
+
+	return string(realRunes), string(markerRunes), string(userRunes)
+}
+
+// Styles a row of RenderAlignment (or a part of it, with the steps of that
+// part), with the matches in the correct style and the rest in the wrong style.
+func StyleAlignmentRow(row []rune, steps []AlignStep, matchStyle lipgloss.Style, wrongStyle lipgloss.Style) string {
+	builder := strings.Builder{}
+
+	for start := 0; start < len(row); {
+		isMatch := steps[start].Op == AlignMatch
+
+		end := start + 1
+		for end < len(row) && (steps[end].Op == AlignMatch) == isMatch {
+			end += 1
+		}
+
+		style := wrongStyle
+		if isMatch {
+			style = matchStyle
+		}
+
+		builder.WriteString(style.Render(string(row[start:end])))
+		start = end
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,107 @@
+package commons
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// The markers of the operations, one for each step.
+func alignMarkers(steps []AlignStep) string {
+	markers := []rune{}
+	for _, step := range steps {
+		markers = append(markers, step.Op.Marker())
+	}
+
+	return string(markers)
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name string
+		real string
+		user string
+		want string
+	}{
+		{"same", "abc", "abc", "   "},
+		{"substitution", "abc", "axc", " ? "},
+		{"insertion", "abc", "abxc", "  + "},
+		{"deletion", "abc", "ac", " - "},
+		{"first letter missed", "abcd", "bcd", "-   "},
+		{"empty answer", "abc", "", "---"},
+		{"nothing to answer", "", "ab", "++"},
+		{"both empty", "", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			steps := Align([]rune(test.real), []rune(test.user))
+			if got := alignMarkers(steps); got != test.want {
+				t.Errorf("Align(%q, %q) = %q, want %q", test.real, test.user, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAlignIndexes(t *testing.T) {
+	got := Align([]rune("abc"), []rune("ac"))
+	want := []AlignStep{
+		{Op: AlignMatch, RealIdx: 0, UserIdx: 0},
+		{Op: AlignDeletion, RealIdx: 1, UserIdx: -1},
+		{Op: AlignMatch, RealIdx: 2, UserIdx: 1},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Align() = %v, want %v", got, want)
+	}
+}
+
+func TestAlignWords(t *testing.T) {
+	real := []string{"the", "quick", "fox"}
+	user := []string{"the", "quack", "brown", "fox"}
+
+	steps := Align(real, user)
+	if got, want := alignMarkers(steps), " +? "; got != want {
+		t.Errorf("Align() = %q, want %q", got, want)
+	}
+
+	got := CountAlignOps(steps)
+	want := map[AlignOp]int{AlignMatch: 2, AlignSubstitution: 1, AlignInsertion: 1}
+
+	if !maps.Equal(got, want) {
+		t.Errorf("CountAlignOps() = %v, want %v", got, want)
+	}
+}
+
+func TestCountAlignOps(t *testing.T) {
+	tests := []struct {
+		name string
+		real string
+		user string
+		want map[AlignOp]int
+	}{
+		{"same", "abc", "abc", map[AlignOp]int{AlignMatch: 3}},
+		{"one of each", "abcd", "axcdy", map[AlignOp]int{AlignMatch: 3, AlignSubstitution: 1, AlignInsertion: 1}},
+		{"deletions", "abcd", "ad", map[AlignOp]int{AlignMatch: 2, AlignDeletion: 2}},
+		{"empty answer", "abc", "", map[AlignOp]int{AlignDeletion: 3}},
+		{"both empty", "", "", map[AlignOp]int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := CountAlignOps(Align([]rune(test.real), []rune(test.user)))
+			if !maps.Equal(got, test.want) {
+				t.Errorf("CountAlignOps() of %q and %q = %v, want %v", test.real, test.user, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderAlignment(t *testing.T) {
+	real, user := []rune("abcd"), []rune("acdx")
+
+	realRow, markerRow, userRow := RenderAlignment(real, user, Align(real, user), '_')
+	if realRow != "abcd_" || markerRow != " -  +" || userRow != "a_cdx" {
+		t.Errorf("RenderAlignment() = %q, %q, %q, want %q, %q, %q", realRow, markerRow, userRow, "abcd_", " -  +", "a_cdx")
+	}
+}
@@ -0,0 +1,128 @@
+package commons
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const configFileName = "config.json"
+
+// The config file of dihdah, in the storage directory.
+//
+// The flag values are keyed by the flag name for every command with that flag
+// (like "speed"), or by the command path and the flag name for only one command
+// (like "decode.letter.speed"), which comes first.
+type Config struct {
+	// Used when a flag is not given.
+	Defaults map[string]string `json:"defaults,omitempty"`
+
+	// Selected with --profile, over the defaults. These are added to the
+	// built-in profiles, or replace them.
+	Profiles map[string]map[string]string `json:"profiles,omitempty"`
+
+	// Values last used in the option screens of 'dihdah ui', by input name.
+	UI map[string]any `json:"ui,omitempty"`
+
+	// Keys of the key bindings, by the names of KeyMap.Bindings().
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Colors of the custom theme, by the names of ThemeColorNames().
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+var BuiltinProfiles = map[string]map[string]string{
+	"beginner": {
+		"level":      "1",
+		"speed":      "0.75",
+		"visualizer": "live",
+		"wpm":        "12",
+	},
+	"contest": {
+		"speed":             "1.5",
+		"visualizer":        "none",
+		"wpm":               "28",
+		"encode.time-limit": "3s",
+	},
+	// Quiet practice, with the morse code flashed instead of played.
+	"evening": {
+		"output":      "visual",
+		"visualizer":  "live",
+		"flash-color": "#aa6600",
+	},
+}
+
+func LoadConfig() (Config, error) {
+	config := Config{}
+	if err := LoadStoredJSON(configFileName, &config); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+func SaveConfig(config Config) error {
+	return SaveStoredJSON(configFileName, config)
+}
+
+func ConfigFilePath() (string, error) {
+	dir, err := StorageDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, configFileName), nil
+}
+
+// The values of a profile. A profile in the config file replaces the built-in
+// one with the same name.
+func (config Config) Profile(name string) (map[string]string, bool) {
+	if values, exists := config.Profiles[name]; exists {
+		return values, true
+	}
+
+	values, exists := BuiltinProfiles[name]
+	return values, exists
+}
+
+func (config Config) ProfileNames() []string {
+	names := slices.Collect(maps.Keys(BuiltinProfiles))
+	for name := range config.Profiles {
+		if _, isBuiltin := BuiltinProfiles[name]; !isBuiltin {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Value of a flag of the command (like "decode.letter"), from the profile if
+// any and then from the defaults. The keys of the command come before the keys
+// of its parent commands, like "decode.output" for 'decode letter'.
+func (config Config) FlagValue(profile string, commandKey string, flagName string) (string, bool) {
+	profileValues, _ := config.Profile(profile)
+
+	for _, values := range []map[string]string{profileValues, config.Defaults} {
+		key := commandKey
+		for {
+			if value, ok := values[ConfigKey(key, flagName)]; ok {
+				return value, true
+			}
+
+			if len(key) == 0 {
+				break
+			}
+
+			key = key[:max(strings.LastIndex(key, "."), 0)]
+		}
+	}
+
+	return "", false
+}
+
+// Like "decode.letter.speed", or "speed" for the root command.
+func ConfigKey(commandKey string, flagName string) string {
+	return strings.Trim(commandKey+"."+flagName, ".")
+}
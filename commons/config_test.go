@@ -0,0 +1,59 @@
+package commons
+
+import "testing"
+
+func TestFlagValue(t *testing.T) {
+	config := Config{
+		Defaults: map[string]string{
+			"speed":               "1",
+			"decode.speed":        "2",
+			"decode.letter.level": "3",
+			"output":              "audio",
+		},
+		Profiles: map[string]map[string]string{
+			"fast": {"speed": "4"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		profile    string
+		commandKey string
+		flagName   string
+		want       string
+		wantFound  bool
+	}{
+		{"flag name", "", "encode", "speed", "1", true},
+		{"parent command over flag name", "", "decode.letter", "speed", "2", true},
+		{"command key", "", "decode.letter", "level", "3", true},
+		{"other command", "", "decode.word", "level", "", false},
+		{"profile over defaults", "fast", "decode.letter", "speed", "4", true},
+		{"defaults without the key in the profile", "fast", "decode.letter", "output", "audio", true},
+		{"unknown profile", "slow", "decode.letter", "speed", "2", true},
+		{"root command", "", "", "speed", "1", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, found := config.FlagValue(test.profile, test.commandKey, test.flagName)
+			if got != test.want || found != test.wantFound {
+				t.Errorf("FlagValue() = %q, %v, want %q, %v", got, found, test.want, test.wantFound)
+			}
+		})
+	}
+}
+
+func TestFlagValueBuiltinProfile(t *testing.T) {
+	config := Config{Profiles: map[string]map[string]string{
+		"beginner": {"speed": "0.5"},
+	}}
+
+	// The profile in the config file replaces the built-in one.
+	if got, _ := config.FlagValue("beginner", "decode.letter", "speed"); got != "0.5" {
+		t.Errorf("FlagValue() = %q, want %q", got, "0.5")
+	}
+
+	if _, found := config.FlagValue("beginner", "decode.letter", "level"); found {
+		t.Errorf("FlagValue() found a key of the replaced built-in profile")
+	}
+}
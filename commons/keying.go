@@ -0,0 +1,367 @@
+package commons
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type KeyerMode int
+
+const (
+	// One key, with the user timing every dit and dah.
+	StraightKey KeyerMode = iota
+
+	// Two paddles, alternating dits and dahs while both are held.
+	IambicA
+
+	// Like IambicA, but an element of the other paddle pressed during an
+	// element is sent after it, even if the paddles are released by then.
+	IambicB
+)
+
+var keyerModeNames = []string{"straight", "iambic-a", "iambic-b"}
+
+func (mode KeyerMode) String() string {
+	return keyerModeNames[mode]
+}
+
+func ParseKeyerMode(str string) (KeyerMode, error) {
+	for i, name := range keyerModeNames {
+		if strings.EqualFold(str, name) {
+			return KeyerMode(i), nil
+		}
+	}
+
+	return StraightKey, fmt.Errorf("Invalid keyer %q (expected one of: %v)", str, strings.Join(keyerModeNames, ", "))
+}
+
+type Paddle int
+
+const (
+	// Also the key of the straight key.
+	DitPaddle Paddle = iota
+	DahPaddle
+)
+
+// A key being pressed or released, at the time since the start of sending.
+type KeyEvent struct {
+	Paddle Paddle
+	Down   bool
+	At     time.Duration
+}
+
+// Duration of a dit at the words per minute, with "PARIS" as the standard word.
+func WpmDitDuration(wpm float64) time.Duration {
+	return time.Duration(float64(time.Minute) / (50 * wpm))
+}
+
+// The speed ratio (like --speed) that plays the sounds at the words per minute.
+func WpmSpeed(wpm float64) float64 {
+	return float64(DefaultDitDuration) / float64(WpmDitDuration(wpm))
+}
+
+// Returns the signal of a straight key, from the first key press to the last
+// key release. Repeated presses (from holding the key) are ignored.
+func StraightKeySignal(events []KeyEvent) []SignalElement {
+	signal := []SignalElement(nil)
+	down, lastChange := false, time.Duration(0)
+
+	for i, event := range events {
+		if event.Down == down {
+			continue
+		}
+
+		if i != 0 {
+			signal = append(signal, SignalElement{On: down, Duration: event.At - lastChange})
+		}
+
+		down, lastChange = event.Down, event.At
+	}
+
+	return trimSignal(signal)
+}
+
+// Returns the signal sent by an iambic keyer with the paddle events, with the
+// elements starting until the given time. The last element may go past it.
+func IambicKeySignal(events []KeyEvent, mode KeyerMode, ditDuration time.Duration, until time.Duration) []SignalElement {
+	// Whether the paddle is down at the time, and since when.
+	paddleState := func(paddle Paddle, at time.Duration) (down bool, since time.Duration) {
+		for _, event := range events {
+			if event.At > at {
+				break
+			}
+
+			if event.Paddle == paddle && event.Down != down {
+				down, since = event.Down, event.At
+			}
+		}
+
+		return down, since
+	}
+
+	paddleDown := func(paddle Paddle, at time.Duration) bool {
+		down, _ := paddleState(paddle, at)
+		return down
+	}
+
+	pressedBetween := func(paddle Paddle, from time.Duration, to time.Duration) bool {
+		if paddleDown(paddle, from) {
+			return true
+		}
+
+		for _, event := range events {
+			if event.Paddle == paddle && event.Down && event.At > from && event.At < to {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// The time of the first press of a paddle after the time, if any.
+	nextPress := func(after time.Duration) (time.Duration, bool) {
+		for _, event := range events {
+			if event.Down && event.At >= after {
+				return event.At, true
+			}
+		}
+
+		return 0, false
+	}
+
+	signal := []SignalElement(nil)
+	appendElement := func(on bool, duration time.Duration) {
+		if len(signal) != 0 && signal[len(signal)-1].On == on {
+			signal[len(signal)-1].Duration += duration
+			return
+		}
+
+		signal = append(signal, SignalElement{On: on, Duration: duration})
+	}
+
+	at, keying := time.Duration(0), false
+	element := DitPaddle
+
+	for {
+		if !keying {
+			pressedAt, ok := nextPress(at)
+			if !ok || pressedAt >= until {
+				break
+			}
+
+			if len(signal) != 0 {
+				appendElement(false, pressedAt-at)
+			}
+
+			at, keying = pressedAt, true
+
+			// When squeezing, the paddle pressed first goes first
+			ditDown, ditSince := paddleState(DitPaddle, at)
+			dahDown, dahSince := paddleState(DahPaddle, at)
+
+			element = DitPaddle
+			if !ditDown || (dahDown && dahSince < ditSince) {
+				element = DahPaddle
+			}
+		}
+
+		if at >= until {
+			break
+		}
+
+		elementDuration := ditDuration
+		if element == DahPaddle {
+			elementDuration = ditDuration * 3
+		}
+
+		appendElement(true, elementDuration)
+		appendElement(false, ditDuration)
+
+		elementEnd := at + elementDuration + ditDuration
+		other := DahPaddle - element
+		otherPressed := pressedBetween(other, at, elementEnd)
+
+		switch {
+		case paddleDown(other, elementEnd):
+			element = other
+		case paddleDown(element, elementEnd):
+		case mode == IambicB && otherPressed:
+			element = other
+		default:
+			keying = false
+		}
+
+		at = elementEnd
+	}
+
+	return trimSignal(signal)
+}
+
+// Removes the silences at the ends of the signal.
+func trimSignal(signal []SignalElement) []SignalElement {
+	for len(signal) != 0 && !signal[0].On {
+		signal = signal[1:]
+	}
+
+	for len(signal) != 0 && !signal[len(signal)-1].On {
+		signal = signal[:len(signal)-1]
+	}
+
+	return signal
+}
+
+// Timing statistics of a sent signal, and what it decodes to.
+type KeyingAnalysis struct {
+	Text string
+
+	// In the notation of ToMorseCode.
+	MorseCode string
+
+	Dits, Dahs                      []time.Duration
+	ElementGaps, CharGaps, WordGaps []time.Duration
+}
+
+// Decodes the signal against the timing of the words per minute: beeps shorter
+// than two dits are dits, and silences are gaps between elements up to two
+// dits, between characters up to five dits, and between words after that.
+func AnalyzeKeying(signal []SignalElement, wpm float64) KeyingAnalysis {
+	analysis := KeyingAnalysis{}
+	ditDuration := WpmDitDuration(wpm)
+
+	text, morseCode := strings.Builder{}, strings.Builder{}
+	charCode := ""
+
+	endChar := func() {
+		if len(charCode) == 0 {
+			return
+		}
+
+		if morseCode.Len() != 0 {
+			morseCode.WriteRune(' ')
+		}
+
+		morseCode.WriteString(charCode)
+		text.WriteRune(MorseCodeChar(charCode))
+		charCode = ""
+	}
+
+	for _, element := range trimSignal(signal) {
+		switch {
+		case element.On && element.Duration < ditDuration*2:
+			charCode += "."
+			analysis.Dits = append(analysis.Dits, element.Duration)
+		case element.On:
+			charCode += ","
+			analysis.Dahs = append(analysis.Dahs, element.Duration)
+		case element.Duration < ditDuration*2:
+			analysis.ElementGaps = append(analysis.ElementGaps, element.Duration)
+		case element.Duration < ditDuration*5:
+			analysis.CharGaps = append(analysis.CharGaps, element.Duration)
+			endChar()
+		default:
+			analysis.WordGaps = append(analysis.WordGaps, element.Duration)
+			endChar()
+
+			text.WriteRune(' ')
+			morseCode.WriteRune(MorseSpaceIndicator)
+		}
+	}
+
+	endChar()
+
+	analysis.Text = text.String()
+	analysis.MorseCode = morseCode.String()
+
+	return analysis
+}
+
+// Returns the character of the morse code, or '?' if there is none.
+func MorseCodeChar(morseCode string) rune {
+	for char, code := range MorseCodeLookup {
+		if code == morseCode {
+			return char
+		}
+	}
+
+	return '?'
+}
+
+// How far a timing of the sending is from the ideal, in dits at the words per
+// minute (except for the ratio).
+type KeyingGrade struct {
+	Name     string
+	Measured float64
+	Ideal    float64
+	Count    int
+}
+
+// Timings within this fraction of the ideal are good.
+const KeyingTolerance = 0.25
+
+func (grade KeyingGrade) Verdict() string {
+	switch {
+	case grade.Measured < grade.Ideal*(1-KeyingTolerance):
+		return "too short"
+	case grade.Measured > grade.Ideal*(1+KeyingTolerance):
+		return "too long"
+	}
+
+	return "good"
+}
+
+// Grades the timings that were sent at least once.
+func (analysis KeyingAnalysis) Grades(wpm float64) []KeyingGrade {
+	ditDuration := WpmDitDuration(wpm)
+	inDits := func(durations []time.Duration) float64 {
+		return float64(averageDuration(durations)) / float64(ditDuration)
+	}
+
+	grades := []KeyingGrade{
+		{"Dit", inDits(analysis.Dits), 1, len(analysis.Dits)},
+		{"Dah", inDits(analysis.Dahs), 3, len(analysis.Dahs)},
+		{"Dah/dit ratio", analysis.DahDitRatio(), 3, min(len(analysis.Dits), len(analysis.Dahs))},
+		{"Element gap", inDits(analysis.ElementGaps), 1, len(analysis.ElementGaps)},
+		{"Letter gap", inDits(analysis.CharGaps), 3, len(analysis.CharGaps)},
+		{"Word gap", inDits(analysis.WordGaps), 7, len(analysis.WordGaps)},
+	}
+
+	return slices.DeleteFunc(grades, func(grade KeyingGrade) bool { return grade.Count == 0 })
+}
+
+func (analysis KeyingAnalysis) DahDitRatio() float64 {
+	if len(analysis.Dits) == 0 || len(analysis.Dahs) == 0 {
+		return 0
+	}
+
+	return float64(averageDuration(analysis.Dahs)) / float64(averageDuration(analysis.Dits))
+}
+
+// The words per minute the dits (and the dahs, at a third) were sent at.
+func (analysis KeyingAnalysis) Wpm() float64 {
+	units := []time.Duration{}
+	units = append(units, analysis.Dits...)
+	for _, dah := range analysis.Dahs {
+		units = append(units, dah/3)
+	}
+
+	if len(units) == 0 {
+		return 0
+	}
+
+	return float64(time.Minute) / (50 * float64(averageDuration(units)))
+}
+
+func averageDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	total := time.Duration(0)
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return total / time.Duration(len(durations))
+}
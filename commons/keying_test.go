@@ -0,0 +1,265 @@
+package commons
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+const testWpm = 20
+
+// 60ms at 20 wpm.
+var testDit = WpmDitDuration(testWpm)
+
+// Presses and releases the key for each of the durations, the silences being
+// the durations of the odd indexes.
+func straightKeyEvents(durations ...time.Duration) []KeyEvent {
+	events := []KeyEvent{}
+	at := time.Duration(0)
+
+	for i, duration := range durations {
+		if i%2 == 0 {
+			events = append(events, KeyEvent{Paddle: DitPaddle, Down: true, At: at})
+			events = append(events, KeyEvent{Paddle: DitPaddle, Down: false, At: at + duration})
+		}
+
+		at += duration
+	}
+
+	return events
+}
+
+func TestStraightKeySignal(t *testing.T) {
+	events := straightKeyEvents(testDit, testDit, testDit*3)
+
+	got := StraightKeySignal(events)
+	want := []SignalElement{
+		{On: true, Duration: testDit},
+		{On: false, Duration: testDit},
+		{On: true, Duration: testDit * 3},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("StraightKeySignal() = %v, want %v", got, want)
+	}
+}
+
+func TestStraightKeySignalIgnoresRepeatedPresses(t *testing.T) {
+	// Holding the key down repeats the key presses, like holding a key of the
+	// keyboard does.
+	events := []KeyEvent{
+		{Down: false, At: 0},
+		{Down: true, At: testDit},
+		{Down: true, At: testDit + testDit/2},
+		{Down: true, At: testDit * 2},
+		{Down: true, At: testDit * 3},
+		{Down: false, At: testDit * 4},
+		{Down: false, At: testDit * 5},
+	}
+
+	got := StraightKeySignal(events)
+	want := []SignalElement{{On: true, Duration: testDit * 3}}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("StraightKeySignal() = %v, want %v", got, want)
+	}
+}
+
+func TestIambicKeySignalSingleElements(t *testing.T) {
+	tests := []struct {
+		name   string
+		paddle Paddle
+		want   string
+	}{
+		{"dit paddle", DitPaddle, "."},
+		{"dah paddle", DahPaddle, ","},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := []KeyEvent{
+				{Paddle: test.paddle, Down: true, At: 0},
+				{Paddle: test.paddle, Down: false, At: testDit / 2},
+			}
+
+			signal := IambicKeySignal(events, IambicA, testDit, time.Second)
+			if got := AnalyzeKeying(signal, testWpm).MorseCode; got != test.want {
+				t.Errorf("morse code = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIambicKeySignalHeldPaddle(t *testing.T) {
+	// Held for a bit more than two dits and their gaps, the third dit starts
+	// before the release.
+	events := []KeyEvent{
+		{Paddle: DitPaddle, Down: true, At: 0},
+		{Paddle: DitPaddle, Down: false, At: testDit*4 + testDit/2},
+	}
+
+	signal := IambicKeySignal(events, IambicA, testDit, time.Second)
+	if got := AnalyzeKeying(signal, testWpm).MorseCode; got != "..." {
+		t.Errorf("morse code = %q, want %q", got, "...")
+	}
+}
+
+func TestIambicKeySignalSqueeze(t *testing.T) {
+	// Both paddles squeezed during the first dit, the dit paddle first, and
+	// released before the dit ends.
+	events := []KeyEvent{
+		{Paddle: DitPaddle, Down: true, At: 0},
+		{Paddle: DahPaddle, Down: true, At: testDit / 4},
+		{Paddle: DitPaddle, Down: false, At: testDit / 2},
+		{Paddle: DahPaddle, Down: false, At: testDit / 2},
+	}
+
+	tests := []struct {
+		mode KeyerMode
+		want string
+	}{
+		// Mode A stops with the paddles released, mode B sends the dah that
+		// was squeezed in during the dit.
+		{IambicA, "."},
+		{IambicB, ".,"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode.String(), func(t *testing.T) {
+			signal := IambicKeySignal(events, test.mode, testDit, time.Second)
+			if got := AnalyzeKeying(signal, testWpm).MorseCode; got != test.want {
+				t.Errorf("morse code = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIambicKeySignalAlternates(t *testing.T) {
+	// Squeezed with the dah paddle first, and held through the second
+	// element: dah dit, then nothing more as both are released by then.
+	events := []KeyEvent{
+		{Paddle: DahPaddle, Down: true, At: 0},
+		{Paddle: DitPaddle, Down: true, At: testDit / 4},
+		{Paddle: DahPaddle, Down: false, At: testDit * 5},
+		{Paddle: DitPaddle, Down: false, At: testDit * 5},
+	}
+
+	signal := IambicKeySignal(events, IambicA, testDit, time.Second)
+	if got := AnalyzeKeying(signal, testWpm).MorseCode; got != ",." {
+		t.Errorf("morse code = %q, want %q", got, ",.")
+	}
+}
+
+func TestAnalyzeKeying(t *testing.T) {
+	// "an e" sent with perfect timing: .- -. / .
+	events := straightKeyEvents(
+		testDit, testDit, testDit*3,
+		testDit*3,
+		testDit*3, testDit, testDit,
+		testDit*7,
+		testDit,
+	)
+
+	analysis := AnalyzeKeying(StraightKeySignal(events), testWpm)
+
+	if analysis.Text != "an e" {
+		t.Errorf("Text = %q, want %q", analysis.Text, "an e")
+	}
+
+	counts := []int{
+		len(analysis.Dits), len(analysis.Dahs),
+		len(analysis.ElementGaps), len(analysis.CharGaps), len(analysis.WordGaps),
+	}
+
+	if want := []int{3, 2, 2, 1, 1}; !slices.Equal(counts, want) {
+		t.Errorf("dits, dahs, element, letter and word gaps = %v, want %v", counts, want)
+	}
+
+	if wpm := analysis.Wpm(); wpm < testWpm-0.01 || wpm > testWpm+0.01 {
+		t.Errorf("Wpm() = %v, want %v", wpm, testWpm)
+	}
+
+	if ratio := analysis.DahDitRatio(); ratio != 3 {
+		t.Errorf("DahDitRatio() = %v, want 3", ratio)
+	}
+
+	for _, grade := range analysis.Grades(testWpm) {
+		if grade.Verdict() != "good" {
+			t.Errorf("%v: %v (%v dits), want good", grade.Name, grade.Verdict(), grade.Measured)
+		}
+	}
+}
+
+func TestAnalyzeKeyingThresholds(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     string
+	}{
+		{"dit just under two dits", testDit*2 - time.Millisecond, "e"},
+		{"dah at two dits", testDit * 2, "t"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signal := []SignalElement{{On: true, Duration: test.duration}}
+			if got := AnalyzeKeying(signal, testWpm).Text; got != test.want {
+				t.Errorf("Text = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	gaps := []struct {
+		name string
+		gap  time.Duration
+		want string
+	}{
+		{"element gap", testDit*2 - time.Millisecond, "i"},
+		{"letter gap", testDit * 2, "ee"},
+		{"letter gap up to five dits", testDit*5 - time.Millisecond, "ee"},
+		{"word gap", testDit * 5, "e e"},
+	}
+
+	for _, test := range gaps {
+		t.Run(test.name, func(t *testing.T) {
+			signal := StraightKeySignal(straightKeyEvents(testDit, test.gap, testDit))
+			if got := AnalyzeKeying(signal, testWpm).Text; got != test.want {
+				t.Errorf("Text = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKeyingGradeVerdict(t *testing.T) {
+	tests := []struct {
+		measured float64
+		want     string
+	}{
+		{3 * (1 - KeyingTolerance), "good"},
+		{3 * (1 - KeyingTolerance - 0.01), "too short"},
+		{3, "good"},
+		{3 * (1 + KeyingTolerance), "good"},
+		{3 * (1 + KeyingTolerance + 0.01), "too long"},
+	}
+
+	for _, test := range tests {
+		grade := KeyingGrade{Name: "Dah", Measured: test.measured, Ideal: 3, Count: 1}
+		if got := grade.Verdict(); got != test.want {
+			t.Errorf("Verdict() of %v dits = %q, want %q", test.measured, got, test.want)
+		}
+	}
+}
+
+func TestKeyingGradesSkipsUnsent(t *testing.T) {
+	// Only dits, without gaps: no dahs, ratio or gaps to grade.
+	analysis := AnalyzeKeying([]SignalElement{{On: true, Duration: testDit}}, testWpm)
+
+	names := []string{}
+	for _, grade := range analysis.Grades(testWpm) {
+		names = append(names, grade.Name)
+	}
+
+	if want := []string{"Dit"}; !slices.Equal(names, want) {
+		t.Errorf("Grades() = %v, want %v", names, want)
+	}
+}
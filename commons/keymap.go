@@ -0,0 +1,214 @@
+package commons
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The key bindings of the drills, the chart and the TUI.
+type KeyMap struct {
+	Quit    key.Binding
+	Back    key.Binding
+	Confirm key.Binding
+	Chart   key.Binding
+	Sort    key.Binding
+
+	// For the letters and words, which have no spaces to type.
+	Replay key.Binding
+
+	// For the quotes and texts, where enter and space are typed.
+	Submit         key.Binding
+	TogglePlayback key.Binding
+	Rewind         key.Binding
+	Forward        key.Binding
+	ReplayWord     key.Binding
+
+	Up    key.Binding
+	Down  key.Binding
+	Left  key.Binding
+	Right key.Binding
+	Undo  key.Binding
+
+	CloseChart key.Binding
+	NextView   key.Binding
+	PrevView   key.Binding
+
+	MenuUp     key.Binding
+	MenuDown   key.Binding
+	MenuBack   key.Binding
+	MenuSelect key.Binding
+	Toggle     key.Binding
+	Increase   key.Binding
+	Decrease   key.Binding
+	Reset      key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:    key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "to exit")),
+		Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("escape", "to go back")),
+		Confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "to confirm")),
+		Chart:   key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "for the chart")),
+		Sort:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "to toggle sort")),
+
+		Replay: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "to repeat sound")),
+
+		// ctrl+s freezes the terminals with XON/XOFF flow control, and is kept
+		// only after ctrl+d.
+		Submit:         key.NewBinding(key.WithKeys("ctrl+d", "ctrl+s"), key.WithHelp("ctrl+d", "to submit")),
+		TogglePlayback: key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "to pause/play")),
+		Rewind:         key.NewBinding(key.WithKeys("ctrl+left", "pgup"), key.WithHelp("ctrl+left", "to go back a word")),
+		Forward:        key.NewBinding(key.WithKeys("ctrl+right", "pgdown"), key.WithHelp("ctrl+right", "to skip a word")),
+		ReplayWord:     key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "to replay the word")),
+
+		Up:    key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("up", "to go up")),
+		Down:  key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("down", "to go down")),
+		Left:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("left", "to go left")),
+		Right: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("right", "to go right")),
+		Undo:  key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "to undo")),
+
+		CloseChart: key.NewBinding(key.WithKeys("esc", "q", "ctrl+o"), key.WithHelp("escape", "to go back")),
+		NextView:   key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "for the next view")),
+		PrevView:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "for the previous view")),
+
+		MenuUp:     key.NewBinding(key.WithKeys("up", "ctrl+p", "tab", "k"), key.WithHelp("up", "to go up")),
+		MenuDown:   key.NewBinding(key.WithKeys("down", "ctrl+n", "shift+tab", "j"), key.WithHelp("down", "to go down")),
+		MenuBack:   key.NewBinding(key.WithKeys("esc", "backspace", "h", "left", "H", "shift+left"), key.WithHelp("backspace", "to go back")),
+		MenuSelect: key.NewBinding(key.WithKeys("enter", " ", "l", "right", "L", "shift+right"), key.WithHelp("enter", "to select")),
+		Toggle:     key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("space", "to toggle")),
+		Increase:   key.NewBinding(key.WithKeys("+", "=", ".", ">", "right", "l"), key.WithHelp("right", "to increase")),
+		Decrease:   key.NewBinding(key.WithKeys("-", "_", ",", "<", "left", "h"), key.WithHelp("left", "to decrease")),
+		Reset:      key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "to reset")),
+	}
+}
+
+// The key bindings in use, with the overrides of the config file.
+var Keys = DefaultKeyMap()
+
+// The bindings by their names in the config file, like "toggle-playback".
+func (keyMap *KeyMap) Bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":    &keyMap.Quit,
+		"back":    &keyMap.Back,
+		"confirm": &keyMap.Confirm,
+		"chart":   &keyMap.Chart,
+		"sort":    &keyMap.Sort,
+
+		"replay": &keyMap.Replay,
+
+		"submit":          &keyMap.Submit,
+		"toggle-playback": &keyMap.TogglePlayback,
+		"rewind":          &keyMap.Rewind,
+		"forward":         &keyMap.Forward,
+		"replay-word":     &keyMap.ReplayWord,
+
+		"up":    &keyMap.Up,
+		"down":  &keyMap.Down,
+		"left":  &keyMap.Left,
+		"right": &keyMap.Right,
+		"undo":  &keyMap.Undo,
+
+		"close-chart": &keyMap.CloseChart,
+		"next-view":   &keyMap.NextView,
+		"prev-view":   &keyMap.PrevView,
+
+		"menu-up":     &keyMap.MenuUp,
+		"menu-down":   &keyMap.MenuDown,
+		"menu-back":   &keyMap.MenuBack,
+		"menu-select": &keyMap.MenuSelect,
+		"toggle":      &keyMap.Toggle,
+		"increase":    &keyMap.Increase,
+		"decrease":    &keyMap.Decrease,
+		"reset":       &keyMap.Reset,
+	}
+}
+
+// Sets the key bindings to the defaults, with the overrides of the config file
+// of the current user.
+func LoadKeys() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	Keys = DefaultKeyMap()
+	return Keys.Override(config.Keys)
+}
+
+func KeyBindingNames() []string {
+	return slices.Sorted(maps.Keys(Keys.Bindings()))
+}
+
+// Replaces the keys of the bindings, like {"submit": ["ctrl+d", "f2"]}. The
+// space bar is written as "space".
+func (keyMap *KeyMap) Override(overrides map[string][]string) error {
+	bindings := keyMap.Bindings()
+
+	for name, keys := range overrides {
+		binding, exists := bindings[name]
+		if !exists {
+			return fmt.Errorf("Error: There is no key binding named %q. The key bindings are: %v", name, strings.Join(KeyBindingNames(), ", "))
+		}
+
+		if len(keys) == 0 {
+			return fmt.Errorf("Error: The key binding %q has no keys.", name)
+		}
+
+		keys = slices.Clone(keys)
+		for i, k := range keys {
+			if k == "space" {
+				keys[i] = " "
+			}
+		}
+
+		binding.SetKeys(keys...)
+		binding.SetHelp(keyName(keys[0]), binding.Help().Desc)
+	}
+
+	return nil
+}
+
+func keyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "esc":
+		return "escape"
+	}
+
+	return k
+}
+
+var keyHelp = func() help.Model {
+	model := help.New()
+	model.ShortSeparator = ", "
+	model.Styles = CurrentTheme.helpStyles()
+	return model
+}()
+
+// The binding, described differently in the help of a drill.
+func WithDesc(binding key.Binding, desc string) key.Binding {
+	binding.SetHelp(binding.Help().Key, desc)
+	return binding
+}
+
+// Like "escape to go back, enter to confirm, ctrl+c to exit".
+func KeyHelp(bindings ...key.Binding) string {
+	return keyHelp.ShortHelpView(bindings)
+}
+
+// Typed into a text input, instead of being a key binding.
+func IsTyped(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace:
+		return true
+	}
+
+	return false
+}
@@ -1,5 +1,10 @@
 package commons
 
+import (
+	"strings"
+	"unicode"
+)
+
 var MorseCodeLookup = map[rune]string{
 	'a': ".,",
 	'b': ",...",
@@ -27,4 +32,81 @@ var MorseCodeLookup = map[rune]string{
 	'x': ",..,",
 	'y': ",.,,",
 	'z': ",,..",
+
+	'0': ",,,,,",
+	'1': ".,,,,",
+	'2': "..,,,",
+	'3': "...,,",
+	'4': "....,",
+	'5': ".....",
+	'6': ",....",
+	'7': ",,...",
+	'8': ",,,..",
+	'9': ",,,,.",
+}
+
+// Splits the text into its words at the spaces, in lowercase and with only the
+// characters that have a morse code. The punctuation inside a word, like the
+// apostrophe of "don't" or the hyphen of "well-known", is dropped without
+// splitting the word.
+func MorseWords(text string) []string {
+	words := []string(nil)
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		word := strings.Map(func(r rune) rune {
+			if _, ok := MorseCodeLookup[unicode.ToLower(r)]; ok {
+				return unicode.ToLower(r)
+			}
+
+			return -1
+		}, field)
+
+		if len(word) != 0 {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// Converts the text into the notation MorseCharSound(...) accepts, with the
+// words of MorseWords(...).
+func ToMorseCode(text string) string {
+	wordCodes := []string{}
+	for _, word := range MorseWords(text) {
+		codes := []string{}
+		for _, r := range word {
+			codes = append(codes, MorseCodeLookup[r])
+		}
+
+		wordCodes = append(wordCodes, strings.Join(codes, " "))
+	}
+
+	return strings.Join(wordCodes, string(MorseSpaceIndicator)+" ")
+}
+
+// Splits morse code written by the user into the codes of its characters, with
+// a MorseSpaceIndicator between words. Characters are separated by spaces, and
+// words by '_' or '/' (like " / ").
+func ParseMorseCode(morseCode string) []string {
+	morseCode = strings.NewReplacer(
+		string(MorseSpaceIndicator), " _ ",
+		"/", " _ ",
+	).Replace(morseCode)
+
+	codes := []string(nil)
+	for _, code := range strings.Fields(morseCode) {
+		if code == string(MorseSpaceIndicator) {
+			if len(codes) == 0 || codes[len(codes)-1] == code {
+				continue
+			}
+		}
+
+		codes = append(codes, code)
+	}
+
+	if len(codes) != 0 && codes[len(codes)-1] == string(MorseSpaceIndicator) {
+		codes = codes[:len(codes)-1]
+	}
+
+	return codes
 }
@@ -0,0 +1,36 @@
+package commons
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMorseWords(t *testing.T) {
+	got := MorseWords("Don't stop, well-known “Chapter 3”! -- ")
+	want := []string{"dont", "stop", "wellknown", "chapter", "3"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("MorseWords() = %q, want %q", got, want)
+	}
+}
+
+func TestToMorseCode(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hi", ".... .."},
+		{"hi  there", ".... .._ , .... . .,. ."},
+		// The apostrophe is dropped without a word gap.
+		{"it's", ".. , ..."},
+		{"no. 5", ",. ,,,_ ....."},
+		{"", ""},
+		{" -- ", ""},
+	}
+
+	for _, test := range tests {
+		if got := ToMorseCode(test.text); got != test.want {
+			t.Errorf("ToMorseCode(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package commons
+
+// A node of the dichotomic morse code tree: going to the dit or the dah child
+// appends a dit or a dah to the code.
+type MorseTreeNode struct {
+	Code string
+
+	// Zero if no character has this code.
+	Char rune
+
+	Dit *MorseTreeNode
+	Dah *MorseTreeNode
+}
+
+// Builds the dichotomic tree of MorseCodeLookup. The root has an empty code.
+func MorseTree() *MorseTreeNode {
+	root := &MorseTreeNode{}
+
+	for char, code := range MorseCodeLookup {
+		node := root
+		for i, element := range code {
+			child := &node.Dit
+			if element == ',' {
+				child = &node.Dah
+			}
+
+			if *child == nil {
+				*child = &MorseTreeNode{Code: code[:i+1]}
+			}
+
+			node = *child
+		}
+
+		node.Char = char
+	}
+
+	return root
+}
+
+// Returns the node of the code, or nil if the tree has no such node.
+func (node *MorseTreeNode) Find(code string) *MorseTreeNode {
+	for _, element := range code {
+		if node == nil {
+			return nil
+		}
+
+		if element == ',' {
+			node = node.Dah
+		} else {
+			node = node.Dit
+		}
+	}
+
+	return node
+}
+
+// Depth of the deepest node, the root being at zero.
+func (node *MorseTreeNode) Depth() int {
+	if node == nil {
+		return -1
+	}
+
+	return 1 + max(node.Dit.Depth(), node.Dah.Depth())
+}
+
+// Returns the nodes at the depth from left (all dits) to right (all dahs), with
+// nil for the codes that are not in the tree.
+func (node *MorseTreeNode) Level(depth int) []*MorseTreeNode {
+	nodes := []*MorseTreeNode{node}
+
+	for range depth {
+		children := make([]*MorseTreeNode, 0, len(nodes)*2)
+		for _, n := range nodes {
+			if n == nil {
+				children = append(children, nil, nil)
+				continue
+			}
+
+			children = append(children, n.Dit, n.Dah)
+		}
+
+		nodes = children
+	}
+
+	return nodes
+}
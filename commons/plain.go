@@ -0,0 +1,99 @@
+package commons
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A drill session of the --plain mode, for screen readers: the prompts and the
+// results are printed as lines of plain text (no colors, no redraws), and the
+// answers are read as lines from the input.
+type PlainSession struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func NewPlainSession(in io.Reader, out io.Writer) *PlainSession {
+	return &PlainSession{
+		in:  bufio.NewScanner(in),
+		out: out,
+	}
+}
+
+// Prints a line.
+func (s *PlainSession) Say(format string, args ...any) {
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+// Prints the prompt and reads an answer, without the surrounding spaces. Not ok
+// at the end of the input, which ends the session.
+func (s *PlainSession) Ask(prompt string) (answer string, ok bool) {
+	s.Say("%v", prompt)
+	return s.read()
+}
+
+// Like Ask, but plays the sound with replay after the prompt, and again on
+// every empty answer, until something is answered.
+func (s *PlainSession) AskOrReplay(prompt string, replay func()) (answer string, ok bool) {
+	s.Say("%v", prompt)
+	replay()
+
+	for {
+		answer, ok = s.read()
+		if !ok || len(answer) != 0 {
+			return answer, ok
+		}
+
+		replay()
+	}
+}
+
+func (s *PlainSession) read() (string, bool) {
+	if !s.in.Scan() {
+		return "", false
+	}
+
+	return strings.TrimSpace(s.in.Text()), true
+}
+
+func (s *PlainSession) Err() error {
+	return s.in.Err()
+}
+
+// Spells out the morse code for reading it aloud, like "dot dash dot dot" for
+// ".,..". The characters are separated with commas, and the words with "space".
+func SpellMorseCode(morseCode string) string {
+	spelled := []string{}
+
+	for _, code := range ParseMorseCode(morseCode) {
+		if code == string(MorseSpaceIndicator) {
+			spelled = append(spelled, "space")
+			continue
+		}
+
+		elements := []string{}
+		for _, r := range code {
+			switch r {
+			case '.':
+				elements = append(elements, "dot")
+			case ',', '-':
+				elements = append(elements, "dash")
+			}
+		}
+
+		spelled = append(spelled, strings.Join(elements, " "))
+	}
+
+	return strings.Join(spelled, ", ")
+}
+
+// Like "1 of 5", or "all 5" when they are all of them.
+func CountOf(count int, total int) string {
+	if count == total {
+		return fmt.Sprintf("all %v", total)
+	}
+
+	return fmt.Sprintf("%v of %v", count, total)
+}
@@ -0,0 +1,113 @@
+package commons
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// A random source for picking the drills, from the seed or, if it is zero,
+// from the time. Returns the seed used, for picking the same drills again.
+func NewRandom(seed int64) (*rand.Rand, int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return rand.New(rand.NewSource(seed)), seed
+}
+
+// Reads the words of a word file, keeping only the letters and the hyphens of
+// each word.
+func LoadWords(reader io.Reader) ([]string, error) {
+	words := []string(nil)
+	scanner := bufio.NewScanner(reader)
+
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		word := strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r
+			}
+
+			if r >= 'A' && r <= 'Z' {
+				return r
+			}
+
+			if r == '-' {
+				return r
+			}
+
+			return -1
+		}, scanner.Text())
+
+		words = append(words, word)
+	}
+
+	return words, scanner.Err()
+}
+
+// Reads the quotes of a quote file, one quote per line. Empty lines are skipped.
+func LoadQuotes(reader io.Reader) ([]string, error) {
+	quotes := []string(nil)
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		quote := strings.TrimSpace(scanner.Text())
+		if len(quote) == 0 {
+			continue
+		}
+
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, scanner.Err()
+}
+
+// Returns the quotes whose length is within minLength and maxLength. A zero
+// maxLength means there is no maximum.
+func FilterQuotesByLength(quotes []string, minLength int, maxLength int) []string {
+	filtered := []string(nil)
+
+	for _, quote := range quotes {
+		quoteLength := len([]rune(quote))
+		if quoteLength < minLength {
+			continue
+		}
+
+		if maxLength != 0 && quoteLength > maxLength {
+			continue
+		}
+
+		filtered = append(filtered, quote)
+	}
+
+	return filtered
+}
+
+// Picks the words to train with, each at most once. The pool is left as is.
+func PickWords(random *rand.Rand, wordPool []string, count int) []string {
+	wordPool = append([]string(nil), wordPool...)
+
+	words := []string(nil)
+	for range min(len(wordPool), count) {
+		wordIdx := random.Intn(len(wordPool))
+		words = append(words, wordPool[wordIdx])
+
+		wordPool[wordIdx] = wordPool[len(wordPool)-1]
+		wordPool = wordPool[:len(wordPool)-1]
+	}
+
+	return words
+}
+
+// Picks the quotes to train with, in a random order. The pool is left as is.
+func PickQuotes(random *rand.Rand, quotePool []string, count int) []string {
+	quotePool = append([]string(nil), quotePool...)
+	random.Shuffle(len(quotePool), func(i, j int) {
+		quotePool[i], quotePool[j] = quotePool[j], quotePool[i]
+	})
+
+	return quotePool[:min(len(quotePool), count)]
+}
@@ -0,0 +1,78 @@
+package commons
+
+import (
+	"github.com/gopxl/beep"
+)
+
+// Streams a buffer that can be paused and seeked while it is playing. It never
+// drains: after the end of the buffer (or while paused) it streams silence, so
+// it can still be rewinded.
+//
+// Lock the speaker before calling its methods while it is playing.
+type SeekableStream struct {
+	streamer beep.StreamSeeker
+	Paused   bool
+
+	// Pauses the stream when reaching this position (if not negative).
+	pauseAt int
+}
+
+func NewSeekableStream(buffer *beep.Buffer) *SeekableStream {
+	return &SeekableStream{
+		streamer: buffer.Streamer(0, buffer.Len()),
+		Paused:   true,
+		pauseAt:  -1,
+	}
+}
+
+func (s *SeekableStream) Stream(samples [][2]float64) (n int, ok bool) {
+	if !s.Paused {
+		toStream := samples
+		if s.pauseAt >= 0 {
+			toStream = toStream[:min(len(toStream), max(0, s.pauseAt-s.streamer.Position()))]
+		}
+
+		n, _ = s.streamer.Stream(toStream)
+
+		if s.pauseAt >= 0 && s.streamer.Position() >= s.pauseAt {
+			s.Paused = true
+			s.pauseAt = -1
+		}
+	}
+
+	clear(samples[n:])
+	return len(samples), true
+}
+
+func (s *SeekableStream) Err() error {
+	return s.streamer.Err()
+}
+
+func (s *SeekableStream) Len() int {
+	return s.streamer.Len()
+}
+
+func (s *SeekableStream) Position() int {
+	return s.streamer.Position()
+}
+
+func (s *SeekableStream) Ended() bool {
+	return s.streamer.Position() >= s.streamer.Len()
+}
+
+func (s *SeekableStream) Seek(position int) error {
+	s.pauseAt = -1
+	return s.streamer.Seek(max(0, min(position, s.streamer.Len())))
+}
+
+// Plays the part of the buffer between the two positions, then pauses.
+func (s *SeekableStream) PlayBetween(from int, to int) error {
+	if err := s.Seek(from); err != nil {
+		return err
+	}
+
+	s.pauseAt = to
+	s.Paused = false
+
+	return nil
+}
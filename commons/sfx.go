@@ -2,13 +2,16 @@ package commons
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/gopxl/beep"
 	"github.com/gopxl/beep/effects"
 	"github.com/gopxl/beep/generators"
 	"github.com/gopxl/beep/speaker"
+	"github.com/gopxl/beep/wav"
 )
 
 type soundType int
@@ -41,15 +44,22 @@ func init() {
 
 var currentDitDuration = time.Duration(0)
 
+// Pitch in hertz of the beeps.
+const DefaultPitch = 1_000.0
+
 func initSoundAssets(ditDuration time.Duration) {
 	if max(ditDuration, currentDitDuration)-min(ditDuration, currentDitDuration) < time.Millisecond*5 {
 		return
 	}
 
 	currentDitDuration = ditDuration
+	SoundAssets = newSoundAssets(ditDuration, DefaultPitch)
+}
+
+func newSoundAssets(ditDuration time.Duration, pitch float64) map[soundType]*beep.Buffer {
 	shortBeepSamples := AudioFormat.SampleRate.N(ditDuration)
 
-	audioTone, err := generators.SineTone(AudioFormat.SampleRate, 1_000)
+	audioTone, err := generators.SineTone(AudioFormat.SampleRate, pitch)
 	tamedAudioTone := &effects.Volume{
 		Streamer: audioTone,
 		Base:     2,
@@ -69,7 +79,7 @@ func initSoundAssets(ditDuration time.Duration) {
 	sDelayBuffer := beep.NewBuffer(AudioFormat)
 	sDelayBuffer.Append(generators.Silence(shortBeepSamples))
 
-	SoundAssets = map[soundType]*beep.Buffer{
+	return map[soundType]*beep.Buffer{
 		ShortBeep:  sBeepBuffer,
 		LongBeep:   lBeepBuffer,
 		ShortDelay: sDelayBuffer,
@@ -77,10 +87,81 @@ func initSoundAssets(ditDuration time.Duration) {
 }
 
 func MorseCharSound(str string, speed float64) beep.Streamer {
-	buffer := beep.NewBuffer(AudioFormat)
-
 	initSoundAssets(time.Duration(float64(DefaultDitDuration) / speed))
-	resampledSounds := SoundAssets
+	return morseSound(str, SoundAssets)
+}
+
+// Like MorseCharSound, but with the beeps at another pitch (in hertz).
+func MorseCharSoundAt(str string, speed float64, pitch float64) beep.Streamer {
+	if pitch == DefaultPitch {
+		return MorseCharSound(str, speed)
+	}
+
+	return morseSound(str, newSoundAssets(time.Duration(float64(DefaultDitDuration)/speed), pitch))
+}
+
+// Plays the morse code (in the notation of MorseCharSound), returning after it
+// has been played.
+func PlayMorseCode(morseCode string, speed float64, pitch float64) {
+	done := make(chan struct{})
+	speaker.Play(beep.Seq(MorseCharSoundAt(morseCode, speed, pitch), beep.Callback(func() {
+		close(done)
+	})))
+
+	<-done
+}
+
+// Renders the morse code (in the notation of MorseCharSound) as a WAV file, for
+// playing it somewhere else. Unlike MorseCharSound, it is safe to call from
+// several goroutines.
+func MorseCodeWAV(morseCode string, speed float64) ([]byte, error) {
+	sound := morseSound(morseCode, newSoundAssets(time.Duration(float64(DefaultDitDuration)/speed), DefaultPitch))
+
+	file := &memoryFile{}
+	if err := wav.Encode(file, sound, AudioFormat); err != nil {
+		return nil, fmt.Errorf("Error rendering the sound: %v", err)
+	}
+
+	return file.data, nil
+}
+
+// An io.WriteSeeker in memory, as wav.Encode goes back to write the header.
+type memoryFile struct {
+	data     []byte
+	position int
+}
+
+func (file *memoryFile) Write(p []byte) (int, error) {
+	if end := file.position + len(p); end > len(file.data) {
+		file.data = append(file.data, make([]byte, end-len(file.data))...)
+	}
+
+	n := copy(file.data[file.position:], p)
+	file.position += n
+
+	return n, nil
+}
+
+func (file *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	position := int(offset)
+
+	switch whence {
+	case io.SeekCurrent:
+		position += file.position
+	case io.SeekEnd:
+		position += len(file.data)
+	}
+
+	if position < 0 {
+		return 0, fmt.Errorf("Seeking before the start of the file.")
+	}
+
+	file.position = position
+	return int64(position), nil
+}
+
+func morseSound(str string, resampledSounds map[soundType]*beep.Buffer) beep.Streamer {
+	buffer := beep.NewBuffer(AudioFormat)
 
 	for _, r := range str {
 		loopCount := 1
@@ -118,3 +199,38 @@ func MorseCharSound(str string, speed float64) beep.Streamer {
 
 	return buffer.Streamer(0, buffer.Len())
 }
+
+// A continuous tone that is silent while it is off, for hearing the key being
+// pressed.
+type Sidetone struct {
+	on   atomic.Bool
+	tone beep.Streamer
+}
+
+func NewSidetone() (*Sidetone, error) {
+	tone, err := generators.SineTone(AudioFormat.SampleRate, DefaultPitch)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating the sidetone: %v", err)
+	}
+
+	return &Sidetone{
+		tone: &effects.Volume{Streamer: tone, Base: 2, Volume: -1},
+	}, nil
+}
+
+func (sidetone *Sidetone) SetOn(on bool) {
+	sidetone.on.Store(on)
+}
+
+func (sidetone *Sidetone) Stream(samples [][2]float64) (int, bool) {
+	n, ok := sidetone.tone.Stream(samples)
+	if !sidetone.on.Load() {
+		clear(samples[:n])
+	}
+
+	return n, ok
+}
+
+func (sidetone *Sidetone) Err() error {
+	return sidetone.tone.Err()
+}
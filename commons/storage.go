@@ -0,0 +1,81 @@
+package commons
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appDirName = "dihdah"
+
+// Returns the directory where dihdah keeps the files of the current user,
+// creating it if needed. This is $XDG_CONFIG_HOME/dihdah on linux for the
+// default user.
+func StorageDir() (string, error) {
+	dir, err := UserDir(currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("Error creating %v: %v", dir, err)
+	}
+
+	return dir, nil
+}
+
+func appDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Error finding the config directory: %v", err)
+	}
+
+	return filepath.Join(configDir, appDirName), nil
+}
+
+// Reads a json file from the storage directory into value. A missing file
+// leaves value untouched.
+func LoadStoredJSON(fileName string, value any) error {
+	dir, err := StorageDir()
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(dir, fileName)
+	contents, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error reading %v: %v", filePath, err)
+	}
+
+	if err := json.Unmarshal(contents, value); err != nil {
+		return fmt.Errorf("Error parsing %v: %v", filePath, err)
+	}
+
+	return nil
+}
+
+func SaveStoredJSON(fileName string, value any) error {
+	dir, err := StorageDir()
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding %v: %v", fileName, err)
+	}
+
+	filePath := filepath.Join(dir, fileName)
+	if err := os.WriteFile(filePath, contents, 0o644); err != nil {
+		return fmt.Errorf("Error writing %v: %v", filePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package commons
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The custom theme of the config file, selected with --theme custom.
+const CustomThemeName = "custom"
+
+// The colors of a theme, as hex like "#ffcc00" or ANSI numbers like "2". An
+// empty color is the color of the terminal.
+type ThemeColors struct {
+	Correct string
+	Wrong   string
+
+	// The letter pool of the chart, the path of the tree and the progress bar.
+	Accent string
+
+	// Of the selected rows and characters, which are shown reversed without
+	// them.
+	Selected           string
+	SelectedBackground string
+
+	Faint   string
+	Border  string
+	Flash   string
+	HelpKey string
+	Help    string
+}
+
+type Theme struct {
+	Name   string
+	Colors ThemeColors
+
+	// Bold instead of faint text, where faint text is hard to read.
+	NoFaint bool
+}
+
+var ThemePresets = map[string]Theme{
+	"dark": {
+		Name: "dark",
+		Colors: ThemeColors{
+			Correct:  "2",
+			Wrong:    "1",
+			Accent:   "#5a56e0",
+			Selected: "212",
+			Faint:    "240",
+			Border:   "240",
+			Flash:    "#ffcc00",
+			HelpKey:  "#626262",
+			Help:     "#4a4a4a",
+		},
+	},
+	"light": {
+		Name: "light",
+		Colors: ThemeColors{
+			Correct:            "#007a00",
+			Wrong:              "#c00000",
+			Accent:             "#3050c0",
+			Selected:           "#ffffff",
+			SelectedBackground: "#3050c0",
+			Faint:              "#8a8a8a",
+			Border:             "#8a8a8a",
+			Flash:              "#c07000",
+			HelpKey:            "#555555",
+			Help:               "#777777",
+		},
+	},
+	// Only the colors of the terminal, readable on dark and light backgrounds.
+	"high-contrast": {
+		Name: "high-contrast",
+		Colors: ThemeColors{
+			Correct: "2",
+			Wrong:   "1",
+		},
+		NoFaint: true,
+	},
+}
+
+const defaultThemeName = "dark"
+
+// The theme in use, selected with --theme.
+var CurrentTheme = ThemePresets[defaultThemeName]
+
+// Like "dark, high-contrast, light", with the custom theme.
+func ThemeNames() []string {
+	return append(slices.Sorted(maps.Keys(ThemePresets)), CustomThemeName)
+}
+
+// The colors by their names in the config file, like "selected-background".
+func (colors *ThemeColors) Fields() map[string]*string {
+	return map[string]*string{
+		"correct":             &colors.Correct,
+		"wrong":               &colors.Wrong,
+		"accent":              &colors.Accent,
+		"selected":            &colors.Selected,
+		"selected-background": &colors.SelectedBackground,
+		"faint":               &colors.Faint,
+		"border":              &colors.Border,
+		"flash":               &colors.Flash,
+		"help-key":            &colors.HelpKey,
+		"help":                &colors.Help,
+	}
+}
+
+// The color names of the custom theme, with "base" for the preset that the
+// colors not set are taken from.
+func ThemeColorNames() []string {
+	return append(slices.Sorted(maps.Keys((&ThemeColors{}).Fields())), "base")
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+// Checks a color of the custom theme, like {"base": "light", "wrong": "#ff0000"}.
+func ValidateThemeColor(name string, value string) error {
+	if name == "base" {
+		if _, exists := ThemePresets[value]; !exists {
+			return fmt.Errorf("Error: There is no theme named %q to base on.", value)
+		}
+
+		return nil
+	}
+
+	if _, exists := (&ThemeColors{}).Fields()[name]; !exists {
+		return fmt.Errorf("Error: There is no theme color named %q. The colors are: %v", name, strings.Join(ThemeColorNames(), ", "))
+	}
+
+	if len(value) != 0 && !colorPattern.MatchString(value) {
+		return fmt.Errorf("Error: Invalid color %q, expected a hex color like \"#ffcc00\" or an ANSI number.", value)
+	}
+
+	return nil
+}
+
+// A preset, or the custom theme from its colors in the config file.
+func LoadTheme(name string, customColors map[string]string) (Theme, error) {
+	if len(name) == 0 {
+		name = defaultThemeName
+	}
+
+	if name != CustomThemeName {
+		theme, exists := ThemePresets[name]
+		if !exists {
+			return Theme{}, fmt.Errorf("Error: There is no theme named %q. The themes are: %v", name, strings.Join(ThemeNames(), ", "))
+		}
+
+		return theme, nil
+	}
+
+	base := customColors["base"]
+	if len(base) == 0 {
+		base = defaultThemeName
+	}
+
+	if err := ValidateThemeColor("base", base); err != nil {
+		return Theme{}, err
+	}
+
+	theme := ThemePresets[base]
+	theme.Name = CustomThemeName
+
+	fields := theme.Colors.Fields()
+	for colorName, value := range customColors {
+		if colorName == "base" {
+			continue
+		}
+
+		if err := ValidateThemeColor(colorName, value); err != nil {
+			return Theme{}, err
+		}
+
+		*fields[colorName] = value
+	}
+
+	return theme, nil
+}
+
+// Sets the theme in use. Without colors if NO_COLOR is set, as per
+// https://no-color.org.
+func SetTheme(theme Theme) {
+	if len(os.Getenv("NO_COLOR")) != 0 {
+		theme.Colors = ThemeColors{}
+	}
+
+	CurrentTheme = theme
+	keyHelp.Styles = theme.helpStyles()
+}
+
+func (theme Theme) Colorless() bool {
+	return theme.Colors == ThemeColors{}
+}
+
+// Sets the foreground to the color, if any.
+func withForeground(style lipgloss.Style, color string) lipgloss.Style {
+	if len(color) == 0 {
+		return style
+	}
+
+	return style.Foreground(lipgloss.Color(color))
+}
+
+func (theme Theme) Correct() lipgloss.Style {
+	return withForeground(lipgloss.NewStyle().Bold(true), theme.Colors.Correct)
+}
+
+func (theme Theme) Wrong() lipgloss.Style {
+	style := withForeground(lipgloss.NewStyle().Bold(true), theme.Colors.Wrong)
+	if len(theme.Colors.Wrong) == 0 {
+		style = style.Reverse(true)
+	}
+
+	return style
+}
+
+func (theme Theme) Accent() lipgloss.Style {
+	return withForeground(lipgloss.NewStyle().Bold(true).Underline(true), theme.Colors.Accent)
+}
+
+func (theme Theme) Selected() lipgloss.Style {
+	style := withForeground(lipgloss.NewStyle().Bold(true), theme.Colors.Selected)
+
+	if len(theme.Colors.SelectedBackground) != 0 {
+		return style.Background(lipgloss.Color(theme.Colors.SelectedBackground))
+	}
+
+	if len(theme.Colors.Selected) == 0 {
+		style = style.Reverse(true)
+	}
+
+	return style
+}
+
+func (theme Theme) Faint() lipgloss.Style {
+	if theme.NoFaint {
+		return lipgloss.NewStyle()
+	}
+
+	return withForeground(lipgloss.NewStyle().Faint(len(theme.Colors.Faint) == 0), theme.Colors.Faint)
+}
+
+func (theme Theme) Border(border lipgloss.Border, sides ...bool) lipgloss.Style {
+	style := lipgloss.NewStyle().Border(border, sides...)
+	if len(theme.Colors.Border) != 0 {
+		style = style.BorderForeground(lipgloss.Color(theme.Colors.Border))
+	}
+
+	return style
+}
+
+func (theme Theme) TableStyles() table.Styles {
+	styles := table.DefaultStyles()
+	styles.Selected = theme.Selected()
+
+	return styles
+}
+
+func (theme Theme) helpStyles() help.Styles {
+	keyStyle := withForeground(lipgloss.NewStyle().Bold(theme.NoFaint), theme.Colors.HelpKey)
+	descStyle := withForeground(lipgloss.NewStyle(), theme.Colors.Help)
+	sepStyle := descStyle
+
+	return help.Styles{
+		ShortKey:       keyStyle,
+		ShortDesc:      descStyle,
+		ShortSeparator: sepStyle,
+		Ellipsis:       sepStyle,
+		FullKey:        keyStyle,
+		FullDesc:       descStyle,
+		FullSeparator:  sepStyle,
+	}
+}
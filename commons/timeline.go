@@ -0,0 +1,164 @@
+package commons
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// One part of the morse code signal, either a beep or a silence.
+type SignalElement struct {
+	On       bool
+	Duration time.Duration
+}
+
+// Returns the on/off elements of the morse code string, timed exactly like the
+// sound of MorseCharSound.
+func MorseTimeline(str string, speed float64) []SignalElement {
+	initSoundAssets(time.Duration(float64(DefaultDitDuration) / speed))
+
+	ditDuration := AudioFormat.SampleRate.D(SoundAssets[ShortBeep].Len())
+	dahDuration := AudioFormat.SampleRate.D(SoundAssets[LongBeep].Len())
+	delayDuration := AudioFormat.SampleRate.D(SoundAssets[ShortDelay].Len())
+
+	timeline := []SignalElement(nil)
+	appendElement := func(on bool, duration time.Duration) {
+		if len(timeline) != 0 && timeline[len(timeline)-1].On == on {
+			timeline[len(timeline)-1].Duration += duration
+			return
+		}
+
+		timeline = append(timeline, SignalElement{On: on, Duration: duration})
+	}
+
+	for _, r := range str {
+		switch r {
+		case '.':
+			appendElement(true, ditDuration)
+			appendElement(false, delayDuration)
+		case ',':
+			appendElement(true, dahDuration)
+			appendElement(false, delayDuration)
+		case ' ', '-':
+			appendElement(false, delayDuration*3)
+		case MorseSpaceIndicator:
+			appendElement(false, delayDuration*4)
+		}
+	}
+
+	return timeline
+}
+
+// Duration of a dit (and of the gap between elements) at the speed.
+func DitDuration(speed float64) time.Duration {
+	initSoundAssets(time.Duration(float64(DefaultDitDuration) / speed))
+	return AudioFormat.SampleRate.D(SoundAssets[ShortBeep].Len())
+}
+
+func TimelineDuration(timeline []SignalElement) time.Duration {
+	duration := time.Duration(0)
+	for _, element := range timeline {
+		duration += element.Duration
+	}
+
+	return duration
+}
+
+// Whether the signal is on at the given time of the timeline.
+func SignalOnAt(timeline []SignalElement, at time.Duration) bool {
+	if at < 0 {
+		return false
+	}
+
+	for _, element := range timeline {
+		if at < element.Duration {
+			return element.On
+		}
+
+		at -= element.Duration
+	}
+
+	return false
+}
+
+type OutputMode int
+
+const (
+	AudioOutput OutputMode = iota
+	VisualOutput
+	AudioVisualOutput
+)
+
+var outputModeNames = []string{"audio", "visual", "both"}
+
+func (mode OutputMode) String() string {
+	return outputModeNames[mode]
+}
+
+func ParseOutputMode(str string) (OutputMode, error) {
+	for i, name := range outputModeNames {
+		if strings.EqualFold(str, name) {
+			return OutputMode(i), nil
+		}
+	}
+
+	return AudioOutput, fmt.Errorf("Invalid output %q (expected one of: %v)", str, strings.Join(outputModeNames, ", "))
+}
+
+type VisualizerMode int
+
+const (
+	NoVisualizer VisualizerMode = iota
+
+	// Lights up the dits and dahs while they play.
+	LiveVisualizer
+
+	// Scrolls the on/off signal while it plays.
+	ScrollVisualizer
+
+	// Shows the dits and dahs only after answering.
+	RevealVisualizer
+)
+
+var visualizerModeNames = []string{"none", "live", "scroll", "reveal"}
+
+func (mode VisualizerMode) String() string {
+	return visualizerModeNames[mode]
+}
+
+func ParseVisualizerMode(str string) (VisualizerMode, error) {
+	for i, name := range visualizerModeNames {
+		if strings.EqualFold(str, name) {
+			return VisualizerMode(i), nil
+		}
+	}
+
+	return NoVisualizer, fmt.Errorf("Invalid visualizer %q (expected one of: %v)", str, strings.Join(visualizerModeNames, ", "))
+}
+
+// How the drills play the morse code: as sound, as a flashing block, or both,
+// and whether the dits and dahs are shown.
+type Playback struct {
+	Output     OutputMode
+	Visualizer VisualizerMode
+
+	// Height of the flashing block in lines (it is twice as wide).
+	FlashSize int
+
+	// Empty for the flash color of the theme.
+	FlashColor string
+}
+
+var DefaultPlayback = Playback{
+	Output:     AudioOutput,
+	Visualizer: NoVisualizer,
+	FlashSize:  3,
+}
+
+func (p Playback) Audio() bool {
+	return p.Output != VisualOutput
+}
+
+func (p Playback) Visual() bool {
+	return p.Output != AudioOutput
+}
@@ -0,0 +1,187 @@
+package commons
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// The user with the files directly in the storage directory, as before there
+// were users.
+const DefaultUser = "default"
+
+const usersDirName = "users"
+
+var userNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+var currentUser = DefaultUser
+
+func CurrentUser() string {
+	return currentUser
+}
+
+// Selects the user that StorageDir() is for, or the default user if empty.
+func SetUser(name string) error {
+	if len(name) == 0 {
+		name = DefaultUser
+	}
+
+	users, err := Users()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(users, name) {
+		return fmt.Errorf("Error: There is no user named %q. Add it with 'dihdah users add %v'.", name, name)
+	}
+
+	currentUser = name
+	return nil
+}
+
+// Directory of the files of the user, which may not exist yet.
+func UserDir(name string) (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+
+	if name == DefaultUser {
+		return dir, nil
+	}
+
+	return filepath.Join(dir, usersDirName, name), nil
+}
+
+// The default user and the added ones, sorted by name.
+func Users() ([]string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return nil, err
+	}
+
+	usersDir := filepath.Join(dir, usersDirName)
+	entries, err := os.ReadDir(usersDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Error reading %v: %v", usersDir, err)
+	}
+
+	users := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && userNamePattern.MatchString(entry.Name()) {
+			users = append(users, entry.Name())
+		}
+	}
+
+	slices.Sort(users)
+	return append([]string{DefaultUser}, users...), nil
+}
+
+func AddUser(name string) error {
+	if !userNamePattern.MatchString(name) {
+		return fmt.Errorf("Error: %q is not a valid user name. Use letters, numbers, '-' and '_'.", name)
+	}
+
+	users, err := Users()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(users, name) {
+		return fmt.Errorf("Error: The user %q already exists.", name)
+	}
+
+	dir, err := UserDir(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("Error creating %v: %v", dir, err)
+	}
+
+	return nil
+}
+
+// Removes the user with all of their files. The default user cannot be removed.
+func RemoveUser(name string) error {
+	if name == DefaultUser {
+		return fmt.Errorf("Error: The default user cannot be removed.")
+	}
+
+	users, err := Users()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(users, name) {
+		return fmt.Errorf("Error: There is no user named %q.", name)
+	}
+
+	dir, err := UserDir(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("Error removing %v: %v", dir, err)
+	}
+
+	return nil
+}
+
+// Writes the files of the user (the settings, history and progress) as a zip
+// archive.
+func ExportUser(name string, w io.Writer) error {
+	users, err := Users()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(users, name) {
+		return fmt.Errorf("Error: There is no user named %q.", name)
+	}
+
+	dir, err := UserDir(name)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Error reading %v: %v", dir, err)
+	}
+
+	archive := zip.NewWriter(w)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("Error reading %v: %v", entry.Name(), err)
+		}
+
+		file, err := archive.Create(entry.Name())
+		if err != nil {
+			return fmt.Errorf("Error archiving %v: %v", entry.Name(), err)
+		}
+
+		if _, err := file.Write(contents); err != nil {
+			return fmt.Errorf("Error archiving %v: %v", entry.Name(), err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("Error archiving the files: %v", err)
+	}
+
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 )
 
 type Checkbox struct {
@@ -92,7 +94,7 @@ func (m *Checkbox) update(msg tea.Msg) (*Checkbox, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == " " {
+		if key.Matches(msg, commons.Keys.Toggle) {
 			m.Toggle()
 			m.reacted = true
 		}
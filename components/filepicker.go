@@ -9,9 +9,11 @@ import (
 
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
 )
 
 type FilePicker struct {
@@ -56,9 +58,28 @@ func newInternalFilePicker(allowedTypes []string) filepicker.Model {
 		allowedTypes = []string{".txt"}
 	}
 	filePicker.AllowedTypes = allowedTypes
+	filePicker.Styles = filePickerStyles(commons.CurrentTheme)
 	return filePicker
 }
 
+func filePickerStyles(theme commons.Theme) filepicker.Styles {
+	styles := filepicker.DefaultStyles()
+	faint := theme.Faint()
+
+	styles.Cursor = theme.Selected()
+	styles.Selected = theme.Selected()
+	styles.Directory = theme.Accent().Underline(false)
+	styles.Symlink = theme.Accent().Underline(false).Italic(true)
+	styles.DisabledCursor = faint
+	styles.DisabledFile = faint
+	styles.DisabledSelected = faint
+	styles.Permission = faint
+	styles.FileSize = faint.Inherit(styles.FileSize)
+	styles.EmptyDirectory = faint.Inherit(styles.EmptyDirectory)
+
+	return styles
+}
+
 func (m *FilePicker) Focus() tea.Cmd {
 	m.focused = true
 	return m.Cursor.Focus()
@@ -114,7 +135,7 @@ func (m *FilePicker) update(msg tea.Msg) (*FilePicker, tea.Cmd) {
 		m.setReact()
 
 		if msg, isKey := msg.(tea.KeyMsg); isKey {
-			if msg.String() == "esc" {
+			if key.Matches(msg, commons.Keys.Back) {
 				m.SelectingFile = false
 				return m, tea.Batch(tea.ExitAltScreen, m.Focus())
 			}
@@ -148,8 +169,7 @@ func (m *FilePicker) update(msg tea.Msg) (*FilePicker, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter", " ":
+		if key.Matches(msg, commons.Keys.MenuSelect) {
 			cmd := m.TriggerSelection()
 			return m, cmd
 		}
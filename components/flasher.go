@@ -0,0 +1,125 @@
+package components
+
+import (
+	"cmp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+var lastFlasherId atomic.Int64
+
+// A block that flashes the morse code signal. It is either driven by the clock
+// (Play) or by the position of a playing stream (SetPosition).
+type Flasher struct {
+	Size  int
+	Color lipgloss.Color
+
+	on bool
+
+	id         int64
+	generation int
+	timeline   []commons.SignalElement
+	element    int
+	start      time.Time
+}
+
+type FlashMsg struct {
+	id         int64
+	generation int
+	element    int
+}
+
+func NewFlasher(playback commons.Playback) *Flasher {
+	return &Flasher{
+		Size:  max(1, playback.FlashSize),
+		Color: lipgloss.Color(cmp.Or(playback.FlashColor, commons.CurrentTheme.Colors.Flash)),
+		id:    lastFlasherId.Add(1),
+	}
+}
+
+// Flashes the timeline from the start.
+func (m *Flasher) Play(timeline []commons.SignalElement) tea.Cmd {
+	m.generation += 1
+	m.timeline = timeline
+	m.element = -1
+	m.start = time.Now()
+
+	return m.nextElement()
+}
+
+func (m *Flasher) Stop() {
+	m.generation += 1
+	m.timeline = nil
+	m.on = false
+}
+
+// Sets the signal state at the position of the timeline, for flashing along
+// with a stream that is played elsewhere.
+func (m *Flasher) SetPosition(timeline []commons.SignalElement, at time.Duration) {
+	m.generation += 1
+	m.timeline = nil
+	m.on = commons.SignalOnAt(timeline, at)
+}
+
+func (m *Flasher) On() bool {
+	return m.on
+}
+
+func (m *Flasher) nextElement() tea.Cmd {
+	m.element += 1
+	if m.element >= len(m.timeline) {
+		m.on = false
+		return nil
+	}
+
+	m.on = m.timeline[m.element].On
+
+	// Scheduling from the start of the timeline, so the delays of the ticks do
+	// not add up.
+	elementEnd := time.Duration(0)
+	for _, element := range m.timeline[:m.element+1] {
+		elementEnd += element.Duration
+	}
+
+	flashMsg := FlashMsg{
+		id:         m.id,
+		generation: m.generation,
+		element:    m.element,
+	}
+
+	return tea.Tick(time.Until(m.start.Add(elementEnd)), func(_ time.Time) tea.Msg {
+		return flashMsg
+	})
+}
+
+func (m *Flasher) Update(msg tea.Msg) tea.Cmd {
+	flashMsg, ok := msg.(FlashMsg)
+	if !ok || flashMsg.id != m.id || flashMsg.generation != m.generation || flashMsg.element != m.element {
+		return nil
+	}
+
+	return m.nextElement()
+}
+
+func (m *Flasher) View() string {
+	style := commons.CurrentTheme.Border(lipgloss.RoundedBorder()).
+		Width(m.Size * 2).
+		Height(m.Size)
+
+	if !m.on {
+		return style.Render("")
+	}
+
+	// Filled with blocks without colors, like with NO_COLOR.
+	if len(m.Color) == 0 || commons.CurrentTheme.Colorless() {
+		row := strings.Repeat("█", m.Size*2)
+		return style.Render(strings.TrimSuffix(strings.Repeat(row+"\n", m.Size), "\n"))
+	}
+
+	return style.Background(m.Color).Render("")
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// Renders the levels of the morse code tree under each other, with each node
+// centered above its children (dits to the left, dahs to the right). The root
+// is not rendered, and neither are the nodes without a character.
+func RenderMorseTree(root *commons.MorseTreeNode, cellWidth int, renderNode func(node *commons.MorseTreeNode) string) string {
+	maxDepth := root.Depth()
+	lines := []string{}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		slotWidth := cellWidth << (maxDepth - depth)
+		line := strings.Builder{}
+
+		for _, node := range root.Level(depth) {
+			rendered := ""
+			if node != nil && node.Char != 0 {
+				rendered = renderNode(node)
+			}
+
+			line.WriteString(lipgloss.PlaceHorizontal(slotWidth, lipgloss.Center, rendered))
+		}
+
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
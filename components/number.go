@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 )
 
 const float64_epsilon = 1.11e-16
@@ -181,10 +183,10 @@ func (m *Number) update(msg tea.Msg) (*Number, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "+":
+		switch {
+		case key.Matches(msg, commons.Keys.Increase):
 			m.Increment()
-		case "-":
+		case key.Matches(msg, commons.Keys.Decrease):
 			m.Decrement()
 		default:
 			_reacted = false
@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 )
 
 type TextInput struct {
@@ -34,7 +35,7 @@ func (m *TextInput) Update(msg tea.Msg) (Input, tea.Cmd) {
 func (m *TextInput) View() string {
 	invalidHighlight := ""
 	if m.Input.Err != nil {
-		invalidHighlight = m.InvalidHighlight
+		invalidHighlight = commons.CurrentTheme.Wrong().Render(m.InvalidHighlight)
 	}
 
 	return fmt.Sprintf("%v %v %v", invalidHighlight, m.Input.View(), invalidHighlight)
@@ -0,0 +1,227 @@
+package components
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+var lastVisualizerId atomic.Int64
+
+const visualizerWidth = 40
+const visualizerTickInterval = time.Millisecond * 10
+
+// Shows the dits and dahs of the morse code being played, either lighting them
+// up (live), scrolling the signal (scroll), or only after answering (reveal).
+// Like the Flasher, it is driven either by the clock (Play) or by the position
+// of a playing stream (SetPosition).
+type Visualizer struct {
+	Mode commons.VisualizerMode
+
+	morseCode   string
+	timeline    []commons.SignalElement
+	ditDuration time.Duration
+	position    time.Duration
+
+	revealLabel string
+	revealCode  string
+
+	id         int64
+	generation int
+	playing    bool
+	start      time.Time
+}
+
+type VisualizerMsg struct {
+	id         int64
+	generation int
+}
+
+func NewVisualizer(mode commons.VisualizerMode) *Visualizer {
+	return &Visualizer{
+		Mode: mode,
+		id:   lastVisualizerId.Add(1),
+	}
+}
+
+// Loads the morse code to be played, without playing it.
+func (m *Visualizer) Load(morseCode string, speed float64) {
+	m.generation += 1
+	m.playing = false
+
+	m.morseCode = morseCode
+	m.timeline = commons.MorseTimeline(morseCode, speed)
+	m.ditDuration = commons.DitDuration(speed)
+	m.position = 0
+}
+
+// Plays the loaded morse code from the start.
+func (m *Visualizer) Play() tea.Cmd {
+	if m.Mode != commons.LiveVisualizer && m.Mode != commons.ScrollVisualizer {
+		return nil
+	}
+
+	m.generation += 1
+	m.playing = true
+	m.start = time.Now()
+	m.position = 0
+
+	return m.tick()
+}
+
+func (m *Visualizer) SetPosition(at time.Duration) {
+	m.generation += 1
+	m.playing = false
+	m.position = at
+}
+
+// Shows the morse code in the reveal mode, after answering.
+func (m *Visualizer) Reveal(label string, morseCode string) {
+	m.revealLabel = label
+	m.revealCode = morseCode
+}
+
+func (m *Visualizer) tick() tea.Cmd {
+	visualizerMsg := VisualizerMsg{id: m.id, generation: m.generation}
+	return tea.Tick(visualizerTickInterval, func(_ time.Time) tea.Msg {
+		return visualizerMsg
+	})
+}
+
+func (m *Visualizer) Update(msg tea.Msg) tea.Cmd {
+	visualizerMsg, ok := msg.(VisualizerMsg)
+	if !ok || visualizerMsg.id != m.id || visualizerMsg.generation != m.generation || !m.playing {
+		return nil
+	}
+
+	m.position = time.Since(m.start)
+	if m.position >= commons.TimelineDuration(m.timeline) {
+		m.playing = false
+		return nil
+	}
+
+	return m.tick()
+}
+
+func (m *Visualizer) View() string {
+	switch m.Mode {
+	case commons.LiveVisualizer:
+		return m.liveView()
+	case commons.ScrollVisualizer:
+		return m.scrollView()
+	case commons.RevealVisualizer:
+		if len(m.revealCode) == 0 {
+			return ""
+		}
+
+		return lipgloss.NewStyle().Width(visualizerWidth * 3 / 2).Render(
+			m.revealLabel + "  " + morsePattern(m.revealCode),
+		)
+	}
+
+	return ""
+}
+
+// Lights up the element currently played, with the elements yet to be played
+// dimmed. Long morse codes are shown through a window around that element.
+func (m *Visualizer) liveView() string {
+	// The n-th beep of the timeline is the n-th dit/dah of the morse code.
+	currentBeep, playedBeeps := -1, 0
+	elapsed := time.Duration(0)
+
+	for _, element := range m.timeline {
+		if elapsed > m.position {
+			break
+		}
+
+		if element.On {
+			if m.position < elapsed+element.Duration {
+				currentBeep = playedBeeps
+			}
+
+			playedBeeps += 1
+		}
+
+		elapsed += element.Duration
+	}
+
+	ended := m.position >= commons.TimelineDuration(m.timeline)
+
+	type patternRune struct {
+		str  string
+		beep int
+	}
+
+	runes := []patternRune(nil)
+	beep := 0
+
+	for _, r := range m.morseCode {
+		switch r {
+		case '.', ',':
+			runes = append(runes, patternRune{string(r), beep})
+			beep += 1
+		default:
+			runes = append(runes, patternRune{morsePattern(string(r)), beep})
+		}
+	}
+
+	if ended {
+		playedBeeps, currentBeep = beep, -1
+	}
+
+	windowStart := 0
+	for i, r := range runes {
+		if r.beep >= playedBeeps-1 {
+			windowStart = max(0, i-visualizerWidth/2)
+			break
+		}
+	}
+
+	currentStyle := commons.CurrentTheme.Selected()
+	pendingStyle := commons.CurrentTheme.Faint()
+
+	builder := strings.Builder{}
+	for _, r := range runes[windowStart:min(len(runes), windowStart+visualizerWidth)] {
+		switch {
+		case r.beep == currentBeep && (r.str == "." || r.str == ","):
+			builder.WriteString(currentStyle.Render(r.str))
+		case r.beep >= playedBeeps:
+			builder.WriteString(pendingStyle.Render(r.str))
+		default:
+			builder.WriteString(r.str)
+		}
+	}
+
+	return builder.String()
+}
+
+// Scrolls the signal from the right to the left, a character per dit.
+func (m *Visualizer) scrollView() string {
+	if m.ditDuration == 0 {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	for i := range visualizerWidth {
+		at := m.position - time.Duration(visualizerWidth-1-i)*m.ditDuration
+		if commons.SignalOnAt(m.timeline, at) {
+			builder.WriteRune('█')
+		} else {
+			builder.WriteRune(' ')
+		}
+	}
+
+	return commons.CurrentTheme.Border(lipgloss.NormalBorder(), false, false, true).Render(builder.String())
+}
+
+// Spaces out the morse code for reading it.
+func morsePattern(morseCode string) string {
+	return strings.NewReplacer(
+		string(commons.MorseSpaceIndicator), " / ",
+		"-", " ",
+	).Replace(morseCode)
+}
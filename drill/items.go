@@ -0,0 +1,94 @@
+package drill
+
+import (
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// An item of a drill: the text to answer (or to encode), like a letter or a
+// word, and the morse code played for it (or expected for it).
+type Item struct {
+	Text      string
+	MorseCode string
+}
+
+// Grades an answer to an item.
+type Check func(item Item, answer string) bool
+
+// The answer is the text of the item, in any case.
+func CheckText(item Item, answer string) bool {
+	return strings.EqualFold(answer, item.Text)
+}
+
+// The answer is the morse code of the item, with '.' for the dits and ',' for
+// the dahs, like for encoding it or for walking the morse code tree to it.
+func CheckMorseCode(item Item, answer string) bool {
+	return answer == item.MorseCode
+}
+
+// The letters a to z of the text, one item each. The rest are skipped.
+func LetterItems(text string) []Item {
+	items := []Item{}
+	for _, r := range strings.ToLower(text) {
+		if r < 'a' || r > 'z' {
+			continue
+		}
+
+		items = append(items, Item{Text: string(r), MorseCode: commons.MorseCodeLookup[r]})
+	}
+
+	return items
+}
+
+func WordItems(words []string) []Item {
+	items := []Item{}
+	for _, word := range words {
+		items = append(items, Item{Text: word, MorseCode: WordMorseCode(word)})
+	}
+
+	return items
+}
+
+// Converts the word to morse code, with the dashes of the compound words
+// played as letter gaps.
+func WordMorseCode(word string) string {
+	runes := []rune(word)
+
+	firstRune := runes[0]
+	if firstRune < 'a' {
+		firstRune += 'a' - 'A'
+	}
+
+	morseCode := ""
+	if firstRune >= 'a' && firstRune <= 'z' {
+		morseCode += commons.MorseCodeLookup[firstRune]
+	}
+
+	for _, r := range runes[1:] {
+		if r == '-' {
+			morseCode += "-"
+			continue
+		}
+
+		if r < 'a' {
+			r += 'a' - 'A'
+		}
+
+		if r >= 'a' && r <= 'z' {
+			morseCode += " " + commons.MorseCodeLookup[r]
+		}
+	}
+
+	return morseCode
+}
+
+// The letters of the items, for highlighting them in the chart.
+func Pool(items []Item) string {
+	pool := strings.Builder{}
+	for _, item := range items {
+		pool.WriteString(item.Text)
+	}
+
+	return pool.String()
+}
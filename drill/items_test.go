@@ -0,0 +1,51 @@
+package drill
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLetterItems(t *testing.T) {
+	items := LetterItems("Ab 1-c!")
+
+	want := []Item{
+		{Text: "a", MorseCode: ".,"},
+		{Text: "b", MorseCode: ",..."},
+		{Text: "c", MorseCode: ",.,."},
+	}
+
+	if !slices.Equal(items, want) {
+		t.Errorf("LetterItems() = %v, want %v", items, want)
+	}
+}
+
+func TestWordMorseCode(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"sos", "... ,,, ..."},
+		{"Et", ". ,"},
+		// The hyphens are marked as letter gaps after the letter before them.
+		{"e-t", ".- ,"},
+		{"ab-cd", "., ,...- ,.,. ,.."},
+	}
+
+	for _, test := range tests {
+		if got := WordMorseCode(test.word); got != test.want {
+			t.Errorf("WordMorseCode(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	item := Item{Text: "Paris"}
+
+	if !CheckText(item, "pArIs") {
+		t.Errorf("CheckText() is false for another case, want true")
+	}
+
+	if CheckText(item, "pari") {
+		t.Errorf("CheckText() is true for a missing letter, want false")
+	}
+}
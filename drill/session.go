@@ -0,0 +1,216 @@
+package drill
+
+import (
+	"time"
+
+	"github.com/noAbbreviation/dihdah/commons"
+)
+
+// A drill session, apart from how it is shown and played: the items are
+// answered one by one, each graded with the check of the session. The TUI
+// models and the --plain mode both drive one.
+type Session struct {
+	training commons.TrainingModel
+	items    []Item
+	check    Check
+
+	answers []Answer
+	replays int
+
+	sessionStart time.Time
+	itemStart    time.Time
+	finished     bool
+}
+
+// An answered item of a session.
+type Answer struct {
+	Item     Item
+	Given    string
+	Correct  bool
+	TimedOut bool
+
+	// From the previous answer (or the start of the session) to this one.
+	Latency time.Duration
+	Replays int
+}
+
+func NewSession(items []Item, check Check) *Session {
+	drills := make([]commons.Drill, len(items))
+	for i, item := range items {
+		drills[i] = commons.Drill{Text: item.Text}
+	}
+
+	session := &Session{
+		training: commons.TrainingModel{
+			Drills:  drills,
+			Correct: make([]bool, len(items)),
+		},
+		items: items,
+		check: check,
+	}
+
+	session.Start()
+	return session
+}
+
+// Starts timing the session and its current item over, like when the first
+// item is actually shown.
+func (s *Session) Start() {
+	s.sessionStart = time.Now()
+	s.itemStart = s.sessionStart
+}
+
+// Starts timing the current item over, like when it is only shown after
+// something else is played.
+func (s *Session) Show() {
+	s.itemStart = time.Now()
+}
+
+// The item to answer, not ok when the session is done.
+func (s *Session) Next() (item Item, ok bool) {
+	if s.Done() {
+		return Item{}, false
+	}
+
+	return s.items[s.training.CurrentDrill], true
+}
+
+// The item to play again, counted in its answer.
+func (s *Session) Replay() (item Item, ok bool) {
+	item, ok = s.Next()
+	if ok {
+		s.replays += 1
+	}
+
+	return item, ok
+}
+
+// Grades the answer to the current item, and moves on to the next one.
+func (s *Session) Answer(given string) Answer {
+	item, ok := s.Next()
+	if !ok {
+		return Answer{}
+	}
+
+	return s.record(Answer{
+		Item:    item,
+		Given:   given,
+		Correct: s.check(item, given),
+	})
+}
+
+// Like Answer, but the time to answer the current item ran out, so it is wrong
+// either way.
+func (s *Session) TimeOut(given string) Answer {
+	item, ok := s.Next()
+	if !ok {
+		return Answer{}
+	}
+
+	return s.record(Answer{
+		Item:     item,
+		Given:    given,
+		TimedOut: true,
+	})
+}
+
+func (s *Session) record(answer Answer) Answer {
+	now := time.Now()
+
+	answer.Latency = now.Sub(s.itemStart)
+	answer.Replays = s.replays
+
+	s.training.Correct[s.training.CurrentDrill] = answer.Correct
+	s.training.CurrentDrill += 1
+	s.answers = append(s.answers, answer)
+
+	s.itemStart = now
+	s.replays = 0
+
+	return answer
+}
+
+// Adds items to the end of the session, like for a session going on until its
+// time is up.
+func (s *Session) Add(items ...Item) {
+	for _, item := range items {
+		s.items = append(s.items, item)
+		s.training.Drills = append(s.training.Drills, commons.Drill{Text: item.Text})
+		s.training.Correct = append(s.training.Correct, false)
+	}
+}
+
+// Ends the session before all of the items are answered. The rest are left
+// out of the results.
+func (s *Session) Finish() {
+	s.finished = true
+}
+
+func (s *Session) Done() bool {
+	return s.finished || s.training.CurrentDrill >= len(s.training.Drills)
+}
+
+// The index of the current item, and the number of items.
+func (s *Session) Progress() (current int, total int) {
+	return s.training.CurrentDrill, len(s.training.Drills)
+}
+
+func (s *Session) Items() []Item {
+	return s.items
+}
+
+// Since the start of the session.
+func (s *Session) Elapsed() time.Duration {
+	return time.Since(s.sessionStart)
+}
+
+// Since the current item was shown, that is, since the previous answer.
+func (s *Session) ItemElapsed() time.Duration {
+	return time.Since(s.itemStart)
+}
+
+// The last answer, not ok if nothing was answered yet.
+func (s *Session) LastAnswer() (answer Answer, ok bool) {
+	if len(s.answers) == 0 {
+		return Answer{}, false
+	}
+
+	return s.answers[len(s.answers)-1], true
+}
+
+// The answers so far.
+func (s *Session) Results() Results {
+	results := Results{Answers: s.answers}
+
+	for _, answer := range s.answers {
+		if answer.Correct {
+			results.Score += 1
+		}
+	}
+
+	return results
+}
+
+type Results struct {
+	Answers []Answer
+	Score   int
+}
+
+func (results Results) Total() int {
+	return len(results.Answers)
+}
+
+func (results Results) AllCorrect() bool {
+	return results.Score == len(results.Answers)
+}
+
+func (results Results) Mistakes() []Answer {
+	mistakes := []Answer{}
+	for _, answer := range results.Answers {
+		if !answer.Correct {
+			mistakes = append(mistakes, answer)
+		}
+	}
+
+	return mistakes
+}
@@ -0,0 +1,174 @@
+package drill
+
+import (
+	"testing"
+)
+
+func newTestSession() *Session {
+	return NewSession(WordItems([]string{"cq", "de", "k"}), CheckText)
+}
+
+func TestSessionAnswer(t *testing.T) {
+	session := newTestSession()
+
+	item, ok := session.Next()
+	if !ok || item.Text != "cq" {
+		t.Fatalf("Next() = %v, %v, want cq, true", item, ok)
+	}
+
+	answer := session.Answer("CQ")
+	if !answer.Correct || answer.TimedOut || answer.Given != "CQ" || answer.Item.Text != "cq" {
+		t.Errorf("Answer(CQ) = %+v, want a correct answer to cq", answer)
+	}
+
+	answer = session.Answer("da")
+	if answer.Correct || answer.Item.Text != "de" {
+		t.Errorf("Answer(da) = %+v, want a wrong answer to de", answer)
+	}
+
+	if current, total := session.Progress(); current != 2 || total != 3 {
+		t.Errorf("Progress() = %v, %v, want 2, 3", current, total)
+	}
+
+	if last, ok := session.LastAnswer(); !ok || last.Given != "da" {
+		t.Errorf("LastAnswer() = %+v, %v, want the answer da", last, ok)
+	}
+}
+
+func TestSessionTimeOut(t *testing.T) {
+	session := newTestSession()
+
+	// Even the right answer is wrong once the time ran out.
+	answer := session.TimeOut("cq")
+	if answer.Correct || !answer.TimedOut || answer.Given != "cq" {
+		t.Errorf("TimeOut(cq) = %+v, want a wrong answer that timed out", answer)
+	}
+
+	if item, _ := session.Next(); item.Text != "de" {
+		t.Errorf("Next() after TimeOut() = %v, want de", item)
+	}
+}
+
+func TestSessionReplay(t *testing.T) {
+	session := newTestSession()
+
+	session.Replay()
+	item, ok := session.Replay()
+	if !ok || item.Text != "cq" {
+		t.Errorf("Replay() = %v, %v, want cq, true", item, ok)
+	}
+
+	if answer := session.Answer("cq"); answer.Replays != 2 {
+		t.Errorf("Replays = %v, want 2", answer.Replays)
+	}
+
+	// The replays are counted for each item on its own.
+	if answer := session.Answer("de"); answer.Replays != 0 {
+		t.Errorf("Replays of the next item = %v, want 0", answer.Replays)
+	}
+}
+
+func TestSessionDone(t *testing.T) {
+	session := newTestSession()
+	for _, given := range []string{"cq", "de", "k"} {
+		session.Answer(given)
+	}
+
+	if !session.Done() {
+		t.Fatalf("Done() = false after answering every item, want true")
+	}
+
+	if item, ok := session.Next(); ok {
+		t.Errorf("Next() = %v, true when done, want false", item)
+	}
+
+	if _, ok := session.Replay(); ok {
+		t.Errorf("Replay() is ok when done, want not ok")
+	}
+
+	if answer := session.Answer("k"); answer != (Answer{}) {
+		t.Errorf("Answer() when done = %+v, want none", answer)
+	}
+
+	if total := session.Results().Total(); total != 3 {
+		t.Errorf("Results().Total() = %v, want 3", total)
+	}
+}
+
+func TestSessionAdd(t *testing.T) {
+	session := newTestSession()
+	for _, given := range []string{"cq", "de", "k"} {
+		session.Answer(given)
+	}
+
+	session.Add(WordItems([]string{"ar", "sk"})...)
+
+	if session.Done() {
+		t.Fatalf("Done() = true after adding items, want false")
+	}
+
+	if current, total := session.Progress(); current != 3 || total != 5 {
+		t.Errorf("Progress() = %v, %v, want 3, 5", current, total)
+	}
+
+	if answer := session.Answer("ar"); !answer.Correct || answer.Item.Text != "ar" {
+		t.Errorf("Answer(ar) = %+v, want a correct answer to ar", answer)
+	}
+
+	if len(session.Items()) != 5 {
+		t.Errorf("len(Items()) = %v, want 5", len(session.Items()))
+	}
+}
+
+func TestSessionFinish(t *testing.T) {
+	session := newTestSession()
+	session.Answer("cq")
+	session.Finish()
+
+	if !session.Done() {
+		t.Fatalf("Done() = false after Finish(), want true")
+	}
+
+	// The items left are not in the results.
+	if total := session.Results().Total(); total != 1 {
+		t.Errorf("Results().Total() = %v, want 1", total)
+	}
+}
+
+func TestResults(t *testing.T) {
+	session := newTestSession()
+	session.Answer("cq")
+	session.Answer("dx")
+	session.TimeOut("")
+
+	results := session.Results()
+	if results.Score != 1 || results.Total() != 3 {
+		t.Errorf("Score, Total() = %v, %v, want 1, 3", results.Score, results.Total())
+	}
+
+	if results.AllCorrect() {
+		t.Errorf("AllCorrect() = true, want false")
+	}
+
+	mistakes := results.Mistakes()
+	if len(mistakes) != 2 || mistakes[0].Item.Text != "de" || mistakes[1].Item.Text != "k" {
+		t.Errorf("Mistakes() = %+v, want the answers to de and k", mistakes)
+	}
+
+	stats := results.Stats()
+	if stats.Answered != 3 || stats.Correct != 1 || stats.TimedOut != 1 {
+		t.Errorf("Stats() = %+v, want 3 answered, 1 correct and 1 timed out", stats)
+	}
+}
+
+func TestResultsAllCorrect(t *testing.T) {
+	session := newTestSession()
+	for _, given := range []string{"cq", "de", "k"} {
+		session.Answer(given)
+	}
+
+	results := session.Results()
+	if !results.AllCorrect() || len(results.Mistakes()) != 0 {
+		t.Errorf("AllCorrect(), Mistakes() = %v, %v, want true and none", results.AllCorrect(), results.Mistakes())
+	}
+}
@@ -0,0 +1,82 @@
+package drill
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Totals of the answers of a session, and of each of its items.
+type Stats struct {
+	Answered       int
+	Correct        int
+	TimedOut       int
+	Replays        int
+	AverageLatency time.Duration
+
+	// By the text of the items, the slowest to answer first.
+	Items []ItemStats
+}
+
+type ItemStats struct {
+	Text           string
+	Answered       int
+	Correct        int
+	AverageLatency time.Duration
+}
+
+// Zero with nothing answered.
+func (stats Stats) Accuracy() float64 {
+	if stats.Answered == 0 {
+		return 0
+	}
+
+	return float64(stats.Correct) / float64(stats.Answered)
+}
+
+func (results Results) Stats() Stats {
+	stats := Stats{Items: []ItemStats{}}
+
+	totalLatency := time.Duration(0)
+	itemLatencies := map[string]time.Duration{}
+	itemIdxs := map[string]int{}
+
+	for _, answer := range results.Answers {
+		stats.Answered += 1
+		stats.Replays += answer.Replays
+		totalLatency += answer.Latency
+
+		if answer.TimedOut {
+			stats.TimedOut += 1
+		}
+
+		idx, ok := itemIdxs[answer.Item.Text]
+		if !ok {
+			idx = len(stats.Items)
+			itemIdxs[answer.Item.Text] = idx
+			stats.Items = append(stats.Items, ItemStats{Text: answer.Item.Text})
+		}
+
+		stats.Items[idx].Answered += 1
+		itemLatencies[answer.Item.Text] += answer.Latency
+
+		if answer.Correct {
+			stats.Correct += 1
+			stats.Items[idx].Correct += 1
+		}
+	}
+
+	if stats.Answered != 0 {
+		stats.AverageLatency = totalLatency / time.Duration(stats.Answered)
+	}
+
+	for i, item := range stats.Items {
+		stats.Items[i].AverageLatency = itemLatencies[item.Text] / time.Duration(item.Answered)
+	}
+
+	slices.SortStableFunc(stats.Items, func(a, b ItemStats) int {
+		return cmp.Compare(b.AverageLatency, a.AverageLatency)
+	})
+
+	return stats
+}
@@ -0,0 +1,336 @@
+package chart
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gopxl/beep"
+	"github.com/gopxl/beep/speaker"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/noAbbreviation/dihdah/components"
+)
+
+type chartMode int
+
+const (
+	alphabeticalChart chartMode = iota
+	byLengthChart
+	treeChart
+)
+
+func (mode chartMode) String() string {
+	return [...]string{"alphabetical", "by length", "tree"}[mode]
+}
+
+const chartColumns = 6
+
+// Reference chart of the morse code characters, that can play any of them.
+type chartModel struct {
+	backReference tea.Model
+
+	pool  string
+	speed float64
+
+	mode      chartMode
+	rows      [][]rune
+	rowLabels []string
+	row       int
+	col       int
+
+	tree  *commons.MorseTreeNode
+	mixer *beep.Mixer
+
+	// Set with the speaker locked, as the chart closes. The mixer then stops
+	// streaming, so the speaker drops it (a mixer alone never ends).
+	closed bool
+}
+
+// The characters of the pool are highlighted. From a drill, the messages that
+// are not key presses are passed to the drill, so it keeps running behind the
+// chart.
+func NewChartModel(pool string, speed float64, backReference tea.Model) *chartModel {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	_m := &chartModel{
+		backReference: backReference,
+		pool:          strings.ToLower(pool),
+		speed:         speed,
+		tree:          commons.MorseTree(),
+		mixer:         &beep.Mixer{},
+	}
+
+	_m.setMode(alphabeticalChart)
+	return _m
+}
+
+func (_m *chartModel) Init() tea.Cmd {
+	speaker.Play(beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if _m.closed {
+			return 0, false
+		}
+
+		return _m.mixer.Stream(samples)
+	}))
+
+	return nil
+}
+
+func (_m *chartModel) setMode(mode chartMode) {
+	selected := _m.selected()
+
+	_m.mode = mode
+	_m.rows, _m.rowLabels = nil, nil
+
+	chars := []rune{}
+	for char := range commons.MorseCodeLookup {
+		chars = append(chars, char)
+	}
+
+	// Letters before the digits
+	slices.SortFunc(chars, func(a, b rune) int {
+		aIsDigit, bIsDigit := a >= '0' && a <= '9', b >= '0' && b <= '9'
+		if aIsDigit != bIsDigit {
+			if aIsDigit {
+				return 1
+			}
+
+			return -1
+		}
+
+		return int(a - b)
+	})
+
+	switch mode {
+	case alphabeticalChart:
+		letters := slices.DeleteFunc(slices.Clone(chars), func(r rune) bool { return r >= '0' && r <= '9' })
+		digits := slices.DeleteFunc(slices.Clone(chars), func(r rune) bool { return r < '0' || r > '9' })
+
+		for chunk := range slices.Chunk(letters, chartColumns) {
+			_m.rows = append(_m.rows, chunk)
+		}
+
+		for chunk := range slices.Chunk(digits, chartColumns) {
+			_m.rows = append(_m.rows, chunk)
+		}
+
+	case byLengthChart:
+		slices.SortStableFunc(chars, func(a, b rune) int {
+			return len(commons.MorseCodeLookup[a]) - len(commons.MorseCodeLookup[b])
+		})
+
+		for len(chars) != 0 {
+			codeLen := len(commons.MorseCodeLookup[chars[0]])
+			groupLen := 0
+			for groupLen < len(chars) && len(commons.MorseCodeLookup[chars[groupLen]]) == codeLen {
+				groupLen += 1
+			}
+
+			for i, chunk := range slices.Collect(slices.Chunk(chars[:groupLen], chartColumns)) {
+				label := ""
+				if i == 0 {
+					label = fmt.Sprintf("%v:", codeLen)
+				}
+
+				_m.rows = append(_m.rows, chunk)
+				_m.rowLabels = append(_m.rowLabels, label)
+			}
+
+			chars = chars[groupLen:]
+		}
+
+	case treeChart:
+		for depth := 1; depth <= _m.tree.Depth(); depth++ {
+			row := []rune{}
+			for _, node := range _m.tree.Level(depth) {
+				if node != nil && node.Char != 0 {
+					row = append(row, node.Char)
+				}
+			}
+
+			_m.rows = append(_m.rows, row)
+		}
+	}
+
+	_m.row, _m.col = 0, 0
+	for i, row := range _m.rows {
+		if j := slices.Index(row, selected); j >= 0 {
+			_m.row, _m.col = i, j
+		}
+	}
+}
+
+func (_m *chartModel) selected() rune {
+	if _m.row >= len(_m.rows) || _m.col >= len(_m.rows[_m.row]) {
+		return 0
+	}
+
+	return _m.rows[_m.row][_m.col]
+}
+
+func (_m *chartModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if !isKey {
+		if _m.backReference == nil {
+			return _m, nil
+		}
+
+		_, cmd := _m.backReference.Update(msg)
+		return _m, cmd
+	}
+
+	switch {
+	case key.Matches(keyMsg, commons.Keys.Quit):
+		return _m, tea.Quit
+	case key.Matches(keyMsg, commons.Keys.CloseChart):
+		speaker.Lock()
+		_m.mixer.Clear()
+		_m.closed = true
+		speaker.Unlock()
+
+		if _m.backReference == nil {
+			return _m, tea.Quit
+		}
+
+		return _m.backReference, nil
+	case key.Matches(keyMsg, commons.Keys.NextView):
+		_m.setMode((_m.mode + 1) % (treeChart + 1))
+	case key.Matches(keyMsg, commons.Keys.PrevView):
+		_m.setMode((_m.mode + treeChart) % (treeChart + 1))
+	case key.Matches(keyMsg, commons.Keys.Left):
+		_m.col = max(0, _m.col-1)
+	case key.Matches(keyMsg, commons.Keys.Right):
+		_m.col = min(len(_m.rows[_m.row])-1, _m.col+1)
+	case key.Matches(keyMsg, commons.Keys.Up):
+		_m.moveRow(-1)
+	case key.Matches(keyMsg, commons.Keys.Down):
+		_m.moveRow(1)
+	case key.Matches(keyMsg, commons.Keys.Confirm, commons.Keys.Replay):
+		sound := commons.MorseCharSound(commons.MorseCodeLookup[_m.selected()], _m.speed)
+
+		speaker.Lock()
+		_m.mixer.Clear()
+		_m.mixer.Add(sound)
+		speaker.Unlock()
+	}
+
+	return _m, nil
+}
+
+func (_m *chartModel) moveRow(delta int) {
+	newRow := max(0, min(len(_m.rows)-1, _m.row+delta))
+	if newRow == _m.row {
+		return
+	}
+
+	if _m.mode != treeChart {
+		_m.row = newRow
+		_m.col = min(_m.col, len(_m.rows[newRow])-1)
+		return
+	}
+
+	// Going to the closest node horizontally in the tree
+	fraction := codeFraction(commons.MorseCodeLookup[_m.selected()])
+	closestCol, closestDistance := 0, 2.0
+
+	for col, char := range _m.rows[newRow] {
+		distance := max(fraction, codeFraction(commons.MorseCodeLookup[char])) -
+			min(fraction, codeFraction(commons.MorseCodeLookup[char]))
+
+		if distance < closestDistance {
+			closestCol, closestDistance = col, distance
+		}
+	}
+
+	_m.row, _m.col = newRow, closestCol
+}
+
+// Horizontal position (from 0 to 1) of the code in the tree.
+func codeFraction(code string) float64 {
+	slot := 0
+	for _, element := range code {
+		slot *= 2
+		if element == ',' {
+			slot += 1
+		}
+	}
+
+	return (float64(slot) + 0.5) / float64(int(1)<<len(code))
+}
+
+func (_m *chartModel) charStyle(char rune) lipgloss.Style {
+	theme := commons.CurrentTheme
+
+	style := lipgloss.NewStyle()
+	if strings.ContainsRune(_m.pool, char) {
+		style = theme.Accent()
+	}
+
+	if char == _m.selected() {
+		style = theme.Selected().Inherit(style)
+	}
+
+	return style
+}
+
+func (_m *chartModel) View() string {
+	chart := ""
+
+	switch _m.mode {
+	case treeChart:
+		chart = lipgloss.JoinVertical(
+			lipgloss.Left,
+			"(dits to the left, dahs to the right)",
+			"",
+			components.RenderMorseTree(_m.tree, 2, func(node *commons.MorseTreeNode) string {
+				return _m.charStyle(node.Char).Render(string(node.Char))
+			}),
+		)
+
+	default:
+		lines := []string{}
+		for i, row := range _m.rows {
+			cells := []string{}
+			if len(_m.rowLabels) != 0 {
+				cells = append(cells, fmt.Sprintf("%-3v", _m.rowLabels[i]))
+			}
+
+			for _, char := range row {
+				cell := fmt.Sprintf("%v %-5v", string(char), commons.MorseCodeLookup[char])
+				cells = append(cells, _m.charStyle(char).Render(cell), "  ")
+			}
+
+			lines = append(lines, strings.Join(cells, ""))
+		}
+
+		chart = strings.Join(lines, "\n")
+	}
+
+	selected := _m.selected()
+	poolText := ""
+	if len(_m.pool) != 0 {
+		poolText = fmt.Sprintf(" (%v are in the pool)", commons.CurrentTheme.Accent().Render("highlighted"))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("Morse code chart (%v)%v", _m.mode, poolText),
+		"",
+		chart,
+		"",
+		fmt.Sprintf("Selected: %v  %v", string(selected), commons.MorseCodeLookup[selected]),
+		"",
+		fmt.Sprintf("(arrows to move, %v)", commons.KeyHelp(
+			commons.WithDesc(commons.Keys.Confirm, "to play"),
+			commons.WithDesc(commons.Keys.NextView, "to switch view"),
+			commons.Keys.CloseChart,
+			commons.Keys.Quit,
+		)),
+		"",
+	)
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"math/rand"
@@ -9,6 +8,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,7 +16,9 @@ import (
 	"github.com/noAbbreviation/dihdah/assets"
 	"github.com/noAbbreviation/dihdah/cmd/decode"
 	"github.com/noAbbreviation/dihdah/cmd/encode"
+	"github.com/noAbbreviation/dihdah/commons"
 	"github.com/noAbbreviation/dihdah/components"
+	"github.com/noAbbreviation/dihdah/ui/chart"
 )
 
 const (
@@ -41,6 +43,9 @@ type dihdahModel struct {
 
 	currentScreen screenEnum
 
+	// Shown in the user screen, with the back button after them.
+	users []string
+
 	encodeFields       [5]inputField
 	decodeLetterFields [6]inputField
 	decodeWordFields   [5]inputField
@@ -67,6 +72,8 @@ const (
 
 	decodeQuoteOptScreen
 	decodeQHelp
+
+	userScreen
 )
 
 type mainScreenOpts int
@@ -74,6 +81,8 @@ type mainScreenOpts int
 const (
 	encodeSelectM mainScreenOpts = iota
 	decodeSelectM
+	chartSelectM
+	userSelectM
 	helpSelectM
 	quitSelectM
 )
@@ -303,7 +312,8 @@ func newDihdahModel() *dihdahModel {
 	}
 }
 
-func (_m dihdahModel) letterLevelUpdate() {
+// Letters of the levels up to the current letter level.
+func (_m dihdahModel) levelLetters() string {
 	levelArg := int(_m.inputs[letterLevelIE].Value().(float64))
 	lettersPerLevel := encode.NewLettersPerLevel
 
@@ -314,7 +324,11 @@ func (_m dihdahModel) letterLevelUpdate() {
 		letters += newLetters
 	}
 
-	dedupedLetters := encode.DedupCleanLetters(letters)
+	return encode.DedupCleanLetters(letters)
+}
+
+func (_m dihdahModel) letterLevelUpdate() {
+	dedupedLetters := _m.levelLetters()
 
 	iterations := max(float64(len(dedupedLetters)/2), 3)
 	_m.inputs[iterationsIE].SetValue(iterations)
@@ -365,7 +379,48 @@ func initInputs() []components.InputReactor {
 	return inputs
 }
 
+// Sets the inputs to the values last used, from the config file. The defaults
+// stay if the file cannot be read, as the commands report it instead.
+func (_m dihdahModel) loadLastUsed() {
+	userConfig, err := commons.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	for i, input := range _m.inputs {
+		value, found := userConfig.UI[inputsE(i).String()]
+		if !found || value == "" {
+			continue
+		}
+
+		input.SetValue(value)
+	}
+}
+
+// Saves the values of the inputs to the config file, when starting a drill.
+// Not saving them is not worth interrupting the drill for.
+func (_m dihdahModel) saveLastUsed() {
+	userConfig, err := commons.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	userConfig.UI = map[string]any{}
+	for i, input := range _m.inputs {
+		userConfig.UI[inputsE(i).String()] = input.Value()
+	}
+
+	commons.SaveConfig(userConfig)
+}
+
 func (_m *dihdahModel) Init() tea.Cmd {
+	return tea.Sequence(textinput.Blink, _m.resetInputs())
+}
+
+// Sets the inputs to the values last used by the current user.
+func (_m *dihdahModel) resetInputs() tea.Cmd {
+	_m.inputs = initInputs()
+
 	cmds := make([]tea.Cmd, 0, len(_m.inputs))
 	for _, input := range _m.inputs {
 		cmd := input.Init()
@@ -374,12 +429,13 @@ func (_m *dihdahModel) Init() tea.Cmd {
 
 	_m.letterLevelUpdate()
 	_m.wordLevelUpdate()
+	_m.loadLastUsed()
 
-	return tea.Sequence(textinput.Blink, tea.Batch(cmds...))
+	return tea.Batch(cmds...)
 }
 
 func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if key, isKey := msg.(tea.KeyMsg); isKey && key.Type == tea.KeyCtrlC {
+	if keyMsg, isKey := msg.(tea.KeyMsg); isKey && key.Matches(keyMsg, commons.Keys.Quit) {
 		return _m, tea.Quit
 	}
 
@@ -451,8 +507,8 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		_m.helpViewPort.SetContent(lipgloss.NewStyle().Width(_m.helpViewPort.Width).Render(*_m.helpText))
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc", "backspace", "h", "left", "H", "shift+left":
+		switch {
+		case key.Matches(msg, commons.Keys.MenuBack):
 			if _m.currentScreen == mainScreen {
 				return _m, tea.Quit
 			}
@@ -463,15 +519,13 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				switch input := _m.inputs[focusedIE].(type) {
 				case *components.TextInput:
-					switch msg.String() {
-					case "h", "H", "backspace":
+					if commons.IsTyped(msg) {
 						updateInput(&cmds, &_m.inputs[focusedIE], msg)
 						specialCase = true
 					}
 
 				case *components.Number:
-					switch msg.String() {
-					case "h", "left":
+					if key.Matches(msg, commons.Keys.Decrease) {
 						input.Decrement()
 						specialCase = true
 					}
@@ -512,6 +566,9 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				_m.currentScreen = decodeScreen
 			case decodeQuoteOptScreen:
 				_m.currentScreen = decodeScreen
+
+			case userScreen:
+				_m.currentScreen = mainScreen
 			}
 
 			if doNoOP {
@@ -528,7 +585,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_m.selected = indexes[0]
 
 			uiNavigate = true
-		case "enter", " ", "l", "right", "L", "shift+right":
+		case key.Matches(msg, commons.Keys.MenuSelect):
 			isUiScreen, _ := _m.uiMaxIndex(_m.currentScreen)
 			if !isUiScreen {
 				break
@@ -540,29 +597,23 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				switch input := _m.inputs[focusedIE].(type) {
 				case *components.Checkbox:
-					switch msg.String() {
-					case "enter", " ":
+					if key.Matches(msg, commons.Keys.Toggle) {
 						input.Toggle()
 						specialCase = true
 					}
 
 				case *components.TextInput:
-					switch msg.String() {
-					case "l", "L", " ":
+					if commons.IsTyped(msg) {
 						updateInput(&cmds, &_m.inputs[focusedIE], msg)
 						specialCase = true
 					}
 
 				case *components.FilePicker:
-					switch msg.String() {
-					case "enter", " ", "l", "right":
-						cmds = append(cmds, input.TriggerSelection())
-						specialCase = true
-					}
+					cmds = append(cmds, input.TriggerSelection())
+					specialCase = true
 
 				case *components.Number:
-					switch msg.String() {
-					case "l", "right":
+					if key.Matches(msg, commons.Keys.Increase) {
 						input.Increment()
 						specialCase = true
 					}
@@ -592,6 +643,8 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_m.currentScreen = encodeHelp
 
 				case lastIdx + startButtonOffset:
+					_m.saveLastUsed()
+
 					var letters string
 					useCustomLetters := _m.inputs[customIE].Value().(bool)
 
@@ -629,7 +682,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						})
 
 						trainingLetters = string(runes)
-						encodeModel := encode.NewLetterModel(trainingLetters, _m)
+						encodeModel := encode.NewLetterModel(trainingLetters, encode.Timing{}, encode.DefaultMistakePitch, _m)
 						return encodeModel, encodeModel.Init()
 					}
 
@@ -639,7 +692,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						trainingLetters += string(letter)
 					}
 
-					encodeModel := encode.NewLetterModel(trainingLetters, _m)
+					encodeModel := encode.NewLetterModel(trainingLetters, encode.Timing{}, encode.DefaultMistakePitch, _m)
 					return encodeModel, encodeModel.Init()
 				}
 
@@ -661,6 +714,8 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_m.currentScreen = decodeLHelp
 
 				case lastIdx + startButtonOffset:
+					_m.saveLastUsed()
+
 					var letters string
 					useCustomLetters := _m.inputs[customIE].Value().(bool)
 
@@ -699,7 +754,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						})
 
 						trainingLetters = string(runes)
-						decodeWordsM := decode.NewLetterModel(trainingLetters, dedupedLetters, speed, _m)
+						decodeWordsM := decode.NewLetterModel(trainingLetters, dedupedLetters, speed, commons.DefaultPlayback, _m)
 						return decodeWordsM, decodeWordsM.Init()
 					}
 
@@ -709,7 +764,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						trainingLetters += string(letter)
 					}
 
-					decodeWordsM := decode.NewLetterModel(trainingLetters, dedupedLetters, speed, _m)
+					decodeWordsM := decode.NewLetterModel(trainingLetters, dedupedLetters, speed, commons.DefaultPlayback, _m)
 					return decodeWordsM, decodeWordsM.Init()
 				}
 
@@ -731,6 +786,8 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_m.currentScreen = decodeWHelp
 
 				case lastIdx + startButtonOffset:
+					_m.saveLastUsed()
+
 					maxWordLen := _m.inputs[maxWordLengthIE].Value().(float64)
 					wordFile := _m.inputs[fileNameIE].Value().(string)
 
@@ -752,28 +809,16 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					maxWordLens := decode.MaxWordLenPerLevel
 
-					wordPool := []string(nil)
-					scanner := bufio.NewScanner(wordsReader)
-
-					scanner.Split(bufio.ScanWords)
-					for scanner.Scan() {
-						word := scanner.Text()
-						word = strings.Map(func(r rune) rune {
-							if r >= 'a' && r <= 'z' {
-								return r
-							}
-
-							if r >= 'A' && r <= 'Z' {
-								return r
-							}
-
-							if r == '-' {
-								return r
-							}
-
-							return -1
-						}, word)
+					allWords, err := commons.LoadWords(wordsReader)
+					if err != nil {
+						return Popup{message: []string{
+							"Error reading the word file:",
+							err.Error(),
+						}, backReference: _m}, nil
+					}
 
+					wordPool := []string(nil)
+					for _, word := range allWords {
 						if len(word) <= int(maxWordLen) ||
 							int(maxWordLen) >= maxWordLens[len(maxWordLens)-1] {
 
@@ -781,9 +826,6 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 					}
 
-					// scanner.SplitWords(...) does not return errors!
-					_ = scanner.Err()
-
 					const iterations = 5
 					words := make([]string, 0, iterations)
 
@@ -803,7 +845,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					speed := _m.inputs[speedIE].Value().(float64)
-					decodeWModel := decode.NewWordModel(words[:], uint16(maxWordLen), speed, _m)
+					decodeWModel := decode.NewWordModel(words[:], uint16(maxWordLen), speed, commons.DefaultPlayback, _m)
 
 					return decodeWModel, decodeWModel.Init()
 				}
@@ -826,12 +868,14 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_m.currentScreen = decodeQHelp
 
 				case lastIdx + startButtonOffset:
+					_m.saveLastUsed()
+
 					quoteFile := _m.inputs[fileNameIE].Value().(string)
 
 					var quotesReader io.Reader
 
 					if len(quoteFile) == 0 {
-						quotesReader = strings.NewReader(assets.Words)
+						quotesReader = strings.NewReader(assets.Quotes)
 					} else {
 						file, err := os.Open(quoteFile)
 						if err != nil {
@@ -845,18 +889,14 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						quotesReader = file
 					}
 
-					quotes := []string(nil)
-					scanner := bufio.NewScanner(quotesReader)
-
-					scanner.Split(bufio.ScanLines)
-					for scanner.Scan() {
-						quote := strings.TrimSpace(scanner.Text())
-						quotes = append(quotes, quote)
+					quotes, err := commons.LoadQuotes(quotesReader)
+					if err != nil {
+						return Popup{message: []string{
+							"Error reading the quote file:",
+							err.Error(),
+						}, backReference: _m}, nil
 					}
 
-					// scanner.SplitLines(...) does not return errors!
-					_ = scanner.Err()
-
 					if len(quotes) == 0 {
 						return Popup{message: []string{
 							"Error processing the quote file:",
@@ -867,7 +907,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					speed := _m.inputs[speedIE].Value().(float64)
 					randomQuote := quotes[rand.Intn(len(quotes))]
 
-					decodeQModel := decode.NewQuoteModel(randomQuote, speed, _m)
+					decodeQModel := decode.NewQuoteModel(randomQuote, speed, commons.DefaultPlayback, _m)
 					return decodeQModel, decodeQModel.Init()
 				}
 
@@ -880,6 +920,22 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					_m.currentScreen = encodeOptScreen
 				case decodeSelectM:
 					_m.currentScreen = decodeScreen
+				case chartSelectM:
+					speed := _m.inputs[speedIE].Value().(float64)
+
+					chartM := chart.NewChartModel(_m.levelLetters(), speed, _m)
+					return chartM, chartM.Init()
+				case userSelectM:
+					users, err := commons.Users()
+					if err != nil {
+						return Popup{message: []string{
+							"Error listing the users:",
+							err.Error(),
+						}, backReference: _m}, nil
+					}
+
+					_m.users = users
+					_m.currentScreen = userScreen
 				case helpSelectM:
 					helpText := RootCmdLong
 					viewPortInitContent(&_m.helpViewPort, &helpText)
@@ -918,6 +974,27 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case backSelectD:
 					_m.currentScreen = mainScreen
 				}
+
+			case userScreen:
+				if _m.selected < len(_m.users) {
+					if err := commons.SetUser(_m.users[_m.selected]); err != nil {
+						return Popup{message: []string{
+							"Error switching the user:",
+							err.Error(),
+						}, backReference: _m}, nil
+					}
+
+					if err := commons.LoadKeys(); err != nil {
+						return Popup{message: []string{
+							"Error loading the key bindings of the user:",
+							err.Error(),
+						}, backReference: _m}, nil
+					}
+
+					cmds = append(cmds, _m.resetInputs())
+				}
+
+				_m.currentScreen = mainScreen
 			}
 
 			if doNoOP {
@@ -934,7 +1011,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_m.selected = indexes[0]
 
 			uiNavigate = true
-		case "down", "ctrl+n", "shift+tab", "j":
+		case key.Matches(msg, commons.Keys.MenuDown):
 			isUiScreen, _ := _m.uiMaxIndex(_m.currentScreen)
 
 			if !isUiScreen {
@@ -947,8 +1024,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if insideInput {
 				switch _m.inputs[focusedIE].(type) {
 				case *components.TextInput:
-					switch msg.String() {
-					case "j":
+					if commons.IsTyped(msg) {
 						updateInput(&cmds, &_m.inputs[focusedIE], msg)
 						specialCase = true
 					}
@@ -961,7 +1037,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			_m.navigateDown()
 			uiNavigate = true
-		case "up", "ctrl+p", "tab", "k":
+		case key.Matches(msg, commons.Keys.MenuUp):
 			isUiScreen, _ := _m.uiMaxIndex(_m.currentScreen)
 
 			if !isUiScreen {
@@ -974,8 +1050,7 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if insideInput {
 				switch _m.inputs[focusedIE].(type) {
 				case *components.TextInput:
-					switch msg.String() {
-					case "k":
+					if commons.IsTyped(msg) {
 						updateInput(&cmds, &_m.inputs[focusedIE], msg)
 						specialCase = true
 					}
@@ -998,19 +1073,18 @@ func (_m *dihdahModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			switch input := _m.inputs[focusedInputE].(type) {
 			case *components.Number:
-				switch msg.String() {
-				case "+", "=", ".", ">", "right", "l":
+				switch {
+				case key.Matches(msg, commons.Keys.Increase):
 					input.Increment()
 					doDefaultUpdate = false
 
-				case "-", "_", ",", "<", "left", "h":
+				case key.Matches(msg, commons.Keys.Decrease):
 					input.Decrement()
 					doDefaultUpdate = false
 				}
 			}
 
-			switch msg.String() {
-			case "ctrl+r":
+			if key.Matches(msg, commons.Keys.Reset) {
 				_m.inputs[focusedInputE].Reset()
 				doDefaultUpdate = false
 			}
@@ -1199,6 +1273,8 @@ func (_m *dihdahModel) uiMaxIndex(currentScreen screenEnum) (isUiScreen bool, ma
 		maxIdx = int(quitSelectM)
 	case decodeScreen:
 		maxIdx = int(backSelectD)
+	case userScreen:
+		maxIdx = len(_m.users)
 
 	case encodeOptScreen:
 		fallthrough
@@ -1337,7 +1413,7 @@ func (_m *dihdahModel) View() string {
 			strings.Repeat("-", _m.helpViewPort.Width),
 			_m.helpViewPort.View(),
 			strings.Repeat("-", viewPortWidth),
-			"(help page) (up/down to navigate, backspace/esc to go back, ctrl+c to exit)",
+			fmt.Sprintf("(help page) (up/down to navigate, %v)", commons.KeyHelp(commons.Keys.MenuBack, commons.Keys.Quit)),
 			"",
 		)
 	}
@@ -1380,7 +1456,14 @@ func (_m *dihdahModel) View() string {
 			"",
 			renderedCommonOpts,
 			"",
-			"(training options) (up/down to navigate, left/right to change numbers, space to toggle, ctrl+r to reset current input)",
+			fmt.Sprintf(
+				"(training options) (%v/%v to navigate, %v/%v to change numbers, %v)",
+				commons.Keys.MenuUp.Help().Key,
+				commons.Keys.MenuDown.Help().Key,
+				commons.Keys.Decrease.Help().Key,
+				commons.Keys.Increase.Help().Key,
+				commons.KeyHelp(commons.Keys.Toggle, commons.WithDesc(commons.Keys.Reset, "to reset current input")),
+			),
 			"",
 		)
 	}
@@ -1395,6 +1478,8 @@ func (_m *dihdahModel) View() string {
 		renderedOptions = renderOpts([]string{
 			"Encode training",
 			"Decode training",
+			"Morse code chart",
+			fmt.Sprintf("Switch user (%v)", commons.CurrentUser()),
 			"Help page",
 			"Quit application",
 		}, _m.selected)
@@ -1409,6 +1494,19 @@ func (_m *dihdahModel) View() string {
 			"Back to main menu",
 		}, _m.selected)
 		screenHeader = "Dihdah: Decode training"
+
+	case userScreen:
+		options := []string{}
+		for _, user := range _m.users {
+			if user == commons.CurrentUser() {
+				user += " (current)"
+			}
+
+			options = append(options, user)
+		}
+
+		renderedOptions = renderOpts(append(options, "Back to main menu"), _m.selected)
+		screenHeader = "Dihdah: Switch user (add users with 'dihdah users add <name>')"
 	}
 
 	isUiScreen, _ := _m.uiMaxIndex(_m.currentScreen)
@@ -1419,7 +1517,12 @@ func (_m *dihdahModel) View() string {
 			"",
 			renderedOptions,
 			"",
-			"(ui screen) (up/down to navigate, enter to select, backspace/esc to go back, ctrl+c to exit)",
+			fmt.Sprintf(
+				"(ui screen) (%v/%v to navigate, %v)",
+				commons.Keys.MenuUp.Help().Key,
+				commons.Keys.MenuDown.Help().Key,
+				commons.KeyHelp(commons.Keys.MenuSelect, commons.Keys.MenuBack, commons.Keys.Quit),
+			),
 			"",
 		)
 	}
@@ -3,7 +3,9 @@ package ui
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/noAbbreviation/dihdah/commons"
 )
 
 type Popup struct {
@@ -17,7 +19,7 @@ func (p Popup) Init() tea.Cmd {
 
 func (p Popup) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if msg, isKey := msg.(tea.KeyMsg); isKey {
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, commons.Keys.Quit) {
 			return p, tea.Quit
 		}
 
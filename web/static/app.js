@@ -0,0 +1,196 @@
+"use strict";
+
+// The drills run through the JSON API of dihdah (see docs/openapi.yaml).
+const API = "/api/v1";
+
+const setupForm = document.getElementById("setup");
+const setupError = setupForm.querySelector(".error");
+
+const drillSection = document.getElementById("drill");
+const progress = document.getElementById("progress");
+const prompt = document.getElementById("prompt");
+const answerForm = document.getElementById("answer");
+const answerInput = answerForm.elements.answer;
+const answerLabel = document.getElementById("answer-label");
+const hint = document.getElementById("hint");
+const replayButton = document.getElementById("replay");
+const feedback = document.getElementById("feedback");
+const audio = document.getElementById("audio");
+
+const resultsSection = document.getElementById("results");
+
+let session = null;
+
+async function api(method, path, body) {
+  const response = await fetch(API + path, {
+    method: method,
+    headers: body ? { "Content-Type": "application/json" } : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+
+  const json = await response.json();
+  if (!response.ok) {
+    throw new Error(json.error);
+  }
+
+  return json;
+}
+
+function play(audioPath) {
+  audio.src = audioPath;
+  audio.play().catch(() => {
+    feedback.textContent = "Press Replay to hear the sound.";
+  });
+}
+
+function isEncode() {
+  return session.mode.startsWith("encode-");
+}
+
+function show(section) {
+  for (const other of [setupForm, drillSection, resultsSection]) {
+    other.hidden = other !== section;
+  }
+}
+
+setupForm.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  setupError.hidden = true;
+
+  const fields = setupForm.elements;
+  try {
+    session = await api("POST", "/sessions", {
+      mode: fields.mode.value,
+      pool: {
+        level: Number(fields.level.value) || 0,
+        letters: fields.letters.value,
+      },
+      iterations: Number(fields.iterations.value) || 0,
+      speed: Number(fields.speed.value) || 0,
+      seed: Number(fields.seed.value) || 0,
+    });
+  } catch (error) {
+    setupError.textContent = error.message;
+    setupError.hidden = false;
+    return;
+  }
+
+  feedback.textContent = "";
+  show(drillSection);
+  showItem();
+});
+
+async function showItem() {
+  if (session.done) {
+    await showResults();
+    return;
+  }
+
+  progress.textContent = `${session.current + 1} of ${session.total}`;
+  answerInput.value = "";
+  answerInput.focus();
+  replayButton.hidden = isEncode();
+
+  if (isEncode()) {
+    prompt.textContent = session.next.prompt;
+    answerLabel.textContent = "Morse code";
+    hint.textContent = "Type . for the dits and , or - for the dahs, with a space between the letters.";
+    return;
+  }
+
+  prompt.textContent = "";
+  answerLabel.textContent = session.mode === "decode-letters" ? "Letter" : "Text";
+  hint.textContent = "Enter on an empty answer plays the sound again.";
+  play(session.next.audio);
+}
+
+async function replay() {
+  if (isEncode()) {
+    return;
+  }
+
+  session = await api("POST", `/sessions/${session.id}/replay`);
+  play(session.next.audio);
+  answerInput.focus();
+}
+
+answerForm.addEventListener("submit", async (event) => {
+  event.preventDefault();
+
+  const given = answerInput.value.trim();
+  if (given === "" && !isEncode()) {
+    await replay();
+    return;
+  }
+
+  const answered = session.next.audio;
+  const response = await api("POST", `/sessions/${session.id}/answers`, { answer: given });
+  session = response.session;
+
+  showFeedback(response.answer);
+
+  // The encode drills play the code of the text just answered, to hear how
+  // it sounds.
+  if (isEncode()) {
+    play(answered);
+  }
+
+  await showItem();
+});
+
+replayButton.addEventListener("click", replay);
+
+function showFeedback(answer) {
+  feedback.className = answer.correct ? "correct" : "wrong";
+
+  if (answer.score && !answer.correct) {
+    let score = `${answer.score.correct}/${answer.score.total} characters`;
+    if (answer.score.words) {
+      score += ` and ${answer.score.words.correct}/${answer.score.words.total} words`;
+    }
+
+    feedback.textContent = `${score} right: "${answer.text}" is ${answer.morseCode}`;
+    return;
+  }
+
+  if (answer.correct) {
+    feedback.textContent = `Correct: ${answer.text} is ${answer.morseCode}`;
+  } else {
+    feedback.textContent = `Wrong: ${answer.text} is ${answer.morseCode}, not ${answer.given || "nothing"}`;
+  }
+}
+
+async function showResults() {
+  const results = await api("GET", `/sessions/${session.id}/results`);
+
+  const seconds = (results.stats.averageLatencyMs / 1000).toFixed(1);
+  document.getElementById("score").textContent =
+    `${results.score} of ${results.total} correct, ${seconds}s per answer on average. ` +
+    `Seed ${session.seed} gives the same items again.`;
+
+  const rows = resultsSection.querySelector("tbody");
+  rows.replaceChildren();
+
+  for (const answer of results.answers) {
+    const row = rows.insertRow();
+    row.className = answer.correct ? "correct" : "wrong";
+
+    row.insertCell().textContent = answer.text;
+
+    const code = row.insertCell();
+    code.className = "code";
+    code.textContent = answer.morseCode;
+
+    row.insertCell().textContent = answer.given;
+    row.insertCell().textContent = `${(answer.latencyMs / 1000).toFixed(1)}s`;
+    row.insertCell().textContent = answer.replays;
+  }
+
+  show(resultsSection);
+  document.getElementById("again").focus();
+}
+
+document.getElementById("again").addEventListener("click", () => {
+  show(setupForm);
+  setupForm.elements.mode.focus();
+});
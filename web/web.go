@@ -0,0 +1,114 @@
+package web
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/noAbbreviation/dihdah/api"
+	"github.com/noAbbreviation/dihdah/assets"
+	"github.com/noAbbreviation/dihdah/commons"
+	"github.com/spf13/cobra"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+func init() {
+	Cmd.Flags().Uint16P("port", "p", 8373, "Port to serve on.")
+	Cmd.Flags().String("host", "127.0.0.1", "Address to serve on. The default is only reachable from this computer.")
+	Cmd.Flags().Bool("api-only", false, "Serve only the JSON API, without the web page.")
+}
+
+// The web page, and the API it runs the drills with.
+func Handler(apiServer *api.Server) http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServerFS(static))
+	mux.Handle(api.Prefix+"/", apiServer.Handler())
+
+	return mux
+}
+
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves the drills as a web page and a JSON API, on this computer only by default.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		port, _ := cmd.Flags().GetUint16("port")
+		host, _ := cmd.Flags().GetString("host")
+		apiOnly, _ := cmd.Flags().GetBool("api-only")
+
+		words, err := commons.LoadWords(strings.NewReader(assets.Words))
+		if err != nil {
+			return fmt.Errorf("Error reading the default word file: %v", err)
+		}
+
+		quotes, err := commons.LoadQuotes(strings.NewReader(assets.Quotes))
+		if err != nil {
+			return fmt.Errorf("Error reading the default quotes file: %v", err)
+		}
+
+		apiServer := api.NewServer(words, quotes)
+		handler := Handler(apiServer)
+		if apiOnly {
+			handler = apiServer.Handler()
+		}
+
+		address := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("Error: cannot serve on %v: %v", address, err)
+		}
+
+		if apiOnly {
+			cmd.Printf("Serving the drill API on http://%v%v (ctrl+c to stop)\n", address, api.Prefix)
+		} else {
+			cmd.Printf("Serving the drills on http://%v (ctrl+c to stop)\n", address)
+		}
+
+		return http.Serve(listener, handler)
+	},
+	Long: `The 'serve' command runs the drills as a web page, for those who would rather not
+use a terminal. Open the address it prints in a browser on the same computer:
+
+  $ dihdah serve
+  Serving the drills on http://127.0.0.1:8373 (ctrl+c to stop)
+
+The page has the decode and encode drills of letters, words and quotes, with the same
+levels as the commands. The morse code is played in the browser, from sounds made by
+dihdah itself, so everything works without the internet.
+
+# API
+
+The page runs the drills through a JSON API under /api/v1, which other programs can
+use too, like a bot of a club or a dashboard. --api-only serves only the API:
+
+  $ dihdah serve --api-only
+  Serving the drill API on http://127.0.0.1:8373/api/v1 (ctrl+c to stop)
+
+  POST   /api/v1/sessions                           starts a session
+  GET    /api/v1/sessions/{id}                      the progress of a session
+  GET    /api/v1/sessions/{id}/next                 the item to answer
+  GET    /api/v1/sessions/{id}/next/audio           its morse code, as a WAV file
+  POST   /api/v1/sessions/{id}/replay               counts a replay of it
+  POST   /api/v1/sessions/{id}/answers              answers it, from {"answer": "..."}
+  GET    /api/v1/sessions/{id}/items/{index}/audio  the morse code of an answered item
+  GET    /api/v1/sessions/{id}/results              the answers and their stats
+  DELETE /api/v1/sessions/{id}                      drops a session
+
+A session is started from the mode, the pool, the speed and the seed, like:
+
+  {"mode": "decode-letters", "pool": {"level": 2}, "iterations": 10, "speed": 1.25, "seed": 42}
+
+The mode is one of decode-letters, decode-words, decode-quotes, encode-letters,
+encode-words or encode-quotes. The same seed gives the same items. The whole API is
+described in docs/openapi.yaml of the repository.
+
+The sessions are only kept in memory, and dropped after an hour without use. The API
+has no authentication: with --host 0.0.0.0 anyone on the network can use it.`,
+}
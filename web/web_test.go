@@ -0,0 +1,267 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/noAbbreviation/dihdah/api"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(Handler(api.NewServer([]string{"paris", "morse"}, []string{"Hello world."})))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHandlerServesTheIndex(t *testing.T) {
+	server := newTestServer(t)
+
+	response, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("GET / = %v, want 200", response.Status)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", contentType)
+	}
+
+	index, _ := staticFiles.ReadFile("static/index.html")
+	body, _ := io.ReadAll(response.Body)
+
+	if !bytes.Equal(body, index) {
+		t.Errorf("GET / is not the embedded index.html")
+	}
+}
+
+func TestHandlerServesTheStaticFiles(t *testing.T) {
+	server := newTestServer(t)
+
+	for _, path := range []string{"/app.js", "/style.css"} {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("GET %v = %v, want 200", path, response.Status)
+		}
+	}
+}
+
+func TestHandlerRoutesTheAPI(t *testing.T) {
+	server := newTestServer(t)
+
+	response, err := http.Post(
+		server.URL+api.Prefix+"/sessions",
+		"application/json",
+		strings.NewReader(`{"mode": "decode-words", "pool": {"level": 1}, "iterations": 2}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("POST %v/sessions = %v, want 201", api.Prefix, response.Status)
+	}
+
+	session := struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}{}
+
+	if err := json.NewDecoder(response.Body).Decode(&session); err != nil {
+		t.Fatalf("decoding the session: %v", err)
+	}
+
+	if len(session.ID) == 0 || session.Total != 2 {
+		t.Errorf("session = %+v, want an id and 2 items", session)
+	}
+
+	response, err = http.Get(server.URL + api.Prefix + "/sessions/" + session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("GET the session = %v, want 200", response.Status)
+	}
+}
+
+// Sends the request to the API, and decodes the response into value.
+func callAPI(t *testing.T, server *httptest.Server, method string, path string, body string, wantStatus int, value any) {
+	t.Helper()
+
+	request, _ := http.NewRequest(method, server.URL+api.Prefix+path, strings.NewReader(body))
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != wantStatus {
+		t.Fatalf("%v %v = %v, want %v", method, path, response.Status, wantStatus)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(value); err != nil {
+		t.Fatalf("decoding %v %v: %v", method, path, err)
+	}
+}
+
+type testSession struct {
+	ID      string `json:"id"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Done    bool   `json:"done"`
+	Next    *struct {
+		Audio  string `json:"audio"`
+		Prompt string `json:"prompt"`
+	} `json:"next"`
+}
+
+func TestHandlerRunsADrill(t *testing.T) {
+	server := newTestServer(t)
+
+	session := testSession{}
+	callAPI(t, server, "POST", "/sessions", `{"mode": "decode-letters", "pool": {"letters": "e"}, "iterations": 2}`, http.StatusCreated, &session)
+
+	if session.Total != 2 || session.Next == nil {
+		t.Fatalf("session = %+v, want 2 items and the next one", session)
+	}
+
+	response, err := http.Get(server.URL + session.Next.Audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sound, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+
+	if response.Header.Get("Content-Type") != "audio/wav" || !bytes.HasPrefix(sound, []byte("RIFF")) {
+		t.Errorf("GET %v = %q, %.4q, want a WAV file", session.Next.Audio, response.Header.Get("Content-Type"), sound)
+	}
+
+	answered := struct {
+		Answer struct {
+			Correct bool `json:"correct"`
+		} `json:"answer"`
+		Session testSession `json:"session"`
+	}{}
+
+	callAPI(t, server, "POST", "/sessions/"+session.ID+"/answers", `{"answer": "E"}`, http.StatusOK, &answered)
+	if !answered.Answer.Correct || answered.Session.Current != 1 {
+		t.Errorf("answering e = %+v, want correct and at the second item", answered)
+	}
+
+	answered.Session = testSession{}
+	callAPI(t, server, "POST", "/sessions/"+session.ID+"/answers", `{"answer": "t"}`, http.StatusOK, &answered)
+	if answered.Answer.Correct || !answered.Session.Done || answered.Session.Next != nil {
+		t.Errorf("answering t = %+v, want wrong and the session done", answered)
+	}
+
+	results := struct {
+		Score   int   `json:"score"`
+		Total   int   `json:"total"`
+		Answers []any `json:"answers"`
+	}{}
+
+	callAPI(t, server, "GET", "/sessions/"+session.ID+"/results", "", http.StatusOK, &results)
+	if results.Score != 1 || results.Total != 2 || len(results.Answers) != 2 {
+		t.Errorf("results = %+v, want 1 of 2 answers correct", results)
+	}
+
+	apiError := struct {
+		Error string `json:"error"`
+	}{}
+
+	callAPI(t, server, "POST", "/sessions/"+session.ID+"/answers", `{"answer": "e"}`, http.StatusConflict, &apiError)
+	if len(apiError.Error) == 0 {
+		t.Errorf("answering a done session has no error message")
+	}
+}
+
+func TestHandlerEncodeShowsThePrompt(t *testing.T) {
+	server := newTestServer(t)
+
+	session := testSession{}
+	callAPI(t, server, "POST", "/sessions", `{"mode": "encode-words", "iterations": 1}`, http.StatusCreated, &session)
+
+	if session.Next == nil || (session.Next.Prompt != "paris" && session.Next.Prompt != "morse") {
+		t.Fatalf("session = %+v, want one of the words to encode", session)
+	}
+
+	answered := struct {
+		Answer struct {
+			Correct bool `json:"correct"`
+		} `json:"answer"`
+	}{}
+
+	// With hyphens for the dahs.
+	code := map[string]string{"paris": ".--. .- .-. .. ...", "morse": "-- --- .-. ... ."}[session.Next.Prompt]
+
+	callAPI(t, server, "POST", "/sessions/"+session.ID+"/answers", `{"answer": "`+code+`"}`, http.StatusOK, &answered)
+	if !answered.Answer.Correct {
+		t.Errorf("answering %q to %v is wrong, want correct", code, session.Next.Prompt)
+	}
+}
+
+func TestHandlerBadRequests(t *testing.T) {
+	server := newTestServer(t)
+	apiError := struct {
+		Error string `json:"error"`
+	}{}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown mode", "POST", "/sessions", `{"mode": "decode-everything"}`, http.StatusBadRequest},
+		{"unknown field", "POST", "/sessions", `{"mode": "decode-letters", "level": 1}`, http.StatusBadRequest},
+		{"speed out of range", "POST", "/sessions", `{"mode": "decode-letters", "pool": {"level": 1}, "speed": 9}`, http.StatusBadRequest},
+		{"unknown session", "GET", "/sessions/nothing", "", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			callAPI(t, server, test.method, test.path, test.body, test.status, &apiError)
+			if !strings.HasPrefix(apiError.Error, "Error") {
+				t.Errorf("error = %q, want an error message", apiError.Error)
+			}
+		})
+	}
+}
+
+func TestHandlerUnknownAPIPathsAreJSON(t *testing.T) {
+	server := newTestServer(t)
+
+	// Not left to the file server, which would answer with text.
+	response, err := http.Get(server.URL + api.Prefix + "/nothing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("GET %v/nothing = %v, want 404", api.Prefix, response.Status)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+}